@@ -15,40 +15,60 @@ import (
 	"github.com/alecthomas/kong"
 	"github.com/blampe/rreading-glasses/cmd"
 	"github.com/blampe/rreading-glasses/internal"
-	"github.com/go-chi/chi/v5/middleware"
 )
 
 // cli contains our command-line flags.
 type cli struct {
 	Serve server `cmd:"" help:"Run an HTTP server."`
 
-	Bust cmd.Bust `cmd:"" help:"Bust cache entries."`
+	Bust   cmd.Bust   `cmd:"" help:"Bust cache entries."`
+	List   cmd.List   `cmd:"" help:"List cached author, work or series IDs."`
+	Verify cmd.Verify `cmd:"" help:"Check a cached author for problems clients can't handle, without modifying it."`
 }
 
 type server struct {
 	cmd.PGConfig
 	cmd.LogConfig
 	cmd.CloudflareConfig
-
-	Port       int    `default:"8788" env:"PORT" help:"Port to serve traffic on."`
-	RPM        int    `default:"0" env:"RPM" help:"Maximum upstream requests per minute."`
-	Cookie     string `xor:"cookie" env:"COOKIE" help:"Cookie to use for upstream HTTP requests."`
-	CookieFile []byte `type:"filecontent" xor:"cookie" env:"COOKIE_FILE" help:"File with the Cookie to use for upstream HTTP requests."`
-	Proxy      string `default:"" env:"PROXY" help:"HTTP proxy URL to use for upstream requests."`
-	Upstream   string `required:"" env:"UPSTREAM" help:"Upstream host (e.g. www.example.com)."`
+	cmd.ResourceConfig
+	cmd.ControllerConfig
+	cmd.GetterConfig
+	cmd.UpstreamConfig
+	cmd.CacheControlConfig
+	cmd.TracingConfig
+
+	Config kong.ConfigFlag `env:"CONFIG" help:"JSON file to load flags from. Re-read on SIGHUP or POST /admin/reload."`
+
+	Port         int           `default:"8788" env:"PORT" help:"Port to serve traffic on."`
+	BasePath     string        `default:"" env:"BASE_PATH" help:"Path prefix to serve the API under (e.g. /metadata) when behind a reverse proxy."`
+	BulkTimeout  time.Duration `default:"30s" env:"BULK_TIMEOUT" help:"How long a bulk request waits for its books. Books which take longer are left out of the (uncached) response. 0 waits indefinitely."`
+	AdminCIDR    []string      `env:"ADMIN_CIDR" help:"Networks (e.g. 10.0.0.0/8) allowed to call admin endpoints like /admin/reload. Only loopback is allowed by default."`
+	DebugHeaders bool          `env:"DEBUG_HEADERS" help:"Let clients add ?debug=1 to see an X-Cache-TTL header and where each work and author came from."`
+	RPM          int           `default:"0" env:"RPM" help:"Maximum upstream requests per minute."`
+	Cookie       string        `xor:"cookie" env:"COOKIE" help:"Cookie to use for upstream HTTP requests."`
+	CookieFile   []byte        `type:"filecontent" xor:"cookie" env:"COOKIE_FILE" help:"File with the Cookie to use for upstream HTTP requests."`
+	Proxy        string        `default:"" env:"PROXY" help:"HTTP proxy URL to use for upstream requests."`
+	Upstream     string        `required:"" env:"UPSTREAM" help:"Upstream host (e.g. www.example.com)."`
 }
 
 func (s *server) Run() error {
 	_ = s.LogConfig.Run()
+	if err := s.ResourceConfig.Run(); err != nil {
+		return err
+	}
+	if err := s.TracingConfig.Run("rggr"); err != nil {
+		return fmt.Errorf("setting up tracing: %w", err)
+	}
 	reg := internal.NewMetrics()
 
-	cf, err := s.Cache(reg)
+	ctx := context.Background()
+
+	cf, err := s.Cache(ctx, reg)
 	if err != nil {
 		return fmt.Errorf("setting up cloudflare: %w", err)
 	}
 
-	ctx := context.Background()
-	cache, err := internal.NewCache(ctx, s.DSN(), cf, reg)
+	cache, err := internal.NewCache(ctx, s.DSN(), cf, reg, s.CacheOptions()...)
 	if err != nil {
 		return fmt.Errorf("setting up cache: %w", err)
 	}
@@ -64,7 +84,7 @@ func (s *server) Run() error {
 		internal.Log(ctx).Info("--rpm is no longer required")
 	}
 
-	upstream, err := internal.NewUpstream(s.Upstream, s.Proxy)
+	upstream, err := internal.NewUpstream(s.Upstream, s.Proxy, s.TransportOptions()...)
 	if err != nil {
 		return err
 	}
@@ -76,12 +96,21 @@ func (s *server) Run() error {
 	// interaction between these requests and the upstream HEAD requests
 	// elsewhere. Especially if those result in a 404. That seems to trigger
 	// the WAF, which blocks everything for a period of time.
-	gql, err := internal.NewGRGQL(ctx, time.Second/2.0, 10, reg)
+	gql, err := internal.NewGRGQL(ctx, time.Second/2.0, 10, reg, s.GQLOptions()...)
 	if err != nil {
 		return err
 	}
 
-	getter, err := internal.NewGRGetter(cache, gql, upstream)
+	gopts, err := s.GetterConfig.Options()
+	if err != nil {
+		return err
+	}
+	gopts = append(gopts,
+		internal.WithGetterMetrics(reg),
+		// This work always 500s for some reason. Ignore it.
+		internal.WithPoisonIDs([]int64{146797269}, nil, nil),
+	)
+	getter, err := internal.NewGRGetter(cache, gql, upstream, gopts...)
 	if err != nil {
 		return err
 	}
@@ -91,17 +120,34 @@ func (s *server) Run() error {
 		return err
 	}
 
-	ctrl, err := internal.NewController(cache, getter, persister, reg)
+	opts, err := s.ControllerConfig.Options()
 	if err != nil {
 		return err
 	}
+
+	ctrl, err := internal.NewController(cache, getter, persister, reg, opts...)
+	if err != nil {
+		return err
+	}
+	reloader := cmd.NewReloader(ctrl, s.ControllerConfig, cmd.ReparseControllerConfig(func(c *cli) cmd.ControllerConfig {
+		return c.Serve.ControllerConfig
+	}))
+	go reloader.Watch(ctx)
+
 	h := internal.NewHandler(ctrl)
+	h.SetReloader(reloader.Reload)
+	h.SetBasePath(s.BasePath)
+	h.SetBulkTimeout(s.BulkTimeout)
+	h.SetDebugHeaders(s.DebugHeaders)
+	if err := h.SetAdminNetworks(s.AdminCIDR); err != nil {
+		return err
+	}
+	if err := s.CacheControlConfig.Apply(h); err != nil {
+		return err
+	}
 	mux := internal.NewMux(h, reg)
 
-	mux = middleware.RequestSize(1024)(mux)  // Limit request bodies.
-	mux = internal.Requestlogger{}.Wrap(mux) // Log requests.
-	mux = middleware.RequestID(mux)          // Include a request ID header.
-	mux = middleware.Recoverer(mux)          // Recover from panics.
+	mux = internal.Middleware(mux)
 
 	// TODO: The client doesn't send Accept-Encoding and doesn't handle
 	// Content-Encoding responses. This would allow us to send compressed bytes
@@ -128,6 +174,7 @@ func (s *server) Run() error {
 
 	go func() {
 		<-shutdown
+		s.TracingConfig.Shutdown()
 		os.Exit(0)
 		// slog.Info("waiting for denormalization to finish")
 		// ctrl.Shutdown(ctx)
@@ -136,6 +183,7 @@ func (s *server) Run() error {
 	}()
 
 	ctrl.Run(ctx)
+	s.TracingConfig.Shutdown()
 
 	slog.Info("au revoir!")
 
@@ -143,7 +191,7 @@ func (s *server) Run() error {
 }
 
 func main() {
-	kctx := kong.Parse(&cli{})
+	kctx := kong.Parse(&cli{}, kong.Configuration(kong.JSON))
 	err := kctx.Run()
 	if err != nil {
 		internal.Log(context.Background()).Error("fatal", "err", err)
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"log/slog"
 	"net/http"
+	"net/url"
 	"os"
 	"os/signal"
 	"time"
@@ -15,24 +16,37 @@ import (
 	"github.com/alecthomas/kong"
 	"github.com/blampe/rreading-glasses/cmd"
 	"github.com/blampe/rreading-glasses/internal"
-	"github.com/go-chi/chi/v5/middleware"
 )
 
 // cli contains our command-line flags.
 type cli struct {
 	Serve server `cmd:"" help:"Run an HTTP server."`
 
-	Bust cmd.Bust `cmd:"" help:"Bust cache entries."`
+	Bust   cmd.Bust   `cmd:"" help:"Bust cache entries."`
+	List   cmd.List   `cmd:"" help:"List cached author, work or series IDs."`
+	Verify cmd.Verify `cmd:"" help:"Check a cached author for problems clients can't handle, without modifying it."`
 }
 
 type server struct {
 	cmd.PGConfig
 	cmd.LogConfig
 	cmd.CloudflareConfig
-
-	Port     int    `default:"8788" env:"PORT" help:"Port to serve traffic on."`
-	Proxy    string `default:"" env:"PROXY" help:"HTTP proxy URL to use for upstream requests."`
-	Upstream string `default:"api.hardcover.app" env:"UPSTREAM" help:"Upstream host (e.g. www.example.com)."`
+	cmd.ResourceConfig
+	cmd.ControllerConfig
+	cmd.GetterConfig
+	cmd.UpstreamConfig
+	cmd.CacheControlConfig
+	cmd.TracingConfig
+
+	Config kong.ConfigFlag `env:"CONFIG" help:"JSON file to load flags from. Re-read on SIGHUP or POST /admin/reload."`
+
+	Port         int           `default:"8788" env:"PORT" help:"Port to serve traffic on."`
+	BasePath     string        `default:"" env:"BASE_PATH" help:"Path prefix to serve the API under (e.g. /metadata) when behind a reverse proxy."`
+	BulkTimeout  time.Duration `default:"30s" env:"BULK_TIMEOUT" help:"How long a bulk request waits for its books. Books which take longer are left out of the (uncached) response. 0 waits indefinitely."`
+	AdminCIDR    []string      `env:"ADMIN_CIDR" help:"Networks (e.g. 10.0.0.0/8) allowed to call admin endpoints like /admin/reload. Only loopback is allowed by default."`
+	DebugHeaders bool          `env:"DEBUG_HEADERS" help:"Let clients add ?debug=1 to see an X-Cache-TTL header and where each work and author came from."`
+	Proxy        string        `default:"" env:"PROXY" help:"HTTP proxy URL to use for upstream requests."`
+	Upstream     string        `default:"api.hardcover.app" env:"UPSTREAM" help:"Upstream host (e.g. www.example.com)."`
 
 	HardcoverAuth     string `required:"" env:"HARDCOVER_AUTH" xor:"hardcover-auth" help:"Hardcover Authorization header, e.g. 'Bearer ...'"`
 	HardcoverAuthFile []byte `required:"" type:"filecontent" xor:"hardcover-auth" env:"HARDCOVER_AUTH_FILE" help:"File containing the Hardcover Authorization header, e.g. 'Bearer ...'"`
@@ -40,15 +54,22 @@ type server struct {
 
 func (s *server) Run() error {
 	_ = s.LogConfig.Run()
+	if err := s.ResourceConfig.Run(); err != nil {
+		return err
+	}
+	if err := s.TracingConfig.Run("rghc"); err != nil {
+		return fmt.Errorf("setting up tracing: %w", err)
+	}
 	reg := internal.NewMetrics()
 
-	cf, err := s.Cache(reg)
+	ctx := context.Background()
+
+	cf, err := s.Cache(ctx, reg)
 	if err != nil {
 		return fmt.Errorf("setting up cloudflare: %w", err)
 	}
 
-	ctx := context.Background()
-	cache, err := internal.NewCache(ctx, s.DSN(), cf, reg)
+	cache, err := internal.NewCache(ctx, s.DSN(), cf, reg, s.CacheOptions()...)
 	if err != nil {
 		return fmt.Errorf("setting up cache: %w", err)
 	}
@@ -57,23 +78,38 @@ func (s *server) Run() error {
 		s.HardcoverAuth = string(bytes.TrimSpace(s.HardcoverAuthFile))
 	}
 
+	topts := s.TransportOptions()
+	if s.Proxy != "" {
+		proxy, err := url.Parse(s.Proxy)
+		if err != nil {
+			return fmt.Errorf("invalid proxy url: %w", err)
+		}
+		topts = append(topts, internal.WithProxy(proxy))
+	}
+
 	hcTransport := internal.ScopedTransport{
 		Host: s.Upstream,
 		RoundTripper: &internal.HeaderTransport{
 			Key:          "Authorization",
 			Value:        s.HardcoverAuth,
-			RoundTripper: http.DefaultTransport,
+			RoundTripper: internal.NewTransport(topts...),
 		},
 	}
 
 	hcClient := &http.Client{Transport: hcTransport}
 
-	gql, err := internal.NewBatchedGraphQLClient("https://api.hardcover.app/v1/graphql", hcClient, time.Second, 25 /* Not sure about this */, reg)
+	gql, err := internal.NewBatchedGraphQLClient(ctx, "https://api.hardcover.app/v1/graphql", hcClient, time.Second, 25 /* Not sure about this */, reg, s.GQLOptions()...)
 	if err != nil {
 		return err
 	}
 
-	getter, err := internal.NewHardcoverGetter(cache, gql)
+	gopts, err := s.GetterConfig.Options()
+	if err != nil {
+		return err
+	}
+
+	gopts = append(gopts, internal.WithGetterMetrics(reg))
+	getter, err := internal.NewHardcoverGetter(cache, gql, gopts...)
 	if err != nil {
 		return err
 	}
@@ -83,17 +119,34 @@ func (s *server) Run() error {
 		return err
 	}
 
-	ctrl, err := internal.NewController(cache, getter, persister, reg)
+	opts, err := s.ControllerConfig.Options()
+	if err != nil {
+		return err
+	}
+
+	ctrl, err := internal.NewController(cache, getter, persister, reg, opts...)
 	if err != nil {
 		return err
 	}
+	reloader := cmd.NewReloader(ctrl, s.ControllerConfig, cmd.ReparseControllerConfig(func(c *cli) cmd.ControllerConfig {
+		return c.Serve.ControllerConfig
+	}))
+	go reloader.Watch(ctx)
+
 	h := internal.NewHandler(ctrl)
+	h.SetReloader(reloader.Reload)
+	h.SetBasePath(s.BasePath)
+	h.SetBulkTimeout(s.BulkTimeout)
+	h.SetDebugHeaders(s.DebugHeaders)
+	if err := h.SetAdminNetworks(s.AdminCIDR); err != nil {
+		return err
+	}
+	if err := s.CacheControlConfig.Apply(h); err != nil {
+		return err
+	}
 	mux := internal.NewMux(h, reg)
 
-	mux = middleware.RequestSize(1024)(mux)  // Limit request bodies.
-	mux = internal.Requestlogger{}.Wrap(mux) // Log requests.
-	mux = middleware.RequestID(mux)          // Include a request ID header.
-	mux = middleware.Recoverer(mux)          // Recover from panics.
+	mux = internal.Middleware(mux)
 
 	// TODO: The client doesn't send Accept-Encoding and doesn't handle
 	// Content-Encoding responses. This would allow us to send compressed bytes
@@ -120,6 +173,7 @@ func (s *server) Run() error {
 
 	go func() {
 		<-shutdown
+		s.TracingConfig.Shutdown()
 		os.Exit(0)
 		// slog.Info("shutting down http server")
 		// _ = server.Shutdown(ctx)
@@ -128,6 +182,7 @@ func (s *server) Run() error {
 	}()
 
 	ctrl.Run(ctx)
+	s.TracingConfig.Shutdown()
 
 	slog.Info("au revoir!")
 
@@ -135,7 +190,7 @@ func (s *server) Run() error {
 }
 
 func main() {
-	kctx := kong.Parse(&cli{})
+	kctx := kong.Parse(&cli{}, kong.Configuration(kong.JSON))
 	err := kctx.Run()
 	if err != nil {
 		internal.Log(context.Background()).Error("fatal", "err", err)
@@ -2,19 +2,31 @@
 package cmd
 
 import (
+	"bufio"
 	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"log/slog"
+	"os"
+	"os/signal"
 	"path/filepath"
+	"reflect"
+	"slices"
+	"strconv"
 	"strings"
+	"sync"
+	"syscall"
+	"time"
 
 	"github.com/KimMachineGun/automemlimit/memlimit"
+	"github.com/alecthomas/kong"
 	"github.com/blampe/rreading-glasses/internal"
 	charm "github.com/charmbracelet/log"
+	"github.com/go-chi/chi/v5/middleware"
 	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/sync/errgroup"
 )
 
 // PGConfig configured a PostGres connection.
@@ -25,6 +37,23 @@ type PGConfig struct {
 	PostgresPasswordFile []byte `type:"filecontent" xor:"db-auth" env:"POSTGRES_PASSWORD_FILE" help:"File with the Postgres password."`
 	PostgresPort         int    `default:"5432" env:"POSTGRES_PORT" help:"Postgres port."`
 	PostgresDatabase     string `default:"rreading-glasses" env:"POSTGRES_DATABASE" help:"Postgres database to use."`
+
+	CompactionInterval time.Duration `default:"24h" env:"COMPACTION_INTERVAL" help:"How often to delete expired rows from Postgres. Set to 0 to disable."`
+	CompactionGrace    time.Duration `default:"720h" env:"COMPACTION_GRACE" help:"How long a row must be expired before it's deleted."`
+	ReadOnly           bool          `env:"READ_ONLY" help:"Never write to Postgres or Cloudflare. Useful for canaries sharing a database."`
+	MemoryCacheMB      int64         `default:"0" env:"MEMORY_CACHE_MB" help:"Size of the in-memory cache in front of Postgres, in MiB. 0 uses half of the memory limit (GOMEMLIMIT)."`
+}
+
+// CacheOptions returns cache options based on the provided flags.
+func (c *PGConfig) CacheOptions() []internal.CacheOption {
+	opts := []internal.CacheOption{
+		internal.WithCompaction(c.CompactionInterval, c.CompactionGrace),
+		internal.WithMemorySize(c.MemoryCacheMB << 20),
+	}
+	if c.ReadOnly {
+		opts = append(opts, internal.WithReadOnly())
+	}
+	return opts
 }
 
 // DSN returns the database's DSN based on the provided flags.
@@ -51,14 +80,428 @@ func (c *PGConfig) DSN() string {
 
 // LogConfig configures logging.
 type LogConfig struct {
-	Verbose bool `env:"VERBOSE" help:"increase log verbosity"`
+	Verbose         bool   `env:"VERBOSE" help:"increase log verbosity"`
+	RequestIDHeader string `default:"X-Request-Id" env:"REQUEST_ID_HEADER" help:"Incoming header to take request IDs from (e.g. X-Correlation-ID), so a proxy's IDs show up in our logs. An ID is generated if the header is missing."`
 }
 
-// Run sets logging to DEBUG if verbose is enabled.
+// Run sets logging to DEBUG if verbose is enabled, and sets which header
+// request IDs are read from.
 func (c *LogConfig) Run() error {
 	if c.Verbose {
 		internal.SetLogLevel(charm.DebugLevel)
 	}
+	if c.RequestIDHeader != "" {
+		middleware.RequestIDHeader = c.RequestIDHeader
+	}
+	return nil
+}
+
+// TracingConfig optionally exports OpenTelemetry traces.
+type TracingConfig struct {
+	OTLPEndpoint string `env:"OTLP_ENDPOINT" help:"OTLP/HTTP collector to export traces to (e.g. localhost:4318). Tracing is disabled if unset."`
+
+	shutdown func(context.Context) error
+}
+
+// Run sets up trace exporting if an endpoint was provided.
+func (c *TracingConfig) Run(service string) error {
+	if c.OTLPEndpoint == "" {
+		return nil
+	}
+	shutdown, err := internal.SetupTracing(context.Background(), c.OTLPEndpoint, service)
+	if err != nil {
+		return err
+	}
+	c.shutdown = shutdown
+	return nil
+}
+
+// Shutdown flushes any buffered spans. It's a no-op if tracing is disabled.
+func (c *TracingConfig) Shutdown() {
+	if c.shutdown == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := c.shutdown(ctx); err != nil {
+		internal.Log(ctx).Warn("problem flushing traces", "err", err)
+	}
+}
+
+// ResourceConfig configures how resources are presented to clients.
+type ResourceConfig struct {
+	DescriptionPlaceholder string   `default:"N/A" env:"DESCRIPTION_PLACEHOLDER" help:"Text to use for empty book and author descriptions. May be empty."`
+	GenrePlaceholder       string   `default:"none" env:"GENRE_PLACEHOLDER" help:"Genre to use for books without any. May be empty, in which case no genres are returned."`
+	MaxFutureYears         int      `default:"0" env:"MAX_FUTURE_YEARS" help:"Omit release dates more than this many years in the future as likely typos. 0 disables the check."`
+	MinEditionPages        int64    `default:"0" env:"MIN_EDITION_PAGES" help:"Prefer editions with at least this many pages as a work's best edition, to avoid placeholder records. Audiobooks are exempt. 0 disables the check."`
+	PrimaryLanguage        string   `env:"PRIMARY_LANGUAGE" help:"Language (e.g. fra or fr) to prefer when choosing a work's best edition and ordering or trimming its editions. Callers can still override this with ?lang= on /work."`
+	EditionInformation     bool     `default:"true" negatable:"" env:"EDITION_INFORMATION" help:"Include edition notes like \"Illustrated\" or \"Revised Edition\", which clients can show to tell editions apart."`
+	FullSizeImages         bool     `default:"true" negatable:"" env:"FULL_SIZE_IMAGES" help:"Strip size suffixes like ._SX98_ from cover and author image URLs so clients get the full-resolution image instead of a thumbnail."`
+	LanguageOverride       []string `env:"LANGUAGE_OVERRIDE" help:"Map an upstream language name or code to the ISO 639-3 code its editions should use, e.g. \"Filipino:fil\" or \"nob:nor\". Unrecognized languages are logged. Formatted as name:code."`
+	OriginalTitles         bool     `env:"ORIGINAL_TITLES" help:"Include each work's original-language title as OriginalTitle, for clients which show it alongside a translated title. Only G——R—— provides it."`
+	UnratedRating          float64  `default:"0" env:"UNRATED_RATING" help:"Average rating to report for books and authors without any ratings. 0 looks like a zero-star rating to clients; a negative value like -1 lets them tell unrated books apart."`
+	EditionOrder           string   `default:"id" enum:"id,ratings,recency" env:"EDITION_ORDER" help:"How to order a work's editions when it's served: id, ratings or recency. Other than id, the work's best edition is listed first, since clients show the first edition most prominently. Editions in the primary language, or the one requested with ?lang=, still come first. Changes apply as works are refreshed."`
+}
+
+// Run applies the resource settings.
+func (c *ResourceConfig) Run() error {
+	internal.SetDescriptionPlaceholder(c.DescriptionPlaceholder)
+	internal.SetGenrePlaceholder(c.GenrePlaceholder)
+	internal.SetMaxFutureYears(c.MaxFutureYears)
+	internal.SetMinEditionPages(c.MinEditionPages)
+	internal.SetEditionInformation(c.EditionInformation)
+	internal.SetOriginalTitles(c.OriginalTitles)
+	internal.SetFullSizeImages(c.FullSizeImages)
+	internal.SetUnratedRating(c.UnratedRating)
+	if err := internal.SetEditionOrder(internal.EditionOrder(c.EditionOrder)); err != nil {
+		return err
+	}
+
+	overrides := map[string]string{}
+	for _, override := range c.LanguageOverride {
+		from, to, ok := strings.Cut(override, ":")
+		if !ok || strings.TrimSpace(from) == "" || strings.TrimSpace(to) == "" {
+			return fmt.Errorf("invalid language override %q: expected name:code", override)
+		}
+		overrides[from] = to
+	}
+	internal.SetLanguageOverrides(overrides)
+
+	// Overrides need to be in place before the primary language is resolved.
+	if err := internal.SetPrimaryLanguage(c.PrimaryLanguage); err != nil {
+		return fmt.Errorf("setting primary language: %w", err)
+	}
+	return nil
+}
+
+// ControllerConfig configures optional controller behavior.
+type ControllerConfig struct {
+	MaxAuthorWorks         int      `default:"1000" env:"MAX_AUTHOR_WORKS" help:"Maximum number of works to load per author. The most popular works are loaded first."`
+	EmbeddedWorks          int      `default:"0" env:"EMBEDDED_WORKS" help:"Maximum number of works to include in an author response, or 0 for no limit. Unlike --max-author-works this doesn't affect what's loaded, and left out works can still be fetched directly."`
+	EmbeddedWorksRecency   float64  `default:"0.5" env:"EMBEDDED_WORKS_RECENCY" help:"How much to prefer recent works over well-rated works when limiting --embedded-works, from 0 (only rating) to 1 (only recency)."`
+	RelaxEditionAuthors    bool     `env:"RELAX_EDITION_AUTHORS" help:"Keep editions whose primary author differs from the work's, as long as the work's author is credited on the edition."`
+	AuthorAlias            []string `env:"AUTHOR_ALIAS" help:"Serve one author in place of another, e.g. after upstream merges them. Formatted as oldID:newID."`
+	MaxEditionsPerWork     int      `default:"0" env:"MAX_EDITIONS_PER_WORK" help:"Maximum number of editions to keep per work, or 0 for no limit. The best edition is always kept."`
+	MaxAuthorSeries        int      `default:"100" env:"MAX_AUTHOR_SERIES" help:"Maximum number of series to load per author, or 0 for no limit. Series with more of the author's works are loaded first."`
+	MaxSeriesLinks         int      `default:"0" env:"MAX_SERIES_LINKS" help:"Maximum number of works listed by each series in an author, or 0 for no limit. The author's own works are kept first. /series is unaffected."`
+	AuthorSeriesOnly       bool     `env:"AUTHOR_SERIES_ONLY" help:"Only list the author's own works in series returned with an author. /series is unaffected."`
+	SearchRank             []string `env:"SEARCH_RANK" help:"Re-rank search results by these signals, in priority order: title, ratings, recency. Results keep the upstream's order by default."`
+	RefreshMemoryThreshold float64  `default:"0" env:"REFRESH_MEMORY_THRESHOLD" help:"Pause new author refreshes while heap usage exceeds this fraction of the memory limit, e.g. 0.8. 0 disables the check."`
+	AuthorStubs            bool     `env:"AUTHOR_STUBS" help:"Return a minimal author (name and image, no works) on a cold cache while the full author loads in the background. Some clients expect at least one work."`
+	DenormWorkers          int      `default:"1" env:"DENORM_WORKERS" help:"How many authors and works to denormalize in parallel. Requires a restart."`
+	WorkAuthor             []string `env:"WORK_AUTHOR" help:"Correct a work's misattributed primary author. Formatted as workID:authorID."`
+	WorkAuthorFile         []byte   `type:"filecontent" env:"WORK_AUTHOR_FILE" help:"File with work author corrections, one per line formatted as workID:authorID."`
+	ContinuingMonths       int      `default:"12" env:"CONTINUING_MONTHS" help:"Mark authors as continuing if they released a work within this many months. 0 disables it."`
+	SearchISBN             bool     `env:"SEARCH_ISBN" help:"Include each search result's ISBN-13. Slower on a cold cache because every result's edition is loaded."`
+	EditionsPerPass        int      `default:"25" env:"EDITIONS_PER_PASS" help:"Maximum editions added to a work per denormalization pass. Larger batches are split across passes so each finishes in time. 0 for no limit."`
+	KeepEmptyWorks         bool     `env:"KEEP_EMPTY_WORKS" help:"List works on their author even if all of their editions were filtered out. They're returned without editions instead of being dropped."`
+	RevalidateWorkers      int      `default:"8" env:"REVALIDATE_WORKERS" help:"How many of a work's expired editions to re-fetch in parallel when the work is refreshed."`
+	BackgroundWorkers      int      `default:"1000" env:"BACKGROUND_WORKERS" help:"Maximum goroutines the controller runs at once for background work, like ensuring a fetched edition's work and author. Work beyond this is dropped until load subsides."`
+	MinEditionYear         int      `default:"0" env:"MIN_EDITION_YEAR" help:"Exclude editions released before this year (e.g. 1450), which are usually data errors. The best edition is always kept. 0 disables it."`
+	ExcludeOmnibus         bool     `env:"EXCLUDE_OMNIBUS" help:"Exclude box sets and omnibus editions (e.g. \"Books 1-3\", or far longer than the work's other editions) from a work's editions. They can still be fetched directly. The best edition is always kept."`
+	SeriesSubtitles        bool     `default:"true" negatable:"" env:"SERIES_SUBTITLES" help:"Always include the subtitle of works in a series, like \"Baz: The Baz Series #3\". Disable to only include subtitles when an author has several works with the same title."`
+
+	NotifyURL      string        `env:"NOTIFY_URL" help:"POST {\"type\": \"author\"|\"work\", \"foreignId\": ID} to this URL when an author or work changes."`
+	NotifyDebounce time.Duration `default:"30s" env:"NOTIFY_DEBOUNCE" help:"How long to collect updates before notifying. Each author or work is sent at most once per interval."`
+	ServeStale     time.Duration `default:"0" env:"SERVE_STALE" help:"While upstream is failing (5XX or timeouts), serve cached data up to this long past its expiry instead of an error. Requires Postgres. 0 disables it."`
+}
+
+// Options returns controller options based on the provided flags.
+func (c *ControllerConfig) Options() ([]internal.ControllerOption, error) {
+	aliases := map[int64]int64{}
+	for _, alias := range c.AuthorAlias {
+		oldID, newID, ok := strings.Cut(alias, ":")
+		if !ok {
+			return nil, fmt.Errorf("invalid author alias %q: expected oldID:newID", alias)
+		}
+		from, err := strconv.ParseInt(oldID, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid author alias %q: %w", alias, err)
+		}
+		to, err := strconv.ParseInt(newID, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid author alias %q: %w", alias, err)
+		}
+		aliases[from] = to
+	}
+
+	workAuthors := map[int64]int64{}
+	lines := slices.Clone(c.WorkAuthor)
+	for line := range strings.Lines(string(c.WorkAuthorFile)) {
+		line, _, _ = strings.Cut(line, "#")
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	for _, line := range lines {
+		rawWorkID, rawAuthorID, ok := strings.Cut(line, ":")
+		if !ok {
+			return nil, fmt.Errorf("invalid work author %q: expected workID:authorID", line)
+		}
+		workID, err := strconv.ParseInt(strings.TrimSpace(rawWorkID), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid work author %q: %w", line, err)
+		}
+		authorID, err := strconv.ParseInt(strings.TrimSpace(rawAuthorID), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid work author %q: %w", line, err)
+		}
+		workAuthors[workID] = authorID
+	}
+
+	if c.RefreshMemoryThreshold < 0 || c.RefreshMemoryThreshold > 1 {
+		return nil, fmt.Errorf("invalid refresh memory threshold %v: expected a fraction between 0 and 1", c.RefreshMemoryThreshold)
+	}
+
+	signals := []internal.SearchSignal{}
+	for _, rank := range c.SearchRank {
+		switch signal := internal.SearchSignal(strings.ToLower(strings.TrimSpace(rank))); signal {
+		case internal.SearchByTitle, internal.SearchByRatings, internal.SearchByRecency:
+			signals = append(signals, signal)
+		default:
+			return nil, fmt.Errorf("invalid search rank %q: expected title, ratings or recency", rank)
+		}
+	}
+
+	return []internal.ControllerOption{
+		internal.WithMaxAuthorWorks(c.MaxAuthorWorks),
+		internal.WithEmbeddedWorks(c.EmbeddedWorks, c.EmbeddedWorksRecency),
+		internal.WithRelaxedEditionAuthors(c.RelaxEditionAuthors),
+		internal.WithAuthorAliases(aliases),
+		internal.WithMaxEditionsPerWork(c.MaxEditionsPerWork),
+		internal.WithMaxAuthorSeries(c.MaxAuthorSeries),
+		internal.WithMaxSeriesLinks(c.MaxSeriesLinks),
+		internal.WithAuthorSeriesOnly(c.AuthorSeriesOnly),
+		internal.WithSearchRanking(signals...),
+		internal.WithRefreshMemoryThreshold(c.RefreshMemoryThreshold),
+		internal.WithAuthorStubs(c.AuthorStubs),
+		internal.WithDenormWorkers(c.DenormWorkers),
+		internal.WithWorkAuthors(workAuthors),
+		internal.WithContinuingMonths(c.ContinuingMonths),
+		internal.WithSearchISBN(c.SearchISBN),
+		internal.WithEditionsPerPass(c.EditionsPerPass),
+		internal.WithRevalidateWorkers(c.RevalidateWorkers),
+		internal.WithBackgroundWorkers(c.BackgroundWorkers),
+		internal.WithKeepEmptyWorks(c.KeepEmptyWorks),
+		internal.WithMinEditionYear(c.MinEditionYear),
+		internal.WithExcludeOmnibus(c.ExcludeOmnibus),
+		internal.WithSeriesSubtitles(c.SeriesSubtitles),
+		internal.WithNotifyURL(c.NotifyURL, c.NotifyDebounce),
+		internal.WithServeStale(c.ServeStale),
+	}, nil
+}
+
+// Reloader re-applies controller configuration to a running server, so
+// operators can tweak behavior without restarting and losing the warm
+// in-memory cache. Only ControllerConfig is reloaded; resource, getter, cache
+// and server flags (e.g. --edition-order, --author-kca-ttl, --cache-control,
+// --bulk-timeout, --admin-cidr) still require a restart.
+type Reloader struct {
+	mu      sync.Mutex
+	ctrl    *internal.Controller
+	current ControllerConfig
+	parse   func() (ControllerConfig, error)
+
+	// extra are controller options which aren't part of ControllerConfig.
+	// Reconfigure replaces every option, so they're re-applied each reload.
+	extra []internal.ControllerOption
+}
+
+// NewReloader creates a Reloader for the controller. parse should re-read
+// flags, environment and any config file. extra should hold any options the
+// controller was created with besides current's, so they survive a reload.
+func NewReloader(ctrl *internal.Controller, current ControllerConfig, parse func() (ControllerConfig, error), extra ...internal.ControllerOption) *Reloader {
+	return &Reloader{ctrl: ctrl, current: current, parse: parse, extra: extra}
+}
+
+// ReparseControllerConfig returns a parse function for NewReloader which
+// re-reads our arguments, environment and config file into a fresh CLI of
+// type T. config extracts the controller configuration from it.
+func ReparseControllerConfig[T any](config func(*T) ControllerConfig) func() (ControllerConfig, error) {
+	return func() (ControllerConfig, error) {
+		var c T
+		parser, err := kong.New(&c, kong.Configuration(kong.JSON))
+		if err != nil {
+			return ControllerConfig{}, err
+		}
+		_, err = parser.Parse(os.Args[1:])
+		return config(&c), err
+	}
+}
+
+// Reload re-parses configuration and applies it to the controller, along with
+// any extra options. Invalid configuration is rejected and the previous
+// options stay in effect.
+func (r *Reloader) Reload(ctx context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	next, err := r.parse()
+	if err != nil {
+		return fmt.Errorf("parsing config: %w", err)
+	}
+	opts, err := next.Options()
+	if err != nil {
+		return fmt.Errorf("validating config: %w", err)
+	}
+
+	changed := 0
+	prev, cur := reflect.ValueOf(r.current), reflect.ValueOf(next)
+	for i := range prev.NumField() {
+		before, after := prev.Field(i).Interface(), cur.Field(i).Interface()
+		if reflect.DeepEqual(before, after) {
+			continue
+		}
+		internal.Log(ctx).Info("config changed", "field", prev.Type().Field(i).Name, "old", before, "new", after)
+		changed++
+	}
+
+	r.ctrl.Reconfigure(append(opts, r.extra...)...)
+	r.current = next
+
+	internal.Log(ctx).Info("config reloaded", "changed", changed)
+	return nil
+}
+
+// Watch reloads configuration whenever the process receives SIGHUP, until the
+// context is canceled.
+func (r *Reloader) Watch(ctx context.Context) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hup:
+			if err := r.Reload(ctx); err != nil {
+				internal.Log(ctx).Error("reloading config", "err", err)
+			}
+		}
+	}
+}
+
+// GetterConfig configures optional getter behavior.
+type GetterConfig struct {
+	SkipEbooks     bool     `env:"SKIP_EBOOKS" help:"Don't include ebook editions in a work's editions. They can still be looked up directly."`
+	AudioFormats   []string `env:"AUDIO_FORMATS" help:"Edition formats to treat as audiobooks. Defaults to common audiobook formats like Audible Audio and Audio CD."`
+	AudioNarrators bool     `env:"AUDIO_NARRATORS" help:"Keep one audiobook edition per narrator and credit the narrator as a contributor. By default only the most popular audiobook edition of a title is kept."`
+	AllEditions    bool     `env:"ALL_EDITIONS" help:"Keep every edition upstream has for a work instead of one per title, language and format, for cataloging. Work and author responses can become very large; consider --max-editions-per-work. --skip-ebooks still applies."`
+
+	PoisonWorks   []int64 `env:"POISON_WORKS" help:"Work IDs known to break the upstream. They're reported as not found."`
+	PoisonBooks   []int64 `env:"POISON_BOOKS" help:"Book (edition) IDs known to break the upstream. They're reported as not found."`
+	PoisonAuthors []int64 `env:"POISON_AUTHORS" help:"Author IDs known to break the upstream. They're reported as not found."`
+	PoisonFile    []byte  `type:"filecontent" env:"POISON_FILE" help:"File with IDs known to break the upstream, one per line formatted as work:ID, book:ID or author:ID."`
+
+	EmptyAuthors bool `env:"EMPTY_AUTHORS" help:"Return authors without any valid works (e.g. only credited as an editor) with no works instead of not found. Hardcover only."`
+
+	DebugRaw time.Duration `default:"0" env:"DEBUG_RAW" help:"Keep raw upstream responses for this long, served at /debug/raw/{key} (e.g. /debug/raw/w123). 0 disables it."`
+
+	AuthorKCATTL time.Duration `default:"2160h" env:"AUTHOR_KCA_TTL" help:"How long to cache resolved author KCAs. 0 disables it. GR only."`
+
+	AuthorProbe int `default:"20" env:"AUTHOR_PROBE" help:"How many of a cold author's works to consider for the single work shown while the full author loads. The most popular wins. GR only."`
+}
+
+// Options returns getter options based on the provided flags.
+func (c *GetterConfig) Options() ([]internal.GetterOption, error) {
+	works := slices.Clone(c.PoisonWorks)
+	books := slices.Clone(c.PoisonBooks)
+	authors := slices.Clone(c.PoisonAuthors)
+
+	for line := range strings.Lines(string(c.PoisonFile)) {
+		line, _, _ = strings.Cut(line, "#")
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		kind, rawID, ok := strings.Cut(line, ":")
+		if !ok {
+			return nil, fmt.Errorf("invalid poison ID %q: expected kind:ID", line)
+		}
+		id, err := strconv.ParseInt(strings.TrimSpace(rawID), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid poison ID %q: %w", line, err)
+		}
+		switch strings.TrimSpace(kind) {
+		case "work":
+			works = append(works, id)
+		case "book":
+			books = append(books, id)
+		case "author":
+			authors = append(authors, id)
+		default:
+			return nil, fmt.Errorf("invalid poison ID %q: expected work, book or author", line)
+		}
+	}
+
+	return []internal.GetterOption{
+		internal.WithSkipEbooks(c.SkipEbooks),
+		internal.WithAudioFormats(c.AudioFormats...),
+		internal.WithAudioNarrators(c.AudioNarrators),
+		internal.WithAllEditions(c.AllEditions),
+		internal.WithPoisonIDs(works, books, authors),
+		internal.WithEmptyAuthors(c.EmptyAuthors),
+		internal.WithRawResponses(c.DebugRaw),
+		internal.WithAuthorKCATTL(c.AuthorKCATTL),
+		internal.WithAuthorProbe(c.AuthorProbe),
+	}, nil
+}
+
+// UpstreamConfig tunes connection reuse for upstream requests.
+type UpstreamConfig struct {
+	MaxIdleConns         int           `default:"100" env:"MAX_IDLE_CONNS" help:"Maximum idle upstream connections to keep across all hosts."`
+	MaxIdleConnsPerHost  int           `default:"32" env:"MAX_IDLE_CONNS_PER_HOST" help:"Maximum idle upstream connections to keep per host."`
+	IdleConnTimeout      time.Duration `default:"90s" env:"IDLE_CONN_TIMEOUT" help:"How long idle upstream connections are kept before closing."`
+	MaxInFlightBatches   int           `default:"8" env:"MAX_IN_FLIGHT_BATCHES" help:"Maximum upstream GraphQL batches awaiting a response at once. 0 is unbounded."`
+	AuthFailureThreshold int           `default:"10" env:"AUTH_FAILURE_THRESHOLD" help:"Stop sending GraphQL batches after this many in a row are rejected with a 401 or 403, which usually means the API key is invalid. 0 disables it."`
+	AuthBackoff          time.Duration `default:"5m" env:"AUTH_BACKOFF" help:"How long to stop sending GraphQL batches after too many auth failures. Queries fail immediately in the meantime."`
+}
+
+// TransportOptions returns transport options based on the provided flags.
+func (c *UpstreamConfig) TransportOptions() []internal.TransportOption {
+	return []internal.TransportOption{
+		internal.WithIdleConns(c.MaxIdleConns, c.MaxIdleConnsPerHost, c.IdleConnTimeout),
+	}
+}
+
+// GQLOptions returns GraphQL client options based on the provided flags.
+func (c *UpstreamConfig) GQLOptions() []internal.GQLOption {
+	return []internal.GQLOption{
+		internal.WithMaxInFlight(c.MaxInFlightBatches),
+		internal.WithAuthBackoff(c.AuthFailureThreshold, c.AuthBackoff),
+	}
+}
+
+// CacheControlConfig tunes the Cache-Control headers sent to clients and CDNs.
+type CacheControlConfig struct {
+	CacheControl []string `env:"CACHE_CONTROL" help:"Override an endpoint's client max-age and CDN s-maxage, formatted as endpoint:maxAge:sMaxAge (e.g. series:24h:168h). Leave a duration empty to keep its default. Endpoints are search, bulk, work, book, author, series, changed and recommended."`
+	Gzip         bool     `env:"GZIP" help:"Compress work, author and series responses for clients which send Accept-Encoding: gzip. Responses vary on Accept-Encoding so CDNs keep both versions apart."`
+}
+
+// Apply sets the handler's cache policies based on the provided flags.
+func (c *CacheControlConfig) Apply(h *internal.Handler) error {
+	h.SetCompression(c.Gzip)
+	for _, raw := range c.CacheControl {
+		parts := strings.Split(raw, ":")
+		if len(parts) != 3 {
+			return fmt.Errorf("invalid cache control %q: expected endpoint:maxAge:sMaxAge", raw)
+		}
+		var policy internal.CachePolicy
+		for i, d := range []*time.Duration{&policy.MaxAge, &policy.SMaxAge} {
+			s := strings.TrimSpace(parts[i+1])
+			if s == "" {
+				continue
+			}
+			var err error
+			if *d, err = time.ParseDuration(s); err != nil {
+				return fmt.Errorf("invalid cache control %q: %w", raw, err)
+			}
+		}
+		if err := h.SetCachePolicy(strings.TrimSpace(parts[0]), policy); err != nil {
+			return fmt.Errorf("invalid cache control %q: %w", raw, err)
+		}
+	}
 	return nil
 }
 
@@ -70,7 +513,7 @@ type CloudflareConfig struct {
 }
 
 // Cache returns the cloudflare cache, if it was configured, or nil otherwise.
-func (c *CloudflareConfig) Cache(reg *prometheus.Registry) (*internal.CloudflareCache, error) {
+func (c *CloudflareConfig) Cache(ctx context.Context, reg *prometheus.Registry) (*internal.CloudflareCache, error) {
 	if c.CloudflareToken == "" {
 		return nil, nil
 	}
@@ -95,13 +538,16 @@ func (c *CloudflareConfig) Cache(reg *prometheus.Registry) (*internal.Cloudflare
 		if strings.HasPrefix(key, "i") {
 			return fmt.Sprintf("https://%s/book/isbn/%s", c.CloudflareDomain, key[1:])
 		}
+		if strings.HasPrefix(key, "debug:") {
+			return fmt.Sprintf("https://%s/debug/raw/%s", c.CloudflareDomain, strings.TrimPrefix(key, "debug:"))
+		}
 		if strings.HasPrefix(key, "/search") {
 			return "https://" + c.CloudflareDomain + key
 		}
 		return "https://" + c.CloudflareDomain + "/unrecognized"
 	}
 
-	return internal.NewCloudflareCache(c.CloudflareToken, c.CloudflareZoneID, pather, reg)
+	return internal.NewCloudflareCache(ctx, c.CloudflareToken, c.CloudflareZoneID, pather, reg)
 }
 
 // Bust allows manually busting entries from the CLI.
@@ -111,6 +557,8 @@ type Bust struct {
 	CloudflareConfig
 
 	AuthorID int64 `arg:"" help:"author ID to cache bust"`
+	Cascade  bool  `default:"true" negatable:"" help:"Also bust the author's works and editions. Disable to only re-denormalize the author from cached works."`
+	Parallel int   `default:"16" help:"How many works and editions to bust in parallel."`
 }
 
 // Run busts a cache key.
@@ -118,7 +566,7 @@ func (b *Bust) Run() error {
 	_ = b.LogConfig.Run()
 	ctx := context.Background()
 
-	cf, err := b.Cache(nil)
+	cf, err := b.Cache(ctx, nil)
 	if err != nil {
 		return fmt.Errorf("setting up cloudflare: %w", err)
 	}
@@ -128,6 +576,21 @@ func (b *Bust) Run() error {
 		return err
 	}
 
+	return b.bust(ctx, cache)
+}
+
+// expirer is the part of the cache Bust needs.
+type expirer interface {
+	Get(ctx context.Context, key string) ([]byte, bool)
+	Expire(ctx context.Context, key string) error
+}
+
+// bust expires the author, and its works and editions if cascading.
+func (b *Bust) bust(ctx context.Context, cache expirer) (err error) {
+	if !b.Cascade {
+		return cache.Expire(ctx, internal.AuthorKey(b.AuthorID))
+	}
+
 	a, ok := cache.Get(ctx, internal.AuthorKey(b.AuthorID))
 	if !ok {
 		return nil
@@ -139,17 +602,109 @@ func (b *Bust) Run() error {
 		return err
 	}
 
+	// Large authors can have thousands of editions, so bust them in parallel
+	// while still reporting every failure.
+	mu := sync.Mutex{}
+	g := errgroup.Group{}
+	g.SetLimit(max(b.Parallel, 1))
+	expire := func(key string) {
+		g.Go(func() error {
+			if expireErr := cache.Expire(ctx, key); expireErr != nil {
+				mu.Lock()
+				defer mu.Unlock()
+				err = errors.Join(err, fmt.Errorf("busting %s: %w", key, expireErr))
+			}
+			return nil
+		})
+	}
+
 	for _, w := range author.Works {
 		for _, b := range w.Books {
-			err = errors.Join(err, cache.Expire(ctx, internal.BookKey(b.ForeignID)))
+			expire(internal.BookKey(b.ForeignID))
 		}
-		err = errors.Join(err, cache.Expire(ctx, internal.WorkKey(w.ForeignID)))
+		expire(internal.WorkKey(w.ForeignID))
 	}
+	_ = g.Wait()
+
+	// Bust the author once its works and editions are gone.
 	err = errors.Join(err, cache.Expire(ctx, internal.AuthorKey(author.ForeignID)))
 
 	return err
 }
 
+// List prints cached IDs so bulk operations can be scripted.
+type List struct {
+	PGConfig
+	LogConfig
+
+	Kind string `arg:"" enum:"authors,works,series" help:"What to list: authors, works or series."`
+}
+
+// Run prints the ID of every cached resource of the given kind, one per line.
+func (l *List) Run() error {
+	// Keep stdout clean for scripting.
+	internal.SetLogLevel(charm.WarnLevel)
+	_ = l.LogConfig.Run()
+	ctx := context.Background()
+
+	kinds := map[string]internal.KeyKind{
+		"authors": internal.AuthorKeys,
+		"works":   internal.WorkKeys,
+		"series":  internal.SeriesKeys,
+	}
+
+	out := bufio.NewWriter(os.Stdout)
+	for id, err := range internal.CachedIDs(ctx, l.DSN(), kinds[l.Kind]) {
+		if err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintln(out, id)
+	}
+	return out.Flush()
+}
+
+// Verify audits a cached author from the CLI.
+type Verify struct {
+	PGConfig
+	LogConfig
+
+	AuthorID int64 `arg:"" help:"author ID to verify"`
+}
+
+// Run prints every invariant the cached author violates, one per line, and
+// fails if there are any. Nothing is modified.
+func (v *Verify) Run() error {
+	_ = v.LogConfig.Run()
+	ctx := context.Background()
+
+	cache, err := internal.NewCache(ctx, v.DSN(), nil, nil, internal.WithReadOnly())
+	if err != nil {
+		return err
+	}
+
+	a, ok := cache.Get(ctx, internal.AuthorKey(v.AuthorID))
+	if !ok {
+		return fmt.Errorf("author %d isn't cached", v.AuthorID)
+	}
+
+	violations, err := internal.VerifyAuthor(a)
+	if err != nil {
+		return err
+	}
+
+	out := bufio.NewWriter(os.Stdout)
+	for _, violation := range violations {
+		_, _ = fmt.Fprintln(out, violation)
+	}
+	if err := out.Flush(); err != nil {
+		return err
+	}
+	if len(violations) > 0 {
+		return fmt.Errorf("author %d has %d violations", v.AuthorID, len(violations))
+	}
+	return nil
+}
+
 func init() {
 	// Limit our memory to 90% of what's free. This affects cache sizes.
 	_, err := memlimit.SetGoMemLimitWithOpts(
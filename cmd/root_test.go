@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/blampe/rreading-glasses/internal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mapExpirer records which keys were expired.
+type mapExpirer struct {
+	mu      sync.Mutex
+	data    map[string][]byte
+	expired []string
+}
+
+func (m *mapExpirer) Get(_ context.Context, key string) ([]byte, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	v, ok := m.data[key]
+	return v, ok
+}
+
+func (m *mapExpirer) Expire(_ context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.expired = append(m.expired, key)
+	return nil
+}
+
+func TestBustCascade(t *testing.T) {
+	// Busting an author also busts its works and editions, unless cascading
+	// is disabled.
+
+	authorBytes := []byte(`{"ForeignId":1,"Works":[{"ForeignId":2,"Books":[{"ForeignId":3}]}]}`)
+
+	cache := func() *mapExpirer {
+		return &mapExpirer{data: map[string][]byte{internal.AuthorKey(1): authorBytes}}
+	}
+
+	c := cache()
+	require.NoError(t, (&Bust{AuthorID: 1, Cascade: false}).bust(t.Context(), c))
+	assert.Equal(t, []string{internal.AuthorKey(1)}, c.expired)
+
+	c = cache()
+	require.NoError(t, (&Bust{AuthorID: 1, Cascade: true, Parallel: 2}).bust(t.Context(), c))
+	assert.ElementsMatch(t, []string{internal.AuthorKey(1), internal.WorkKey(2), internal.BookKey(3)}, c.expired)
+	assert.Equal(t, internal.AuthorKey(1), c.expired[len(c.expired)-1], "author should be busted last")
+}
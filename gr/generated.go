@@ -12,18 +12,19 @@ import (
 
 // BookInfo includes the GraphQL fields of Book requested by the fragment BookInfo.
 type BookInfo struct {
-	Id                     string                                            `json:"id"`
-	LegacyId               int64                                             `json:"legacyId"`
-	Description            string                                            `json:"description"`
-	BookGenres             []BookInfoBookGenresBookGenre                     `json:"bookGenres"`
-	BookSeries             []BookInfoBookSeries                              `json:"bookSeries"`
-	Details                BookInfoDetailsBookDetails                        `json:"details"`
-	ImageUrl               string                                            `json:"imageUrl"`
-	PrimaryContributorEdge BookInfoPrimaryContributorEdgeBookContributorEdge `json:"primaryContributorEdge"`
-	Stats                  BookInfoStatsBookOrWorkStats                      `json:"stats"`
-	Title                  string                                            `json:"title"`
-	TitlePrimary           string                                            `json:"titlePrimary"`
-	WebUrl                 string                                            `json:"webUrl"`
+	Id                        string                                                 `json:"id"`
+	LegacyId                  int64                                                  `json:"legacyId"`
+	Description               string                                                 `json:"description"`
+	BookGenres                []BookInfoBookGenresBookGenre                          `json:"bookGenres"`
+	BookSeries                []BookInfoBookSeries                                   `json:"bookSeries"`
+	Details                   BookInfoDetailsBookDetails                             `json:"details"`
+	ImageUrl                  string                                                 `json:"imageUrl"`
+	PrimaryContributorEdge    BookInfoPrimaryContributorEdgeBookContributorEdge      `json:"primaryContributorEdge"`
+	SecondaryContributorEdges []BookInfoSecondaryContributorEdgesBookContributorEdge `json:"secondaryContributorEdges"`
+	Stats                     BookInfoStatsBookOrWorkStats                           `json:"stats"`
+	Title                     string                                                 `json:"title"`
+	TitlePrimary              string                                                 `json:"titlePrimary"`
+	WebUrl                    string                                                 `json:"webUrl"`
 }
 
 // GetId returns BookInfo.Id, and is useful for accessing the field via an interface.
@@ -52,6 +53,11 @@ func (v *BookInfo) GetPrimaryContributorEdge() BookInfoPrimaryContributorEdgeBoo
 	return v.PrimaryContributorEdge
 }
 
+// GetSecondaryContributorEdges returns BookInfo.SecondaryContributorEdges, and is useful for accessing the field via an interface.
+func (v *BookInfo) GetSecondaryContributorEdges() []BookInfoSecondaryContributorEdgesBookContributorEdge {
+	return v.SecondaryContributorEdges
+}
+
 // GetStats returns BookInfo.Stats, and is useful for accessing the field via an interface.
 func (v *BookInfo) GetStats() BookInfoStatsBookOrWorkStats { return v.Stats }
 
@@ -204,6 +210,30 @@ func (v *BookInfoPrimaryContributorEdgeBookContributorEdgeNodeContributor) GetDe
 	return v.Description
 }
 
+// BookInfoSecondaryContributorEdgesBookContributorEdge includes the requested fields of the GraphQL type BookContributorEdge.
+type BookInfoSecondaryContributorEdgesBookContributorEdge struct {
+	Role string                                                              `json:"role"`
+	Node BookInfoSecondaryContributorEdgesBookContributorEdgeNodeContributor `json:"node"`
+}
+
+// GetRole returns BookInfoSecondaryContributorEdgesBookContributorEdge.Role, and is useful for accessing the field via an interface.
+func (v *BookInfoSecondaryContributorEdgesBookContributorEdge) GetRole() string { return v.Role }
+
+// GetNode returns BookInfoSecondaryContributorEdgesBookContributorEdge.Node, and is useful for accessing the field via an interface.
+func (v *BookInfoSecondaryContributorEdgesBookContributorEdge) GetNode() BookInfoSecondaryContributorEdgesBookContributorEdgeNodeContributor {
+	return v.Node
+}
+
+// BookInfoSecondaryContributorEdgesBookContributorEdgeNodeContributor includes the requested fields of the GraphQL type Contributor.
+type BookInfoSecondaryContributorEdgesBookContributorEdgeNodeContributor struct {
+	LegacyId int64 `json:"legacyId"`
+}
+
+// GetLegacyId returns BookInfoSecondaryContributorEdgesBookContributorEdgeNodeContributor.LegacyId, and is useful for accessing the field via an interface.
+func (v *BookInfoSecondaryContributorEdgesBookContributorEdgeNodeContributor) GetLegacyId() int64 {
+	return v.LegacyId
+}
+
 // BookInfoStatsBookOrWorkStats includes the requested fields of the GraphQL type BookOrWorkStats.
 type BookInfoStatsBookOrWorkStats struct {
 	AverageRating float64 `json:"averageRating"`
@@ -248,8 +278,9 @@ func (v *GetAuthorWorksGetWorksByContributorContributorWorksConnectionEdgesContr
 
 // GetAuthorWorksGetWorksByContributorContributorWorksConnectionEdgesContributorWorksEdgeNodeWork includes the requested fields of the GraphQL type Work.
 type GetAuthorWorksGetWorksByContributorContributorWorksConnectionEdgesContributorWorksEdgeNodeWork struct {
-	Id       string                                                                                                 `json:"id"`
-	BestBook GetAuthorWorksGetWorksByContributorContributorWorksConnectionEdgesContributorWorksEdgeNodeWorkBestBook `json:"bestBook"`
+	Id       string                                                                                                             `json:"id"`
+	Stats    GetAuthorWorksGetWorksByContributorContributorWorksConnectionEdgesContributorWorksEdgeNodeWorkStatsBookOrWorkStats `json:"stats"`
+	BestBook GetAuthorWorksGetWorksByContributorContributorWorksConnectionEdgesContributorWorksEdgeNodeWorkBestBook             `json:"bestBook"`
 }
 
 // GetId returns GetAuthorWorksGetWorksByContributorContributorWorksConnectionEdgesContributorWorksEdgeNodeWork.Id, and is useful for accessing the field via an interface.
@@ -257,6 +288,11 @@ func (v *GetAuthorWorksGetWorksByContributorContributorWorksConnectionEdgesContr
 	return v.Id
 }
 
+// GetStats returns GetAuthorWorksGetWorksByContributorContributorWorksConnectionEdgesContributorWorksEdgeNodeWork.Stats, and is useful for accessing the field via an interface.
+func (v *GetAuthorWorksGetWorksByContributorContributorWorksConnectionEdgesContributorWorksEdgeNodeWork) GetStats() GetAuthorWorksGetWorksByContributorContributorWorksConnectionEdgesContributorWorksEdgeNodeWorkStatsBookOrWorkStats {
+	return v.Stats
+}
+
 // GetBestBook returns GetAuthorWorksGetWorksByContributorContributorWorksConnectionEdgesContributorWorksEdgeNodeWork.BestBook, and is useful for accessing the field via an interface.
 func (v *GetAuthorWorksGetWorksByContributorContributorWorksConnectionEdgesContributorWorksEdgeNodeWork) GetBestBook() GetAuthorWorksGetWorksByContributorContributorWorksConnectionEdgesContributorWorksEdgeNodeWorkBestBook {
 	return v.BestBook
@@ -320,6 +356,22 @@ func (v *GetAuthorWorksGetWorksByContributorContributorWorksConnectionEdgesContr
 	return v.Role
 }
 
+// GetAuthorWorksGetWorksByContributorContributorWorksConnectionEdgesContributorWorksEdgeNodeWorkStatsBookOrWorkStats includes the requested fields of the GraphQL type BookOrWorkStats.
+type GetAuthorWorksGetWorksByContributorContributorWorksConnectionEdgesContributorWorksEdgeNodeWorkStatsBookOrWorkStats struct {
+	AverageRating float64 `json:"averageRating"`
+	RatingsCount  int64   `json:"ratingsCount"`
+}
+
+// GetAverageRating returns GetAuthorWorksGetWorksByContributorContributorWorksConnectionEdgesContributorWorksEdgeNodeWorkStatsBookOrWorkStats.AverageRating, and is useful for accessing the field via an interface.
+func (v *GetAuthorWorksGetWorksByContributorContributorWorksConnectionEdgesContributorWorksEdgeNodeWorkStatsBookOrWorkStats) GetAverageRating() float64 {
+	return v.AverageRating
+}
+
+// GetRatingsCount returns GetAuthorWorksGetWorksByContributorContributorWorksConnectionEdgesContributorWorksEdgeNodeWorkStatsBookOrWorkStats.RatingsCount, and is useful for accessing the field via an interface.
+func (v *GetAuthorWorksGetWorksByContributorContributorWorksConnectionEdgesContributorWorksEdgeNodeWorkStatsBookOrWorkStats) GetRatingsCount() int64 {
+	return v.RatingsCount
+}
+
 // GetAuthorWorksGetWorksByContributorContributorWorksConnectionPageInfo includes the requested fields of the GraphQL type PageInfo.
 type GetAuthorWorksGetWorksByContributorContributorWorksConnectionPageInfo struct {
 	HasNextPage   bool   `json:"hasNextPage"`
@@ -387,6 +439,11 @@ func (v *GetBookGetBookByLegacyIdBook) GetPrimaryContributorEdge() BookInfoPrima
 	return v.BookInfo.PrimaryContributorEdge
 }
 
+// GetSecondaryContributorEdges returns GetBookGetBookByLegacyIdBook.SecondaryContributorEdges, and is useful for accessing the field via an interface.
+func (v *GetBookGetBookByLegacyIdBook) GetSecondaryContributorEdges() []BookInfoSecondaryContributorEdgesBookContributorEdge {
+	return v.BookInfo.SecondaryContributorEdges
+}
+
 // GetStats returns GetBookGetBookByLegacyIdBook.Stats, and is useful for accessing the field via an interface.
 func (v *GetBookGetBookByLegacyIdBook) GetStats() BookInfoStatsBookOrWorkStats {
 	return v.BookInfo.Stats
@@ -445,6 +502,8 @@ type __premarshalGetBookGetBookByLegacyIdBook struct {
 
 	PrimaryContributorEdge BookInfoPrimaryContributorEdgeBookContributorEdge `json:"primaryContributorEdge"`
 
+	SecondaryContributorEdges []BookInfoSecondaryContributorEdgesBookContributorEdge `json:"secondaryContributorEdges"`
+
 	Stats BookInfoStatsBookOrWorkStats `json:"stats"`
 
 	Title string `json:"title"`
@@ -474,6 +533,7 @@ func (v *GetBookGetBookByLegacyIdBook) __premarshalJSON() (*__premarshalGetBookG
 	retval.Details = v.BookInfo.Details
 	retval.ImageUrl = v.BookInfo.ImageUrl
 	retval.PrimaryContributorEdge = v.BookInfo.PrimaryContributorEdge
+	retval.SecondaryContributorEdges = v.BookInfo.SecondaryContributorEdges
 	retval.Stats = v.BookInfo.Stats
 	retval.Title = v.BookInfo.Title
 	retval.TitlePrimary = v.BookInfo.TitlePrimary
@@ -563,6 +623,7 @@ func (v *GetBookGetBookByLegacyIdBookWorkBestBookPrimaryContributorEdgeBookContr
 type GetBookGetBookByLegacyIdBookWorkDetails struct {
 	WebUrl          string  `json:"webUrl"`
 	PublicationTime float64 `json:"publicationTime"`
+	OriginalTitle   string  `json:"originalTitle"`
 }
 
 // GetWebUrl returns GetBookGetBookByLegacyIdBookWorkDetails.WebUrl, and is useful for accessing the field via an interface.
@@ -573,6 +634,9 @@ func (v *GetBookGetBookByLegacyIdBookWorkDetails) GetPublicationTime() float64 {
 	return v.PublicationTime
 }
 
+// GetOriginalTitle returns GetBookGetBookByLegacyIdBookWorkDetails.OriginalTitle, and is useful for accessing the field via an interface.
+func (v *GetBookGetBookByLegacyIdBookWorkDetails) GetOriginalTitle() string { return v.OriginalTitle }
+
 // GetBookGetBookByLegacyIdBookWorkEditionsBooksConnection includes the requested fields of the GraphQL type BooksConnection.
 type GetBookGetBookByLegacyIdBookWorkEditionsBooksConnection struct {
 	Edges []GetBookGetBookByLegacyIdBookWorkEditionsBooksConnectionEdgesBooksEdge `json:"edges"`
@@ -638,6 +702,11 @@ func (v *GetBookGetBookByLegacyIdBookWorkEditionsBooksConnectionEdgesBooksEdgeNo
 	return v.BookInfo.PrimaryContributorEdge
 }
 
+// GetSecondaryContributorEdges returns GetBookGetBookByLegacyIdBookWorkEditionsBooksConnectionEdgesBooksEdgeNodeBook.SecondaryContributorEdges, and is useful for accessing the field via an interface.
+func (v *GetBookGetBookByLegacyIdBookWorkEditionsBooksConnectionEdgesBooksEdgeNodeBook) GetSecondaryContributorEdges() []BookInfoSecondaryContributorEdgesBookContributorEdge {
+	return v.BookInfo.SecondaryContributorEdges
+}
+
 // GetStats returns GetBookGetBookByLegacyIdBookWorkEditionsBooksConnectionEdgesBooksEdgeNodeBook.Stats, and is useful for accessing the field via an interface.
 func (v *GetBookGetBookByLegacyIdBookWorkEditionsBooksConnectionEdgesBooksEdgeNodeBook) GetStats() BookInfoStatsBookOrWorkStats {
 	return v.BookInfo.Stats
@@ -700,6 +769,8 @@ type __premarshalGetBookGetBookByLegacyIdBookWorkEditionsBooksConnectionEdgesBoo
 
 	PrimaryContributorEdge BookInfoPrimaryContributorEdgeBookContributorEdge `json:"primaryContributorEdge"`
 
+	SecondaryContributorEdges []BookInfoSecondaryContributorEdgesBookContributorEdge `json:"secondaryContributorEdges"`
+
 	Stats BookInfoStatsBookOrWorkStats `json:"stats"`
 
 	Title string `json:"title"`
@@ -728,6 +799,7 @@ func (v *GetBookGetBookByLegacyIdBookWorkEditionsBooksConnectionEdgesBooksEdgeNo
 	retval.Details = v.BookInfo.Details
 	retval.ImageUrl = v.BookInfo.ImageUrl
 	retval.PrimaryContributorEdge = v.BookInfo.PrimaryContributorEdge
+	retval.SecondaryContributorEdges = v.BookInfo.SecondaryContributorEdges
 	retval.Stats = v.BookInfo.Stats
 	retval.Title = v.BookInfo.Title
 	retval.TitlePrimary = v.BookInfo.TitlePrimary
@@ -5584,6 +5656,106 @@ func (v *GetRecommendedResponse) GetGetHomeWidgets() GetRecommendedGetHomeWidget
 	return v.GetHomeWidgets
 }
 
+// GetSimilarBooksGetSimilarBooksSimilarBooksConnection includes the requested fields of the GraphQL type SimilarBooksConnection.
+type GetSimilarBooksGetSimilarBooksSimilarBooksConnection struct {
+	Edges []GetSimilarBooksGetSimilarBooksSimilarBooksConnectionEdgesSimilarBooksEdge `json:"edges"`
+}
+
+// GetEdges returns GetSimilarBooksGetSimilarBooksSimilarBooksConnection.Edges, and is useful for accessing the field via an interface.
+func (v *GetSimilarBooksGetSimilarBooksSimilarBooksConnection) GetEdges() []GetSimilarBooksGetSimilarBooksSimilarBooksConnectionEdgesSimilarBooksEdge {
+	return v.Edges
+}
+
+// GetSimilarBooksGetSimilarBooksSimilarBooksConnectionEdgesSimilarBooksEdge includes the requested fields of the GraphQL type SimilarBooksEdge.
+type GetSimilarBooksGetSimilarBooksSimilarBooksConnectionEdgesSimilarBooksEdge struct {
+	Node GetSimilarBooksGetSimilarBooksSimilarBooksConnectionEdgesSimilarBooksEdgeNodeBook `json:"node"`
+}
+
+// GetNode returns GetSimilarBooksGetSimilarBooksSimilarBooksConnectionEdgesSimilarBooksEdge.Node, and is useful for accessing the field via an interface.
+func (v *GetSimilarBooksGetSimilarBooksSimilarBooksConnectionEdgesSimilarBooksEdge) GetNode() GetSimilarBooksGetSimilarBooksSimilarBooksConnectionEdgesSimilarBooksEdgeNodeBook {
+	return v.Node
+}
+
+// GetSimilarBooksGetSimilarBooksSimilarBooksConnectionEdgesSimilarBooksEdgeNodeBook includes the requested fields of the GraphQL type Book.
+type GetSimilarBooksGetSimilarBooksSimilarBooksConnectionEdgesSimilarBooksEdgeNodeBook struct {
+	Work GetSimilarBooksGetSimilarBooksSimilarBooksConnectionEdgesSimilarBooksEdgeNodeBookWork `json:"work"`
+}
+
+// GetWork returns GetSimilarBooksGetSimilarBooksSimilarBooksConnectionEdgesSimilarBooksEdgeNodeBook.Work, and is useful for accessing the field via an interface.
+func (v *GetSimilarBooksGetSimilarBooksSimilarBooksConnectionEdgesSimilarBooksEdgeNodeBook) GetWork() GetSimilarBooksGetSimilarBooksSimilarBooksConnectionEdgesSimilarBooksEdgeNodeBookWork {
+	return v.Work
+}
+
+// GetSimilarBooksGetSimilarBooksSimilarBooksConnectionEdgesSimilarBooksEdgeNodeBookWork includes the requested fields of the GraphQL type Work.
+type GetSimilarBooksGetSimilarBooksSimilarBooksConnectionEdgesSimilarBooksEdgeNodeBookWork struct {
+	LegacyId int64 `json:"legacyId"`
+}
+
+// GetLegacyId returns GetSimilarBooksGetSimilarBooksSimilarBooksConnectionEdgesSimilarBooksEdgeNodeBookWork.LegacyId, and is useful for accessing the field via an interface.
+func (v *GetSimilarBooksGetSimilarBooksSimilarBooksConnectionEdgesSimilarBooksEdgeNodeBookWork) GetLegacyId() int64 {
+	return v.LegacyId
+}
+
+// GetSimilarBooksResponse is returned by GetSimilarBooks on success.
+type GetSimilarBooksResponse struct {
+	GetSimilarBooks GetSimilarBooksGetSimilarBooksSimilarBooksConnection `json:"getSimilarBooks"`
+}
+
+// GetGetSimilarBooks returns GetSimilarBooksResponse.GetSimilarBooks, and is useful for accessing the field via an interface.
+func (v *GetSimilarBooksResponse) GetGetSimilarBooks() GetSimilarBooksGetSimilarBooksSimilarBooksConnection {
+	return v.GetSimilarBooks
+}
+
+// GetTaggedBooksGetTaggedBooksTaggedBooksConnection includes the requested fields of the GraphQL type TaggedBooksConnection.
+type GetTaggedBooksGetTaggedBooksTaggedBooksConnection struct {
+	Edges []GetTaggedBooksGetTaggedBooksTaggedBooksConnectionEdgesTaggedBookEdge `json:"edges"`
+}
+
+// GetEdges returns GetTaggedBooksGetTaggedBooksTaggedBooksConnection.Edges, and is useful for accessing the field via an interface.
+func (v *GetTaggedBooksGetTaggedBooksTaggedBooksConnection) GetEdges() []GetTaggedBooksGetTaggedBooksTaggedBooksConnectionEdgesTaggedBookEdge {
+	return v.Edges
+}
+
+// GetTaggedBooksGetTaggedBooksTaggedBooksConnectionEdgesTaggedBookEdge includes the requested fields of the GraphQL type TaggedBookEdge.
+type GetTaggedBooksGetTaggedBooksTaggedBooksConnectionEdgesTaggedBookEdge struct {
+	Node GetTaggedBooksGetTaggedBooksTaggedBooksConnectionEdgesTaggedBookEdgeNodeBook `json:"node"`
+}
+
+// GetNode returns GetTaggedBooksGetTaggedBooksTaggedBooksConnectionEdgesTaggedBookEdge.Node, and is useful for accessing the field via an interface.
+func (v *GetTaggedBooksGetTaggedBooksTaggedBooksConnectionEdgesTaggedBookEdge) GetNode() GetTaggedBooksGetTaggedBooksTaggedBooksConnectionEdgesTaggedBookEdgeNodeBook {
+	return v.Node
+}
+
+// GetTaggedBooksGetTaggedBooksTaggedBooksConnectionEdgesTaggedBookEdgeNodeBook includes the requested fields of the GraphQL type Book.
+type GetTaggedBooksGetTaggedBooksTaggedBooksConnectionEdgesTaggedBookEdgeNodeBook struct {
+	Work GetTaggedBooksGetTaggedBooksTaggedBooksConnectionEdgesTaggedBookEdgeNodeBookWork `json:"work"`
+}
+
+// GetWork returns GetTaggedBooksGetTaggedBooksTaggedBooksConnectionEdgesTaggedBookEdgeNodeBook.Work, and is useful for accessing the field via an interface.
+func (v *GetTaggedBooksGetTaggedBooksTaggedBooksConnectionEdgesTaggedBookEdgeNodeBook) GetWork() GetTaggedBooksGetTaggedBooksTaggedBooksConnectionEdgesTaggedBookEdgeNodeBookWork {
+	return v.Work
+}
+
+// GetTaggedBooksGetTaggedBooksTaggedBooksConnectionEdgesTaggedBookEdgeNodeBookWork includes the requested fields of the GraphQL type Work.
+type GetTaggedBooksGetTaggedBooksTaggedBooksConnectionEdgesTaggedBookEdgeNodeBookWork struct {
+	LegacyId int64 `json:"legacyId"`
+}
+
+// GetLegacyId returns GetTaggedBooksGetTaggedBooksTaggedBooksConnectionEdgesTaggedBookEdgeNodeBookWork.LegacyId, and is useful for accessing the field via an interface.
+func (v *GetTaggedBooksGetTaggedBooksTaggedBooksConnectionEdgesTaggedBookEdgeNodeBookWork) GetLegacyId() int64 {
+	return v.LegacyId
+}
+
+// GetTaggedBooksResponse is returned by GetTaggedBooks on success.
+type GetTaggedBooksResponse struct {
+	GetTaggedBooks GetTaggedBooksGetTaggedBooksTaggedBooksConnection `json:"getTaggedBooks"`
+}
+
+// GetGetTaggedBooks returns GetTaggedBooksResponse.GetTaggedBooks, and is useful for accessing the field via an interface.
+func (v *GetTaggedBooksResponse) GetGetTaggedBooks() GetTaggedBooksGetTaggedBooksTaggedBooksConnection {
+	return v.GetTaggedBooks
+}
+
 type GetWorksByContributorInput struct {
 	Id string `json:"id"`
 }
@@ -5718,9 +5890,12 @@ func (v *SearchGetSearchSuggestionsSearchResultsConnectionEdgesSearchBookEdge) G
 
 // SearchGetSearchSuggestionsSearchResultsConnectionEdgesSearchBookEdgeNodeBook includes the requested fields of the GraphQL type Book.
 type SearchGetSearchSuggestionsSearchResultsConnectionEdgesSearchBookEdgeNodeBook struct {
-	Work     SearchGetSearchSuggestionsSearchResultsConnectionEdgesSearchBookEdgeNodeBookWork `json:"work"`
-	Title    string                                                                           `json:"title"`
-	LegacyId int64                                                                            `json:"legacyId"`
+	Work         SearchGetSearchSuggestionsSearchResultsConnectionEdgesSearchBookEdgeNodeBookWork                 `json:"work"`
+	Title        string                                                                                           `json:"title"`
+	TitlePrimary string                                                                                           `json:"titlePrimary"`
+	LegacyId     int64                                                                                            `json:"legacyId"`
+	Stats        SearchGetSearchSuggestionsSearchResultsConnectionEdgesSearchBookEdgeNodeBookStatsBookOrWorkStats `json:"stats"`
+	Details      SearchGetSearchSuggestionsSearchResultsConnectionEdgesSearchBookEdgeNodeBookDetails              `json:"details"`
 }
 
 // GetWork returns SearchGetSearchSuggestionsSearchResultsConnectionEdgesSearchBookEdgeNodeBook.Work, and is useful for accessing the field via an interface.
@@ -5733,11 +5908,46 @@ func (v *SearchGetSearchSuggestionsSearchResultsConnectionEdgesSearchBookEdgeNod
 	return v.Title
 }
 
+// GetTitlePrimary returns SearchGetSearchSuggestionsSearchResultsConnectionEdgesSearchBookEdgeNodeBook.TitlePrimary, and is useful for accessing the field via an interface.
+func (v *SearchGetSearchSuggestionsSearchResultsConnectionEdgesSearchBookEdgeNodeBook) GetTitlePrimary() string {
+	return v.TitlePrimary
+}
+
 // GetLegacyId returns SearchGetSearchSuggestionsSearchResultsConnectionEdgesSearchBookEdgeNodeBook.LegacyId, and is useful for accessing the field via an interface.
 func (v *SearchGetSearchSuggestionsSearchResultsConnectionEdgesSearchBookEdgeNodeBook) GetLegacyId() int64 {
 	return v.LegacyId
 }
 
+// GetStats returns SearchGetSearchSuggestionsSearchResultsConnectionEdgesSearchBookEdgeNodeBook.Stats, and is useful for accessing the field via an interface.
+func (v *SearchGetSearchSuggestionsSearchResultsConnectionEdgesSearchBookEdgeNodeBook) GetStats() SearchGetSearchSuggestionsSearchResultsConnectionEdgesSearchBookEdgeNodeBookStatsBookOrWorkStats {
+	return v.Stats
+}
+
+// GetDetails returns SearchGetSearchSuggestionsSearchResultsConnectionEdgesSearchBookEdgeNodeBook.Details, and is useful for accessing the field via an interface.
+func (v *SearchGetSearchSuggestionsSearchResultsConnectionEdgesSearchBookEdgeNodeBook) GetDetails() SearchGetSearchSuggestionsSearchResultsConnectionEdgesSearchBookEdgeNodeBookDetails {
+	return v.Details
+}
+
+// SearchGetSearchSuggestionsSearchResultsConnectionEdgesSearchBookEdgeNodeBookDetails includes the requested fields of the GraphQL type BookDetails.
+type SearchGetSearchSuggestionsSearchResultsConnectionEdgesSearchBookEdgeNodeBookDetails struct {
+	PublicationTime float64 `json:"publicationTime"`
+}
+
+// GetPublicationTime returns SearchGetSearchSuggestionsSearchResultsConnectionEdgesSearchBookEdgeNodeBookDetails.PublicationTime, and is useful for accessing the field via an interface.
+func (v *SearchGetSearchSuggestionsSearchResultsConnectionEdgesSearchBookEdgeNodeBookDetails) GetPublicationTime() float64 {
+	return v.PublicationTime
+}
+
+// SearchGetSearchSuggestionsSearchResultsConnectionEdgesSearchBookEdgeNodeBookStatsBookOrWorkStats includes the requested fields of the GraphQL type BookOrWorkStats.
+type SearchGetSearchSuggestionsSearchResultsConnectionEdgesSearchBookEdgeNodeBookStatsBookOrWorkStats struct {
+	RatingsCount int64 `json:"ratingsCount"`
+}
+
+// GetRatingsCount returns SearchGetSearchSuggestionsSearchResultsConnectionEdgesSearchBookEdgeNodeBookStatsBookOrWorkStats.RatingsCount, and is useful for accessing the field via an interface.
+func (v *SearchGetSearchSuggestionsSearchResultsConnectionEdgesSearchBookEdgeNodeBookStatsBookOrWorkStats) GetRatingsCount() int64 {
+	return v.RatingsCount
+}
+
 // SearchGetSearchSuggestionsSearchResultsConnectionEdgesSearchBookEdgeNodeBookWork includes the requested fields of the GraphQL type Work.
 type SearchGetSearchSuggestionsSearchResultsConnectionEdgesSearchBookEdgeNodeBookWork struct {
 	LegacyId int64                                                                                    `json:"legacyId"`
@@ -5893,6 +6103,30 @@ func (v *__GetEditionsInput) GetWorkId() string { return v.WorkId }
 // GetPagination returns __GetEditionsInput.Pagination, and is useful for accessing the field via an interface.
 func (v *__GetEditionsInput) GetPagination() PaginationInput { return v.Pagination }
 
+// __GetSimilarBooksInput is used internally by genqlient
+type __GetSimilarBooksInput struct {
+	Id         string          `json:"id"`
+	Pagination PaginationInput `json:"pagination"`
+}
+
+// GetId returns __GetSimilarBooksInput.Id, and is useful for accessing the field via an interface.
+func (v *__GetSimilarBooksInput) GetId() string { return v.Id }
+
+// GetPagination returns __GetSimilarBooksInput.Pagination, and is useful for accessing the field via an interface.
+func (v *__GetSimilarBooksInput) GetPagination() PaginationInput { return v.Pagination }
+
+// __GetTaggedBooksInput is used internally by genqlient
+type __GetTaggedBooksInput struct {
+	TagName    string          `json:"tagName"`
+	Pagination PaginationInput `json:"pagination"`
+}
+
+// GetTagName returns __GetTaggedBooksInput.TagName, and is useful for accessing the field via an interface.
+func (v *__GetTaggedBooksInput) GetTagName() string { return v.TagName }
+
+// GetPagination returns __GetTaggedBooksInput.Pagination, and is useful for accessing the field via an interface.
+func (v *__GetTaggedBooksInput) GetPagination() PaginationInput { return v.Pagination }
+
 // __SearchInput is used internally by genqlient
 type __SearchInput struct {
 	Query string `json:"query"`
@@ -5908,6 +6142,10 @@ query GetAuthorWorks ($getWorksByContributorInput: GetWorksByContributorInput!,
 		edges {
 			node {
 				id
+				stats {
+					averageRating
+					ratingsCount
+				}
 				bestBook {
 					legacyId
 					primaryContributorEdge {
@@ -5968,6 +6206,7 @@ query GetBook ($legacyId: Int!) {
 			details {
 				webUrl
 				publicationTime
+				originalTitle
 			}
 			bestBook {
 				legacyId
@@ -6030,6 +6269,12 @@ fragment BookInfo on Book {
 			description
 		}
 	}
+	secondaryContributorEdges {
+		role
+		node {
+			legacyId
+		}
+	}
 	stats {
 		averageRating
 		ratingsCount
@@ -6155,6 +6400,90 @@ func GetRecommended(
 	return data_, err_
 }
 
+// The query executed by GetSimilarBooks.
+const GetSimilarBooks_Operation = `
+query GetSimilarBooks ($id: ID!, $pagination: PaginationInput!) {
+	getSimilarBooks(id: $id, pagination: $pagination) {
+		edges {
+			node {
+				work {
+					legacyId
+				}
+			}
+		}
+	}
+}
+`
+
+func GetSimilarBooks(
+	ctx_ context.Context,
+	client_ graphql.Client,
+	id string,
+	pagination PaginationInput,
+) (data_ *GetSimilarBooksResponse, err_ error) {
+	req_ := &graphql.Request{
+		OpName: "GetSimilarBooks",
+		Query:  GetSimilarBooks_Operation,
+		Variables: &__GetSimilarBooksInput{
+			Id:         id,
+			Pagination: pagination,
+		},
+	}
+
+	data_ = &GetSimilarBooksResponse{}
+	resp_ := &graphql.Response{Data: data_}
+
+	err_ = client_.MakeRequest(
+		ctx_,
+		req_,
+		resp_,
+	)
+
+	return data_, err_
+}
+
+// The query executed by GetTaggedBooks.
+const GetTaggedBooks_Operation = `
+query GetTaggedBooks ($tagName: String!, $pagination: PaginationInput!) {
+	getTaggedBooks(tagName: $tagName, pagination: $pagination) {
+		edges {
+			node {
+				work {
+					legacyId
+				}
+			}
+		}
+	}
+}
+`
+
+func GetTaggedBooks(
+	ctx_ context.Context,
+	client_ graphql.Client,
+	tagName string,
+	pagination PaginationInput,
+) (data_ *GetTaggedBooksResponse, err_ error) {
+	req_ := &graphql.Request{
+		OpName: "GetTaggedBooks",
+		Query:  GetTaggedBooks_Operation,
+		Variables: &__GetTaggedBooksInput{
+			TagName:    tagName,
+			Pagination: pagination,
+		},
+	}
+
+	data_ = &GetTaggedBooksResponse{}
+	resp_ := &graphql.Response{Data: data_}
+
+	err_ = client_.MakeRequest(
+		ctx_,
+		req_,
+		resp_,
+	)
+
+	return data_, err_
+}
+
 // The query executed by Search.
 const Search_Operation = `
 query Search ($query: String!) {
@@ -6176,7 +6505,14 @@ query Search ($query: String!) {
 						}
 					}
 					title
+					titlePrimary
 					legacyId
+					stats {
+						ratingsCount
+					}
+					details {
+						publicationTime
+					}
 				}
 			}
 		}
@@ -250,7 +250,12 @@ func (v *DefaultEditionsContributions) __premarshalJSON() (*__premarshalDefaultE
 //
 // columns and relationships of "editions"
 type DefaultEditionsDefault_audio_editionEditions struct {
-	Id int64 `json:"id"`
+	Id            int64           `json:"id"`
+	Pages         int64           `json:"pages"`
+	Audio_seconds int64           `json:"audio_seconds"`
+	Cached_image  json.RawMessage `json:"cached_image"`
+	// An object relationship
+	Language DefaultEditionsDefault_audio_editionEditionsLanguageLanguages `json:"language"`
 	// An array relationship
 	Contributions []DefaultEditionsDefault_audio_editionEditionsContributions `json:"contributions"`
 }
@@ -258,6 +263,24 @@ type DefaultEditionsDefault_audio_editionEditions struct {
 // GetId returns DefaultEditionsDefault_audio_editionEditions.Id, and is useful for accessing the field via an interface.
 func (v *DefaultEditionsDefault_audio_editionEditions) GetId() int64 { return v.Id }
 
+// GetPages returns DefaultEditionsDefault_audio_editionEditions.Pages, and is useful for accessing the field via an interface.
+func (v *DefaultEditionsDefault_audio_editionEditions) GetPages() int64 { return v.Pages }
+
+// GetAudio_seconds returns DefaultEditionsDefault_audio_editionEditions.Audio_seconds, and is useful for accessing the field via an interface.
+func (v *DefaultEditionsDefault_audio_editionEditions) GetAudio_seconds() int64 {
+	return v.Audio_seconds
+}
+
+// GetCached_image returns DefaultEditionsDefault_audio_editionEditions.Cached_image, and is useful for accessing the field via an interface.
+func (v *DefaultEditionsDefault_audio_editionEditions) GetCached_image() json.RawMessage {
+	return v.Cached_image
+}
+
+// GetLanguage returns DefaultEditionsDefault_audio_editionEditions.Language, and is useful for accessing the field via an interface.
+func (v *DefaultEditionsDefault_audio_editionEditions) GetLanguage() DefaultEditionsDefault_audio_editionEditionsLanguageLanguages {
+	return v.Language
+}
+
 // GetContributions returns DefaultEditionsDefault_audio_editionEditions.Contributions, and is useful for accessing the field via an interface.
 func (v *DefaultEditionsDefault_audio_editionEditions) GetContributions() []DefaultEditionsDefault_audio_editionEditionsContributions {
 	return v.Contributions
@@ -328,12 +351,30 @@ func (v *DefaultEditionsDefault_audio_editionEditionsContributions) __premarshal
 	return &retval, nil
 }
 
+// DefaultEditionsDefault_audio_editionEditionsLanguageLanguages includes the requested fields of the GraphQL type languages.
+// The GraphQL type's documentation follows.
+//
+// columns and relationships of "languages"
+type DefaultEditionsDefault_audio_editionEditionsLanguageLanguages struct {
+	Code3 string `json:"code3"`
+}
+
+// GetCode3 returns DefaultEditionsDefault_audio_editionEditionsLanguageLanguages.Code3, and is useful for accessing the field via an interface.
+func (v *DefaultEditionsDefault_audio_editionEditionsLanguageLanguages) GetCode3() string {
+	return v.Code3
+}
+
 // DefaultEditionsDefault_cover_editionEditions includes the requested fields of the GraphQL type editions.
 // The GraphQL type's documentation follows.
 //
 // columns and relationships of "editions"
 type DefaultEditionsDefault_cover_editionEditions struct {
-	Id int64 `json:"id"`
+	Id            int64           `json:"id"`
+	Pages         int64           `json:"pages"`
+	Audio_seconds int64           `json:"audio_seconds"`
+	Cached_image  json.RawMessage `json:"cached_image"`
+	// An object relationship
+	Language DefaultEditionsDefault_cover_editionEditionsLanguageLanguages `json:"language"`
 	// An array relationship
 	Contributions []DefaultEditionsDefault_cover_editionEditionsContributions `json:"contributions"`
 }
@@ -341,6 +382,24 @@ type DefaultEditionsDefault_cover_editionEditions struct {
 // GetId returns DefaultEditionsDefault_cover_editionEditions.Id, and is useful for accessing the field via an interface.
 func (v *DefaultEditionsDefault_cover_editionEditions) GetId() int64 { return v.Id }
 
+// GetPages returns DefaultEditionsDefault_cover_editionEditions.Pages, and is useful for accessing the field via an interface.
+func (v *DefaultEditionsDefault_cover_editionEditions) GetPages() int64 { return v.Pages }
+
+// GetAudio_seconds returns DefaultEditionsDefault_cover_editionEditions.Audio_seconds, and is useful for accessing the field via an interface.
+func (v *DefaultEditionsDefault_cover_editionEditions) GetAudio_seconds() int64 {
+	return v.Audio_seconds
+}
+
+// GetCached_image returns DefaultEditionsDefault_cover_editionEditions.Cached_image, and is useful for accessing the field via an interface.
+func (v *DefaultEditionsDefault_cover_editionEditions) GetCached_image() json.RawMessage {
+	return v.Cached_image
+}
+
+// GetLanguage returns DefaultEditionsDefault_cover_editionEditions.Language, and is useful for accessing the field via an interface.
+func (v *DefaultEditionsDefault_cover_editionEditions) GetLanguage() DefaultEditionsDefault_cover_editionEditionsLanguageLanguages {
+	return v.Language
+}
+
 // GetContributions returns DefaultEditionsDefault_cover_editionEditions.Contributions, and is useful for accessing the field via an interface.
 func (v *DefaultEditionsDefault_cover_editionEditions) GetContributions() []DefaultEditionsDefault_cover_editionEditionsContributions {
 	return v.Contributions
@@ -411,12 +470,30 @@ func (v *DefaultEditionsDefault_cover_editionEditionsContributions) __premarshal
 	return &retval, nil
 }
 
+// DefaultEditionsDefault_cover_editionEditionsLanguageLanguages includes the requested fields of the GraphQL type languages.
+// The GraphQL type's documentation follows.
+//
+// columns and relationships of "languages"
+type DefaultEditionsDefault_cover_editionEditionsLanguageLanguages struct {
+	Code3 string `json:"code3"`
+}
+
+// GetCode3 returns DefaultEditionsDefault_cover_editionEditionsLanguageLanguages.Code3, and is useful for accessing the field via an interface.
+func (v *DefaultEditionsDefault_cover_editionEditionsLanguageLanguages) GetCode3() string {
+	return v.Code3
+}
+
 // DefaultEditionsDefault_ebook_editionEditions includes the requested fields of the GraphQL type editions.
 // The GraphQL type's documentation follows.
 //
 // columns and relationships of "editions"
 type DefaultEditionsDefault_ebook_editionEditions struct {
-	Id int64 `json:"id"`
+	Id            int64           `json:"id"`
+	Pages         int64           `json:"pages"`
+	Audio_seconds int64           `json:"audio_seconds"`
+	Cached_image  json.RawMessage `json:"cached_image"`
+	// An object relationship
+	Language DefaultEditionsDefault_ebook_editionEditionsLanguageLanguages `json:"language"`
 	// An array relationship
 	Contributions []DefaultEditionsDefault_ebook_editionEditionsContributions `json:"contributions"`
 }
@@ -424,6 +501,24 @@ type DefaultEditionsDefault_ebook_editionEditions struct {
 // GetId returns DefaultEditionsDefault_ebook_editionEditions.Id, and is useful for accessing the field via an interface.
 func (v *DefaultEditionsDefault_ebook_editionEditions) GetId() int64 { return v.Id }
 
+// GetPages returns DefaultEditionsDefault_ebook_editionEditions.Pages, and is useful for accessing the field via an interface.
+func (v *DefaultEditionsDefault_ebook_editionEditions) GetPages() int64 { return v.Pages }
+
+// GetAudio_seconds returns DefaultEditionsDefault_ebook_editionEditions.Audio_seconds, and is useful for accessing the field via an interface.
+func (v *DefaultEditionsDefault_ebook_editionEditions) GetAudio_seconds() int64 {
+	return v.Audio_seconds
+}
+
+// GetCached_image returns DefaultEditionsDefault_ebook_editionEditions.Cached_image, and is useful for accessing the field via an interface.
+func (v *DefaultEditionsDefault_ebook_editionEditions) GetCached_image() json.RawMessage {
+	return v.Cached_image
+}
+
+// GetLanguage returns DefaultEditionsDefault_ebook_editionEditions.Language, and is useful for accessing the field via an interface.
+func (v *DefaultEditionsDefault_ebook_editionEditions) GetLanguage() DefaultEditionsDefault_ebook_editionEditionsLanguageLanguages {
+	return v.Language
+}
+
 // GetContributions returns DefaultEditionsDefault_ebook_editionEditions.Contributions, and is useful for accessing the field via an interface.
 func (v *DefaultEditionsDefault_ebook_editionEditions) GetContributions() []DefaultEditionsDefault_ebook_editionEditionsContributions {
 	return v.Contributions
@@ -494,12 +589,30 @@ func (v *DefaultEditionsDefault_ebook_editionEditionsContributions) __premarshal
 	return &retval, nil
 }
 
+// DefaultEditionsDefault_ebook_editionEditionsLanguageLanguages includes the requested fields of the GraphQL type languages.
+// The GraphQL type's documentation follows.
+//
+// columns and relationships of "languages"
+type DefaultEditionsDefault_ebook_editionEditionsLanguageLanguages struct {
+	Code3 string `json:"code3"`
+}
+
+// GetCode3 returns DefaultEditionsDefault_ebook_editionEditionsLanguageLanguages.Code3, and is useful for accessing the field via an interface.
+func (v *DefaultEditionsDefault_ebook_editionEditionsLanguageLanguages) GetCode3() string {
+	return v.Code3
+}
+
 // DefaultEditionsDefault_physical_editionEditions includes the requested fields of the GraphQL type editions.
 // The GraphQL type's documentation follows.
 //
 // columns and relationships of "editions"
 type DefaultEditionsDefault_physical_editionEditions struct {
-	Id int64 `json:"id"`
+	Id            int64           `json:"id"`
+	Pages         int64           `json:"pages"`
+	Audio_seconds int64           `json:"audio_seconds"`
+	Cached_image  json.RawMessage `json:"cached_image"`
+	// An object relationship
+	Language DefaultEditionsDefault_physical_editionEditionsLanguageLanguages `json:"language"`
 	// An array relationship
 	Contributions []DefaultEditionsDefault_physical_editionEditionsContributions `json:"contributions"`
 }
@@ -507,6 +620,24 @@ type DefaultEditionsDefault_physical_editionEditions struct {
 // GetId returns DefaultEditionsDefault_physical_editionEditions.Id, and is useful for accessing the field via an interface.
 func (v *DefaultEditionsDefault_physical_editionEditions) GetId() int64 { return v.Id }
 
+// GetPages returns DefaultEditionsDefault_physical_editionEditions.Pages, and is useful for accessing the field via an interface.
+func (v *DefaultEditionsDefault_physical_editionEditions) GetPages() int64 { return v.Pages }
+
+// GetAudio_seconds returns DefaultEditionsDefault_physical_editionEditions.Audio_seconds, and is useful for accessing the field via an interface.
+func (v *DefaultEditionsDefault_physical_editionEditions) GetAudio_seconds() int64 {
+	return v.Audio_seconds
+}
+
+// GetCached_image returns DefaultEditionsDefault_physical_editionEditions.Cached_image, and is useful for accessing the field via an interface.
+func (v *DefaultEditionsDefault_physical_editionEditions) GetCached_image() json.RawMessage {
+	return v.Cached_image
+}
+
+// GetLanguage returns DefaultEditionsDefault_physical_editionEditions.Language, and is useful for accessing the field via an interface.
+func (v *DefaultEditionsDefault_physical_editionEditions) GetLanguage() DefaultEditionsDefault_physical_editionEditionsLanguageLanguages {
+	return v.Language
+}
+
 // GetContributions returns DefaultEditionsDefault_physical_editionEditions.Contributions, and is useful for accessing the field via an interface.
 func (v *DefaultEditionsDefault_physical_editionEditions) GetContributions() []DefaultEditionsDefault_physical_editionEditionsContributions {
 	return v.Contributions
@@ -577,17 +708,110 @@ func (v *DefaultEditionsDefault_physical_editionEditionsContributions) __premars
 	return &retval, nil
 }
 
+// DefaultEditionsDefault_physical_editionEditionsLanguageLanguages includes the requested fields of the GraphQL type languages.
+// The GraphQL type's documentation follows.
+//
+// columns and relationships of "languages"
+type DefaultEditionsDefault_physical_editionEditionsLanguageLanguages struct {
+	Code3 string `json:"code3"`
+}
+
+// GetCode3 returns DefaultEditionsDefault_physical_editionEditionsLanguageLanguages.Code3, and is useful for accessing the field via an interface.
+func (v *DefaultEditionsDefault_physical_editionEditionsLanguageLanguages) GetCode3() string {
+	return v.Code3
+}
+
 // DefaultEditionsFallbackEditions includes the requested fields of the GraphQL type editions.
 // The GraphQL type's documentation follows.
 //
 // columns and relationships of "editions"
 type DefaultEditionsFallbackEditions struct {
-	Id int64 `json:"id"`
+	Id            int64 `json:"id"`
+	Pages         int64 `json:"pages"`
+	Audio_seconds int64 `json:"audio_seconds"`
+	// An array relationship
+	Contributions []DefaultEditionsFallbackEditionsContributions `json:"contributions"`
 }
 
 // GetId returns DefaultEditionsFallbackEditions.Id, and is useful for accessing the field via an interface.
 func (v *DefaultEditionsFallbackEditions) GetId() int64 { return v.Id }
 
+// GetPages returns DefaultEditionsFallbackEditions.Pages, and is useful for accessing the field via an interface.
+func (v *DefaultEditionsFallbackEditions) GetPages() int64 { return v.Pages }
+
+// GetAudio_seconds returns DefaultEditionsFallbackEditions.Audio_seconds, and is useful for accessing the field via an interface.
+func (v *DefaultEditionsFallbackEditions) GetAudio_seconds() int64 { return v.Audio_seconds }
+
+// GetContributions returns DefaultEditionsFallbackEditions.Contributions, and is useful for accessing the field via an interface.
+func (v *DefaultEditionsFallbackEditions) GetContributions() []DefaultEditionsFallbackEditionsContributions {
+	return v.Contributions
+}
+
+// DefaultEditionsFallbackEditionsContributions includes the requested fields of the GraphQL type contributions.
+// The GraphQL type's documentation follows.
+//
+// columns and relationships of "contributions"
+type DefaultEditionsFallbackEditionsContributions struct {
+	Contributions `json:"-"`
+}
+
+// GetContribution returns DefaultEditionsFallbackEditionsContributions.Contribution, and is useful for accessing the field via an interface.
+func (v *DefaultEditionsFallbackEditionsContributions) GetContribution() string {
+	return v.Contributions.Contribution
+}
+
+// GetAuthor returns DefaultEditionsFallbackEditionsContributions.Author, and is useful for accessing the field via an interface.
+func (v *DefaultEditionsFallbackEditionsContributions) GetAuthor() ContributionsAuthorAuthors {
+	return v.Contributions.Author
+}
+
+func (v *DefaultEditionsFallbackEditionsContributions) UnmarshalJSON(b []byte) error {
+
+	if string(b) == "null" {
+		return nil
+	}
+
+	var firstPass struct {
+		*DefaultEditionsFallbackEditionsContributions
+		graphql.NoUnmarshalJSON
+	}
+	firstPass.DefaultEditionsFallbackEditionsContributions = v
+
+	err := json.Unmarshal(b, &firstPass)
+	if err != nil {
+		return err
+	}
+
+	err = json.Unmarshal(
+		b, &v.Contributions)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+type __premarshalDefaultEditionsFallbackEditionsContributions struct {
+	Contribution string `json:"contribution"`
+
+	Author ContributionsAuthorAuthors `json:"author"`
+}
+
+func (v *DefaultEditionsFallbackEditionsContributions) MarshalJSON() ([]byte, error) {
+	premarshaled, err := v.__premarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(premarshaled)
+}
+
+func (v *DefaultEditionsFallbackEditionsContributions) __premarshalJSON() (*__premarshalDefaultEditionsFallbackEditionsContributions, error) {
+	var retval __premarshalDefaultEditionsFallbackEditionsContributions
+
+	retval.Contribution = v.Contributions.Contribution
+	retval.Author = v.Contributions.Author
+	return &retval, nil
+}
+
 // EditionInfo includes the GraphQL fields of editions requested by the fragment EditionInfo.
 // The GraphQL type's documentation follows.
 //
@@ -1389,6 +1613,26 @@ func (v *GetEditionResponse) GetEditions_by_pk() GetEditionEditions_by_pkEdition
 	return v.Editions_by_pk
 }
 
+// GetGenreRecommendedBooks includes the requested fields of the GraphQL type books.
+// The GraphQL type's documentation follows.
+//
+// columns and relationships of "books"
+type GetGenreRecommendedBooks struct {
+	Id int64 `json:"id"`
+}
+
+// GetId returns GetGenreRecommendedBooks.Id, and is useful for accessing the field via an interface.
+func (v *GetGenreRecommendedBooks) GetId() int64 { return v.Id }
+
+// GetGenreRecommendedResponse is returned by GetGenreRecommended on success.
+type GetGenreRecommendedResponse struct {
+	// An array relationship
+	Books []GetGenreRecommendedBooks `json:"books"`
+}
+
+// GetBooks returns GetGenreRecommendedResponse.Books, and is useful for accessing the field via an interface.
+func (v *GetGenreRecommendedResponse) GetBooks() []GetGenreRecommendedBooks { return v.Books }
+
 // GetRecommendedBooks_trendingTrendingBookType includes the requested fields of the GraphQL type TrendingBookType.
 type GetRecommendedBooks_trendingTrendingBookType struct {
 	WorkIDs []int64 `json:"workIDs"`
@@ -1456,6 +1700,8 @@ type GetSeriesSeries_by_pkSeriesBook_series struct {
 	Details  string  `json:"details"`
 	Position float32 `json:"position"`
 	Featured bool    `json:"featured"`
+	// An object relationship
+	Book GetSeriesSeries_by_pkSeriesBook_seriesBookBooks `json:"book"`
 }
 
 // GetBook_id returns GetSeriesSeries_by_pkSeriesBook_series.Book_id, and is useful for accessing the field via an interface.
@@ -1470,6 +1716,24 @@ func (v *GetSeriesSeries_by_pkSeriesBook_series) GetPosition() float32 { return
 // GetFeatured returns GetSeriesSeries_by_pkSeriesBook_series.Featured, and is useful for accessing the field via an interface.
 func (v *GetSeriesSeries_by_pkSeriesBook_series) GetFeatured() bool { return v.Featured }
 
+// GetBook returns GetSeriesSeries_by_pkSeriesBook_series.Book, and is useful for accessing the field via an interface.
+func (v *GetSeriesSeries_by_pkSeriesBook_series) GetBook() GetSeriesSeries_by_pkSeriesBook_seriesBookBooks {
+	return v.Book
+}
+
+// GetSeriesSeries_by_pkSeriesBook_seriesBookBooks includes the requested fields of the GraphQL type books.
+// The GraphQL type's documentation follows.
+//
+// columns and relationships of "books"
+type GetSeriesSeries_by_pkSeriesBook_seriesBookBooks struct {
+	Ratings_count int64 `json:"ratings_count"`
+}
+
+// GetRatings_count returns GetSeriesSeries_by_pkSeriesBook_seriesBookBooks.Ratings_count, and is useful for accessing the field via an interface.
+func (v *GetSeriesSeries_by_pkSeriesBook_seriesBookBooks) GetRatings_count() int64 {
+	return v.Ratings_count
+}
+
 // GetWorkBooks_by_pkBooks includes the requested fields of the GraphQL type books.
 // The GraphQL type's documentation follows.
 //
@@ -1661,6 +1925,13 @@ func (v *GetWorkBooks_by_pkBooks) __premarshalJSON() (*__premarshalGetWorkBooks_
 // columns and relationships of "editions"
 type GetWorkBooks_by_pkBooksEditions struct {
 	EditionInfo `json:"-"`
+	// An array relationship
+	Contributions []GetWorkBooks_by_pkBooksEditionsContributions `json:"contributions"`
+}
+
+// GetContributions returns GetWorkBooks_by_pkBooksEditions.Contributions, and is useful for accessing the field via an interface.
+func (v *GetWorkBooks_by_pkBooksEditions) GetContributions() []GetWorkBooks_by_pkBooksEditionsContributions {
+	return v.Contributions
 }
 
 // GetId returns GetWorkBooks_by_pkBooksEditions.Id, and is useful for accessing the field via an interface.
@@ -1756,6 +2027,8 @@ func (v *GetWorkBooks_by_pkBooksEditions) UnmarshalJSON(b []byte) error {
 }
 
 type __premarshalGetWorkBooks_by_pkBooksEditions struct {
+	Contributions []GetWorkBooks_by_pkBooksEditionsContributions `json:"contributions"`
+
 	Id int64 `json:"id"`
 
 	Title string `json:"title"`
@@ -1802,6 +2075,7 @@ func (v *GetWorkBooks_by_pkBooksEditions) MarshalJSON() ([]byte, error) {
 func (v *GetWorkBooks_by_pkBooksEditions) __premarshalJSON() (*__premarshalGetWorkBooks_by_pkBooksEditions, error) {
 	var retval __premarshalGetWorkBooks_by_pkBooksEditions
 
+	retval.Contributions = v.Contributions
 	retval.Id = v.EditionInfo.Id
 	retval.Title = v.EditionInfo.Title
 	retval.Subtitle = v.EditionInfo.Subtitle
@@ -1822,6 +2096,71 @@ func (v *GetWorkBooks_by_pkBooksEditions) __premarshalJSON() (*__premarshalGetWo
 	return &retval, nil
 }
 
+// GetWorkBooks_by_pkBooksEditionsContributions includes the requested fields of the GraphQL type contributions.
+// The GraphQL type's documentation follows.
+//
+// columns and relationships of "contributions"
+type GetWorkBooks_by_pkBooksEditionsContributions struct {
+	Contributions `json:"-"`
+}
+
+// GetContribution returns GetWorkBooks_by_pkBooksEditionsContributions.Contribution, and is useful for accessing the field via an interface.
+func (v *GetWorkBooks_by_pkBooksEditionsContributions) GetContribution() string {
+	return v.Contributions.Contribution
+}
+
+// GetAuthor returns GetWorkBooks_by_pkBooksEditionsContributions.Author, and is useful for accessing the field via an interface.
+func (v *GetWorkBooks_by_pkBooksEditionsContributions) GetAuthor() ContributionsAuthorAuthors {
+	return v.Contributions.Author
+}
+
+func (v *GetWorkBooks_by_pkBooksEditionsContributions) UnmarshalJSON(b []byte) error {
+
+	if string(b) == "null" {
+		return nil
+	}
+
+	var firstPass struct {
+		*GetWorkBooks_by_pkBooksEditionsContributions
+		graphql.NoUnmarshalJSON
+	}
+	firstPass.GetWorkBooks_by_pkBooksEditionsContributions = v
+
+	err := json.Unmarshal(b, &firstPass)
+	if err != nil {
+		return err
+	}
+
+	err = json.Unmarshal(
+		b, &v.Contributions)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+type __premarshalGetWorkBooks_by_pkBooksEditionsContributions struct {
+	Contribution string `json:"contribution"`
+
+	Author ContributionsAuthorAuthors `json:"author"`
+}
+
+func (v *GetWorkBooks_by_pkBooksEditionsContributions) MarshalJSON() ([]byte, error) {
+	premarshaled, err := v.__premarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(premarshaled)
+}
+
+func (v *GetWorkBooks_by_pkBooksEditionsContributions) __premarshalJSON() (*__premarshalGetWorkBooks_by_pkBooksEditionsContributions, error) {
+	var retval __premarshalGetWorkBooks_by_pkBooksEditionsContributions
+
+	retval.Contribution = v.Contributions.Contribution
+	retval.Author = v.Contributions.Author
+	return &retval, nil
+}
+
 // GetWorkByASINISBNEditions includes the requested fields of the GraphQL type editions.
 // The GraphQL type's documentation follows.
 //
@@ -1846,6 +2185,26 @@ type GetWorkByASINISBNResponse struct {
 // GetEditions returns GetWorkByASINISBNResponse.Editions, and is useful for accessing the field via an interface.
 func (v *GetWorkByASINISBNResponse) GetEditions() []GetWorkByASINISBNEditions { return v.Editions }
 
+// GetWorkBySlugBooks includes the requested fields of the GraphQL type books.
+// The GraphQL type's documentation follows.
+//
+// columns and relationships of "books"
+type GetWorkBySlugBooks struct {
+	Id int64 `json:"id"`
+}
+
+// GetId returns GetWorkBySlugBooks.Id, and is useful for accessing the field via an interface.
+func (v *GetWorkBySlugBooks) GetId() int64 { return v.Id }
+
+// GetWorkBySlugResponse is returned by GetWorkBySlug on success.
+type GetWorkBySlugResponse struct {
+	// An array relationship
+	Books []GetWorkBySlugBooks `json:"books"`
+}
+
+// GetBooks returns GetWorkBySlugResponse.Books, and is useful for accessing the field via an interface.
+func (v *GetWorkBySlugResponse) GetBooks() []GetWorkBySlugBooks { return v.Books }
+
 // GetWorkResponse is returned by GetWork on success.
 type GetWorkResponse struct {
 	// fetch data from the table: "books" using primary key columns
@@ -2125,6 +2484,22 @@ type __GetEditionInput struct {
 // GetEditionID returns __GetEditionInput.EditionID, and is useful for accessing the field via an interface.
 func (v *__GetEditionInput) GetEditionID() int64 { return v.EditionID }
 
+// __GetGenreRecommendedInput is used internally by genqlient
+type __GetGenreRecommendedInput struct {
+	Genre  string `json:"genre"`
+	Limit  int64  `json:"limit"`
+	Offset int64  `json:"offset"`
+}
+
+// GetGenre returns __GetGenreRecommendedInput.Genre, and is useful for accessing the field via an interface.
+func (v *__GetGenreRecommendedInput) GetGenre() string { return v.Genre }
+
+// GetLimit returns __GetGenreRecommendedInput.Limit, and is useful for accessing the field via an interface.
+func (v *__GetGenreRecommendedInput) GetLimit() int64 { return v.Limit }
+
+// GetOffset returns __GetGenreRecommendedInput.Offset, and is useful for accessing the field via an interface.
+func (v *__GetGenreRecommendedInput) GetOffset() int64 { return v.Offset }
+
 // __GetRecommendedInput is used internally by genqlient
 type __GetRecommendedInput struct {
 	From   string `json:"from"`
@@ -2169,14 +2544,26 @@ type __GetWorkByASINISBNInput struct {
 // GetIsbn returns __GetWorkByASINISBNInput.Isbn, and is useful for accessing the field via an interface.
 func (v *__GetWorkByASINISBNInput) GetIsbn() string { return v.Isbn }
 
+// __GetWorkBySlugInput is used internally by genqlient
+type __GetWorkBySlugInput struct {
+	Slug string `json:"slug"`
+}
+
+// GetSlug returns __GetWorkBySlugInput.Slug, and is useful for accessing the field via an interface.
+func (v *__GetWorkBySlugInput) GetSlug() string { return v.Slug }
+
 // __GetWorkInput is used internally by genqlient
 type __GetWorkInput struct {
-	BookID int64 `json:"bookID"`
+	BookID    int64 `json:"bookID"`
+	Narrators bool  `json:"narrators"`
 }
 
 // GetBookID returns __GetWorkInput.BookID, and is useful for accessing the field via an interface.
 func (v *__GetWorkInput) GetBookID() int64 { return v.BookID }
 
+// GetNarrators returns __GetWorkInput.Narrators, and is useful for accessing the field via an interface.
+func (v *__GetWorkInput) GetNarrators() bool { return v.Narrators }
+
 // __SearchInput is used internally by genqlient
 type __SearchInput struct {
 	Query string `json:"query"`
@@ -2256,30 +2643,59 @@ fragment DefaultEditions on books {
 	}
 	default_audio_edition {
 		id
+		pages
+		audio_seconds
+		cached_image(path: "url")
+		language {
+			code3
+		}
 		contributions {
 			... Contributions
 		}
 	}
 	default_physical_edition {
 		id
+		pages
+		audio_seconds
+		cached_image(path: "url")
+		language {
+			code3
+		}
 		contributions {
 			... Contributions
 		}
 	}
 	default_cover_edition {
 		id
+		pages
+		audio_seconds
+		cached_image(path: "url")
+		language {
+			code3
+		}
 		contributions {
 			... Contributions
 		}
 	}
 	default_ebook_edition {
 		id
+		pages
+		audio_seconds
+		cached_image(path: "url")
+		language {
+			code3
+		}
 		contributions {
 			... Contributions
 		}
 	}
 	fallback: editions(order_by: {id:desc}, limit: 1) {
 		id
+		pages
+		audio_seconds
+		contributions {
+			... Contributions
+		}
 	}
 }
 `
@@ -2377,30 +2793,59 @@ fragment DefaultEditions on books {
 	}
 	default_audio_edition {
 		id
+		pages
+		audio_seconds
+		cached_image(path: "url")
+		language {
+			code3
+		}
 		contributions {
 			... Contributions
 		}
 	}
 	default_physical_edition {
 		id
+		pages
+		audio_seconds
+		cached_image(path: "url")
+		language {
+			code3
+		}
 		contributions {
 			... Contributions
 		}
 	}
 	default_cover_edition {
 		id
+		pages
+		audio_seconds
+		cached_image(path: "url")
+		language {
+			code3
+		}
 		contributions {
 			... Contributions
 		}
 	}
 	default_ebook_edition {
 		id
+		pages
+		audio_seconds
+		cached_image(path: "url")
+		language {
+			code3
+		}
 		contributions {
 			... Contributions
 		}
 	}
 	fallback: editions(order_by: {id:desc}, limit: 1) {
 		id
+		pages
+		audio_seconds
+		contributions {
+			... Contributions
+		}
 	}
 }
 fragment Contributions on contributions {
@@ -2443,6 +2888,44 @@ func GetEdition(
 	return data_, err_
 }
 
+// The query executed by GetGenreRecommended.
+const GetGenreRecommended_Operation = `
+query GetGenreRecommended ($genre: String!, $limit: Int!, $offset: Int!) {
+	books(where: {book_status_id:{_eq:"1"},taggings:{tag:{tag:{_eq:$genre},tag_category:{category:{_eq:"Genre"}}}}}, order_by: {users_count:desc}, limit: $limit, offset: $offset) {
+		id
+	}
+}
+`
+
+func GetGenreRecommended(
+	ctx_ context.Context,
+	client_ graphql.Client,
+	genre string,
+	limit int64,
+	offset int64,
+) (data_ *GetGenreRecommendedResponse, err_ error) {
+	req_ := &graphql.Request{
+		OpName: "GetGenreRecommended",
+		Query:  GetGenreRecommended_Operation,
+		Variables: &__GetGenreRecommendedInput{
+			Genre:  genre,
+			Limit:  limit,
+			Offset: offset,
+		},
+	}
+
+	data_ = &GetGenreRecommendedResponse{}
+	resp_ := &graphql.Response{Data: data_}
+
+	err_ = client_.MakeRequest(
+		ctx_,
+		req_,
+		resp_,
+	)
+
+	return data_, err_
+}
+
 // The query executed by GetRecommended.
 const GetRecommended_Operation = `
 query GetRecommended ($from: date!, $to: date!, $limit: Int!, $offset: Int!) {
@@ -2491,11 +2974,14 @@ query GetSeries ($seriesID: Int!, $limit: Int!, $offset: Int!) {
 		name
 		description
 		books_count
-		book_series(limit: $limit, offset: $offset, where: {book:{book_status_id:{_eq:"1"}}}, order_by: [{position:asc},{book:{users_read_count:desc}}]) {
+		book_series(limit: $limit, offset: $offset, where: {book:{book_status_id:{_eq:"1"}}}, order_by: [{position:asc},{book:{ratings_count:desc}},{book_id:asc}]) {
 			book_id
 			details
 			position
 			featured
+			book {
+				ratings_count
+			}
 		}
 	}
 }
@@ -2532,11 +3018,14 @@ func GetSeries(
 
 // The query executed by GetWork.
 const GetWork_Operation = `
-query GetWork ($bookID: Int!) {
+query GetWork ($bookID: Int!, $narrators: Boolean!) {
 	books_by_pk(id: $bookID) {
 		... WorkInfo
 		editions(order_by: {score:desc_nulls_last}) {
 			... EditionInfo
+			contributions @include(if: $narrators) {
+				... Contributions
+			}
 		}
 	}
 }
@@ -2587,6 +3076,12 @@ fragment EditionInfo on editions {
 	book_id
 	score
 }
+fragment Contributions on contributions {
+	contribution
+	author {
+		... AuthorInfo
+	}
+}
 fragment DefaultEditions on books {
 	id
 	contributions {
@@ -2594,36 +3089,59 @@ fragment DefaultEditions on books {
 	}
 	default_audio_edition {
 		id
+		pages
+		audio_seconds
+		cached_image(path: "url")
+		language {
+			code3
+		}
 		contributions {
 			... Contributions
 		}
 	}
 	default_physical_edition {
 		id
+		pages
+		audio_seconds
+		cached_image(path: "url")
+		language {
+			code3
+		}
 		contributions {
 			... Contributions
 		}
 	}
 	default_cover_edition {
 		id
+		pages
+		audio_seconds
+		cached_image(path: "url")
+		language {
+			code3
+		}
 		contributions {
 			... Contributions
 		}
 	}
 	default_ebook_edition {
 		id
+		pages
+		audio_seconds
+		cached_image(path: "url")
+		language {
+			code3
+		}
 		contributions {
 			... Contributions
 		}
 	}
 	fallback: editions(order_by: {id:desc}, limit: 1) {
 		id
-	}
-}
-fragment Contributions on contributions {
-	contribution
-	author {
-		... AuthorInfo
+		pages
+		audio_seconds
+		contributions {
+			... Contributions
+		}
 	}
 }
 fragment AuthorInfo on authors {
@@ -2639,12 +3157,14 @@ func GetWork(
 	ctx_ context.Context,
 	client_ graphql.Client,
 	bookID int64,
+	narrators bool,
 ) (data_ *GetWorkResponse, err_ error) {
 	req_ := &graphql.Request{
 		OpName: "GetWork",
 		Query:  GetWork_Operation,
 		Variables: &__GetWorkInput{
-			BookID: bookID,
+			BookID:    bookID,
+			Narrators: narrators,
 		},
 	}
 
@@ -2695,6 +3215,40 @@ func GetWorkByASINISBN(
 	return data_, err_
 }
 
+// The query executed by GetWorkBySlug.
+const GetWorkBySlug_Operation = `
+query GetWorkBySlug ($slug: String!) {
+	books(where: {slug:{_eq:$slug}}, limit: 1) {
+		id
+	}
+}
+`
+
+func GetWorkBySlug(
+	ctx_ context.Context,
+	client_ graphql.Client,
+	slug string,
+) (data_ *GetWorkBySlugResponse, err_ error) {
+	req_ := &graphql.Request{
+		OpName: "GetWorkBySlug",
+		Query:  GetWorkBySlug_Operation,
+		Variables: &__GetWorkBySlugInput{
+			Slug: slug,
+		},
+	}
+
+	data_ = &GetWorkBySlugResponse{}
+	resp_ := &graphql.Response{Data: data_}
+
+	err_ = client_.MakeRequest(
+		ctx_,
+		req_,
+		resp_,
+	)
+
+	return data_, err_
+}
+
 // The query executed by Search.
 const Search_Operation = `
 query Search ($query: String!) {
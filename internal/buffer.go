@@ -1,6 +1,7 @@
 package internal
 
 import (
+	"context"
 	"fmt"
 	"sync"
 	"sync/atomic"
@@ -51,6 +52,112 @@ func accumulate[T any](producer <-chan T, buf bbuffer[T]) <-chan T {
 	return c
 }
 
+// partition fans values out to n workers which call fn. Values with the same
+// key always go to the same worker, so they're handled serially and in order,
+// while values with different keys can be handled in parallel. Values for a
+// busy worker are parked in its own queue, so one slow key doesn't hold up
+// every other worker. queue creates those queues; a nil queue parks values in
+// a slicebuffer.
+//
+// partition blocks until in is closed and every value has been handled, or
+// until ctx is cancelled and the values already being handled are done.
+//
+// If fn panics the remaining workers are stopped and the panic is re-raised on
+// the caller's goroutine, where it can be recovered. Values still parked when
+// the panic is noticed are dropped.
+func partition[T any](ctx context.Context, in <-chan T, n int, key func(T) int64, queue func() bbuffer[T], fn func(T)) {
+	n = max(n, 1)
+	if queue == nil {
+		queue = func() bbuffer[T] { return &slicebuffer[T]{} }
+	}
+
+	workers := make([]chan T, n)
+	panicked := make(chan any, n)
+	done := make(chan struct{})
+	wg := sync.WaitGroup{}
+	for i := range workers {
+		workers[i] = make(chan T)
+		parked := park(workers[i], queue(), done)
+		wg.Go(func() {
+			defer func() {
+				if r := recover(); r != nil {
+					panicked <- r
+				}
+			}()
+			for t := range parked {
+				fn(t)
+			}
+		})
+	}
+
+	stop := func() {
+		for _, w := range workers {
+			close(w)
+		}
+		wg.Wait()
+	}
+
+	for {
+		select {
+		case t, ok := <-in:
+			if !ok {
+				stop()
+				select {
+				case r := <-panicked:
+					close(done)
+					panic(r)
+				default:
+				}
+				return
+			}
+			workers[uint64(key(t))%uint64(n)] <- t
+		case r := <-panicked:
+			close(done)
+			stop()
+			panic(r)
+		case <-ctx.Done():
+			close(done)
+			stop()
+			return
+		}
+	}
+}
+
+// park buffers values from in until the consumer is ready for them, so sends
+// to in never wait on the consumer. Parked values are still delivered after in
+// is closed, unless done is closed first.
+func park[T any](in <-chan T, parked bbuffer[T], done <-chan struct{}) <-chan T {
+	out := make(chan T)
+	go func() {
+		defer close(out)
+		for {
+			// Like accumulate, our send no-ops while nothing is parked. We
+			// peek rather than check len, since an edgebuf doesn't count
+			// edges without children.
+			var consumer chan T
+			next, ok := parked.peek()
+			if ok {
+				consumer = out
+			} else if in == nil {
+				return
+			}
+			select {
+			case t, ok := <-in:
+				if !ok {
+					in = nil
+					continue
+				}
+				parked.push(t)
+			case consumer <- next:
+				_ = parked.pop()
+			case <-done:
+				return
+			}
+		}
+	}()
+	return out
+}
+
 // slicebuffer is a simple slice buffer. It is not thread safe.
 type slicebuffer[T any] []T
 
@@ -92,6 +199,10 @@ type edgebuf struct {
 	works   map[int64]*edge
 	authors map[int64]*edge
 	size    atomic.Int32
+
+	// pushed, if set, is called for every pushed edge with how many of its
+	// children weren't already waiting in the buffer.
+	pushed func(e edge, added int)
 }
 
 // push enqueues the edge. If an edge of the same kind was already
@@ -130,14 +241,18 @@ func (b *edgebuf) push(e edge) {
 		panic(fmt.Sprintf("unrecognized edge kind %q", fmt.Sprint(rune(e.kind))))
 	}
 
+	added := len(e.childIDs)
 	if ok {
 		combined := union(existing.childIDs, e.childIDs)
-		b.size.Add(int32(len(combined) - len(existing.childIDs)))
+		added = len(combined) - len(existing.childIDs)
 		existing.childIDs = combined
 	} else {
-		b.size.Add(int32(len(e.childIDs)))
 		b.queue = append(b.queue, &e)
 	}
+	b.size.Add(int32(added))
+	if b.pushed != nil {
+		b.pushed(e, added)
+	}
 	b.cond.Signal()
 }
 
@@ -1,6 +1,10 @@
 package internal
 
 import (
+	"maps"
+	"slices"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -90,3 +94,116 @@ func TestAccumulateSlice(t *testing.T) {
 	_, ok := <-consumer
 	assert.False(t, ok)
 }
+
+func TestPartition(t *testing.T) {
+	// Different parents are denormalized in parallel, but edges for the same
+	// parent are handled serially and in order.
+
+	in := make(chan edge)
+	go func() {
+		in <- edge{kind: authorEdge, parentID: 1, childIDs: newSet(int64(10))}
+		in <- edge{kind: authorEdge, parentID: 2, childIDs: newSet(int64(20))}
+		in <- edge{kind: authorEdge, parentID: 1, childIDs: newSet(int64(11))}
+		close(in)
+	}()
+
+	author2Done := make(chan struct{})
+	var inFlight atomic.Int32
+	var order []int64
+	mu := sync.Mutex{}
+
+	partition(t.Context(), in, 2, func(e edge) int64 { return e.parentID }, nil, func(e edge) {
+		if e.parentID == 2 {
+			close(author2Done)
+			return
+		}
+
+		assert.Equal(t, int32(1), inFlight.Add(1), "author 1 was denormalized concurrently")
+		defer inFlight.Add(-1)
+
+		// The first edge for author 1 can only finish if author 2 is
+		// handled in parallel.
+		select {
+		case <-author2Done:
+		case <-time.After(5 * time.Second):
+			t.Error("author 2 wasn't denormalized in parallel")
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		order = append(order, slices.Collect(maps.Keys(e.childIDs))...)
+	})
+
+	assert.Equal(t, []int64{10, 11}, order)
+}
+
+func TestPartitionBusyKey(t *testing.T) {
+	// A slow key doesn't stop other keys from being dispatched, even when
+	// more values are waiting for it.
+
+	in := make(chan int64)
+	go func() {
+		in <- 0
+		in <- 0
+		in <- 1
+		close(in)
+	}()
+
+	otherDone := make(chan struct{})
+	var handled atomic.Int32
+	partition(t.Context(), in, 2, func(i int64) int64 { return i }, nil, func(i int64) {
+		handled.Add(1)
+		if i == 1 {
+			close(otherDone)
+			return
+		}
+		select {
+		case <-otherDone:
+		case <-time.After(5 * time.Second):
+			t.Error("key 1 was stuck behind key 0")
+		}
+	})
+
+	assert.Equal(t, int32(3), handled.Load())
+}
+
+func TestPartitionEdgebuf(t *testing.T) {
+	// Edges parked for a busy parent are merged, and pending children are
+	// only counted once.
+
+	var pending atomic.Int64
+	send := func(in chan<- edge, e edge) {
+		pending.Add(int64(len(e.childIDs)))
+		in <- e
+	}
+	parked := func() bbuffer[edge] {
+		return &edgebuf{pushed: func(e edge, added int) { pending.Add(int64(added - len(e.childIDs))) }}
+	}
+
+	in := make(chan edge)
+	started := make(chan struct{})
+	sent := make(chan struct{})
+	go func() {
+		send(in, edge{kind: authorEdge, parentID: 1, childIDs: newSet(int64(10))})
+		<-started
+		send(in, edge{kind: authorEdge, parentID: 1, childIDs: newSet(int64(11))})
+		send(in, edge{kind: authorEdge, parentID: 1, childIDs: newSet(int64(11), int64(12))})
+		send(in, edge{kind: refreshDone, parentID: 1})
+		close(sent)
+		close(in)
+	}()
+
+	var handled [][]int64
+	partition(t.Context(), in, 2, func(e edge) int64 { return e.parentID }, parked, func(e edge) {
+		defer pending.Add(-int64(len(e.childIDs)))
+		if len(handled) == 0 {
+			close(started)
+			<-sent // Everything else is parked behind us.
+			assert.Eventually(t, func() bool { return pending.Load() == 3 }, 5*time.Second, time.Millisecond)
+		}
+		handled = append(handled, slices.Sorted(maps.Keys(e.childIDs)))
+	})
+
+	assert.Equal(t, [][]int64{{10}, {11, 12}, nil}, handled)
+	assert.Zero(t, pending.Load())
+}
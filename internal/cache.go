@@ -6,7 +6,9 @@ import (
 	"fmt"
 	"log/slog"
 	"slices"
+	"strings"
 	"time"
+	"unicode"
 
 	"github.com/blampe/isbn"
 	"github.com/prometheus/client_golang/prometheus"
@@ -20,6 +22,14 @@ type cache[T any] interface {
 	Delete(ctx context.Context, key string) error
 }
 
+// staleGetter is implemented by caches which keep expired values around, like
+// Postgres.
+type staleGetter interface {
+	// GetStale returns a value even if it's expired, along with how long ago
+	// it expired.
+	GetStale(ctx context.Context, key string) ([]byte, time.Duration, bool)
+}
+
 // LayeredCache implements a simple tiered cache. In practice we use an
 // in-memory cache backed by Postgres for persistent storage. Hits at lower
 // layers are automatically percolated up. Values are compressed with gzip at
@@ -32,7 +42,11 @@ type LayeredCache struct {
 	metrics *cacheMetrics
 }
 
-var _ cache[[]byte] = (*LayeredCache)(nil)
+var (
+	_ cache[[]byte] = (*LayeredCache)(nil)
+	_ staleGetter   = (*LayeredCache)(nil)
+	_ staleGetter   = readOnlyCache{}
+)
 
 // GetWithTTL returns the cached value and its TTL. The boolean returned is
 // false if no value was found.
@@ -41,8 +55,11 @@ func (c *LayeredCache) GetWithTTL(ctx context.Context, key string) ([]byte, time
 	var ttl time.Duration
 	var ok bool
 
-	for _, cc := range c.wrapped {
+	for i, cc := range c.wrapped {
 		val, ttl, ok = cc.GetWithTTL(ctx, key)
+		if i == 0 {
+			c.metrics.memoryResultInc(ok)
+		}
 		if !ok {
 			// Percolate the value back up if we eventually find it.
 			defer func(cc cache[[]byte]) {
@@ -68,6 +85,21 @@ func (c *LayeredCache) Get(ctx context.Context, key string) ([]byte, bool) {
 	return val, ok
 }
 
+// GetStale returns a value from the first layer which keeps expired values,
+// along with how long ago it expired.
+func (c *LayeredCache) GetStale(ctx context.Context, key string) ([]byte, time.Duration, bool) {
+	for _, cc := range c.wrapped {
+		sg, ok := cc.(staleGetter)
+		if !ok {
+			continue
+		}
+		if val, age, ok := sg.GetStale(ctx, key); ok {
+			return val, age, true
+		}
+	}
+	return nil, 0, false
+}
+
 // Expire expires a key from all layers of the cache. This removes it from
 // memory but keeps data persisted in Postgres without a TTL.
 func (c *LayeredCache) Expire(ctx context.Context, key string) error {
@@ -110,10 +142,58 @@ func (c *LayeredCache) Set(ctx context.Context, key string, val []byte, ttl time
 	}
 }
 
+// CacheOption configures optional cache behavior.
+type CacheOption func(*cacheOptions)
+
+type cacheOptions struct {
+	// compactEvery is how often expired rows are deleted from Postgres. Zero
+	// disables compaction.
+	compactEvery time.Duration
+	// compactGrace is how long a row must have been expired before it's
+	// deleted. Stale data is still useful while it's being refreshed.
+	compactGrace time.Duration
+	// readOnly prevents any writes to shared layers (Postgres, Cloudflare).
+	readOnly bool
+	// memoryBytes bounds the in-memory layer. Non-positive values use half
+	// of the memory limit.
+	memoryBytes int64
+}
+
+// WithCompaction periodically deletes Postgres rows which expired more than
+// grace ago. A non-positive interval disables compaction.
+func WithCompaction(every, grace time.Duration) CacheOption {
+	return func(o *cacheOptions) {
+		o.compactEvery = every
+		o.compactGrace = grace
+	}
+}
+
+// WithReadOnly never writes to, expires or deletes from shared layers. Hits
+// are still served from them and the in-memory layer still works normally, so
+// a canary can run against a shared database without mutating it.
+func WithReadOnly() CacheOption {
+	return func(o *cacheOptions) {
+		o.readOnly = true
+	}
+}
+
+// WithMemorySize bounds the in-memory layer in front of Postgres to the given
+// number of bytes. Non-positive sizes use half of the Go memory limit.
+func WithMemorySize(bytes int64) CacheOption {
+	return func(o *cacheOptions) {
+		o.memoryBytes = bytes
+	}
+}
+
 // NewCache constructs a new layered cache.
-func NewCache(ctx context.Context, dsn string, cf *CloudflareCache, reg *prometheus.Registry) (*LayeredCache, error) {
-	m := newMemoryCache()
-	pg, err := newPostgresCache(ctx, dsn, reg)
+func NewCache(ctx context.Context, dsn string, cf *CloudflareCache, reg *prometheus.Registry, opts ...CacheOption) (*LayeredCache, error) {
+	o := cacheOptions{}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	m := newSizedMemoryCache(o.memoryBytes)
+	pg, err := newPostgresCache(ctx, dsn, reg, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -121,13 +201,17 @@ func NewCache(ctx context.Context, dsn string, cf *CloudflareCache, reg *prometh
 		wrapped: []cache[[]byte]{m, pg},
 		metrics: newCacheMetrics(reg),
 	}
+	registerMemoryCacheGauges(reg, c.metrics, m.size)
 
 	if cf != nil {
 		c.wrapped = append(c.wrapped, cf)
 	}
 
-	if cf != nil {
-		c.wrapped = append(c.wrapped, cf)
+	if o.readOnly {
+		Log(ctx).Warn("cache is read-only; nothing will be persisted")
+		for i := 1; i < len(c.wrapped); i++ {
+			c.wrapped[i] = readOnlyCache{c.wrapped[i]}
+		}
 	}
 
 	// Log cache stats every minute.
@@ -139,6 +223,8 @@ func NewCache(ctx context.Context, dsn string, cf *CloudflareCache, reg *prometh
 				slog.Int64("hits", c.metrics.cacheHitGet()),
 				slog.Int64("misses", c.metrics.cacheMissGet()),
 				slog.Float64("ratio", c.metrics.cacheHitRatioGet()),
+				slog.Float64("memoryRatio", c.metrics.memoryHitRatioGet()),
+				slog.Int64("memoryBytes", m.size()),
 			)
 		}
 	}()
@@ -146,9 +232,43 @@ func NewCache(ctx context.Context, dsn string, cf *CloudflareCache, reg *prometh
 	return c, nil
 }
 
+// readOnlyCache serves reads from a wrapped cache but drops all writes.
+type readOnlyCache struct {
+	cache[[]byte]
+}
+
+// Set is a no-op.
+func (readOnlyCache) Set(context.Context, string, []byte, time.Duration) {}
+
+// Expire is a no-op.
+func (readOnlyCache) Expire(context.Context, string) error { return nil }
+
+// Delete is a no-op.
+func (readOnlyCache) Delete(context.Context, string) error { return nil }
+
+// GetStale forwards to the wrapped cache, if it keeps expired values. Serving
+// stale data matters most when we can't write fresh data.
+func (c readOnlyCache) GetStale(ctx context.Context, key string) ([]byte, time.Duration, bool) {
+	sg, ok := c.cache.(staleGetter)
+	if !ok {
+		return nil, 0, false
+	}
+	return sg.GetStale(ctx, key)
+}
+
+// KeyKind is the prefix identifying what kind of resource a cache key holds.
+type KeyKind string
+
+// Kinds of cache keys which can be listed.
+const (
+	AuthorKeys KeyKind = "a"
+	WorkKeys   KeyKind = "w"
+	SeriesKeys KeyKind = "s"
+)
+
 // WorkKey returns a cache key for a work ID.
 func WorkKey(workID int64) string {
-	return fmt.Sprintf("w%d", workID)
+	return fmt.Sprintf("%s%d", WorkKeys, workID)
 }
 
 // BookKey returns a cache key for a book (edition) ID.
@@ -158,15 +278,53 @@ func BookKey(bookID int64) string {
 
 // AuthorKey returns a cache key for an author ID.
 func AuthorKey(authorID int64) string {
-	return fmt.Sprintf("a%d", authorID)
+	return fmt.Sprintf("%s%d", AuthorKeys, authorID)
+}
+
+// RawKey returns the cache key for the raw upstream response behind another
+// cache key.
+func RawKey(key string) string {
+	return "debug:" + key
 }
 
 func seriesKey(seriesID int64) string {
-	return fmt.Sprintf("s%d", seriesID)
+	return fmt.Sprintf("%s%d", SeriesKeys, seriesID)
+}
+
+func authorKCAKey(authorID int64) string {
+	return fmt.Sprintf("ka%d", authorID)
+}
+
+// aliasKey returns a cache key for an author alias like a pseudonym, so
+// searches for the name can be resolved to the author using it.
+func aliasKey(name string) string {
+	return "p" + strings.ToLower(strings.Join(strings.Fields(name), " "))
 }
 
 func asinKey(asin string) string {
-	return fmt.Sprintf("z%s", asin)
+	return fmt.Sprintf("z%s", normalizeASIN(asin))
+}
+
+// normalizeASIN uppercases an ASIN and strips any whitespace or hyphens, so
+// equivalent ASINs share a cache key.
+func normalizeASIN(asin string) string {
+	return strings.ToUpper(strings.Map(func(r rune) rune {
+		if r == '-' || unicode.IsSpace(r) {
+			return -1
+		}
+		return r
+	}, asin))
+}
+
+// parseISBN parses an ISBN-10 or ISBN-13, ignoring any whitespace. Hyphens
+// are already handled by isbn.Parse.
+func parseISBN(s string) (*isbn.ISBN, error) {
+	return isbn.Parse(strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) {
+			return -1
+		}
+		return r
+	}, s))
 }
 
 func isbnKey(isbn isbn.ISBN) string {
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestCache(t *testing.T) {
@@ -58,3 +59,100 @@ func TestCache(t *testing.T) {
 		assert.Equal(t, val, out)
 	})
 }
+
+func TestReadOnlyCache(t *testing.T) {
+	ctx := context.Background()
+	c0 := newMemoryCache()
+	c1 := newMemoryCache()
+
+	l := &LayeredCache{wrapped: []cache[[]byte]{c0, readOnlyCache{c1}}, metrics: newCacheMetrics(NewMetrics())}
+
+	// Hits from the shared layer are still served.
+	c1.Set(ctx, "shared", []byte("shared"), time.Hour)
+	out, ok := l.Get(ctx, "shared")
+	assert.True(t, ok)
+	assert.Equal(t, []byte("shared"), out)
+
+	// Writes only land in memory.
+	l.Set(ctx, "new", []byte("new"), time.Hour)
+	_, ok = c0.Get(ctx, "new")
+	assert.True(t, ok)
+	_, ok = c1.Get(ctx, "new")
+	assert.False(t, ok)
+
+	// Expiring and deleting leave the shared layer alone.
+	assert.NoError(t, l.Expire(ctx, "shared"))
+	assert.NoError(t, l.Delete(ctx, "shared"))
+	_, ttl, ok := c1.GetWithTTL(ctx, "shared")
+	assert.True(t, ok)
+	assert.Greater(t, ttl, time.Minute)
+}
+
+func TestReadOnlyCacheStale(t *testing.T) {
+	ctx := context.Background()
+	stale := staleCache{cache: newMemoryCache(), stale: map[string][]byte{"key": []byte("stale")}}
+
+	// Stale values are still served through the read-only wrapper.
+	l := &LayeredCache{wrapped: []cache[[]byte]{newMemoryCache(), readOnlyCache{stale}}, metrics: newCacheMetrics(NewMetrics())}
+	out, age, ok := l.GetStale(ctx, "key")
+	assert.True(t, ok)
+	assert.Equal(t, []byte("stale"), out)
+	assert.Equal(t, time.Hour, age)
+
+	_, _, ok = l.GetStale(ctx, "missing")
+	assert.False(t, ok)
+
+	// Layers which don't keep stale values have nothing to offer.
+	l = &LayeredCache{wrapped: []cache[[]byte]{newMemoryCache(), readOnlyCache{newMemoryCache()}}, metrics: newCacheMetrics(NewMetrics())}
+	_, _, ok = l.GetStale(ctx, "key")
+	assert.False(t, ok)
+}
+
+func TestLookupKeys(t *testing.T) {
+	assert.Equal(t, "zB00ABC1234", asinKey("b00abc1234"))
+	assert.Equal(t, asinKey("B00ABC1234"), asinKey(" b00-abc1234\n"))
+
+	want, err := parseISBN("9780306406157")
+	require.NoError(t, err)
+	for _, s := range []string{"978-0-306-40615-7", " 978 0306406157 ", "0-306-40615-2"} {
+		got, err := parseISBN(s)
+		require.NoError(t, err, s)
+		assert.Equal(t, isbnKey(*want), isbnKey(*got), s)
+	}
+
+	// Lookups hit regardless of how the ASIN or ISBN was written.
+	ctrl, err := NewController(newMemoryCache(), nil, nil, nil)
+	require.NoError(t, err)
+	require.NoError(t, ctrl.setASIN(t.Context(), "b00abc1234", 1))
+	editionID, err := ctrl.GetASIN(t.Context(), "B00ABC1234 ")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), editionID)
+
+	hyphenated, err := parseISBN("978-0-306-40615-7")
+	require.NoError(t, err)
+	require.NoError(t, ctrl.setISBN(t.Context(), *hyphenated, 2))
+	editionID, err = ctrl.GetISBN(t.Context(), *want)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), editionID)
+}
+
+func TestMemoryCacheMetrics(t *testing.T) {
+	ctx := context.Background()
+	c0 := newSizedMemoryCache(1 << 20)
+	c1 := newMemoryCache()
+
+	l := &LayeredCache{wrapped: []cache[[]byte]{c0, c1}, metrics: newCacheMetrics(NewMetrics())}
+	assert.Zero(t, c0.size())
+	assert.Equal(t, int64(1<<20), c0.r.MaxCost())
+
+	val := []byte("value")
+	c1.Set(ctx, "key", val, time.Hour)
+
+	_, ok := l.Get(ctx, "key") // Misses memory and is promoted.
+	require.True(t, ok)
+	_, ok = l.Get(ctx, "key")
+	require.True(t, ok)
+
+	assert.Equal(t, 0.5, l.metrics.memoryHitRatioGet())
+	assert.GreaterOrEqual(t, c0.size(), int64(len(val))) // Includes per-item overhead.
+}
@@ -144,21 +144,27 @@ type CloudflareCache struct {
 	pather func(string) string // Responsible for mapping cache keys to URLs for busting.
 }
 
-// NewCloudflareCache creates a new CloudflareCache.
-func NewCloudflareCache(apiKey string, zoneID string, pather func(string) string, reg *prometheus.Registry) (*CloudflareCache, error) {
+// NewCloudflareCache creates a new CloudflareCache. Busts are sent until the
+// context is cancelled.
+func NewCloudflareCache(ctx context.Context, apiKey string, zoneID string, pather func(string) string, reg *prometheus.Registry) (*CloudflareCache, error) {
 	cb, err := newCloudflareBuster(apiKey, zoneID, reg)
 	if err != nil {
 		return nil, err
 	}
 
-	ctx := context.WithValue(context.Background(), middleware.RequestIDKey, "cloudflare")
+	ctx = context.WithValue(ctx, middleware.RequestIDKey, "cloudflare")
 	go cb.Run(ctx)
 
 	// Log cloudflare stats every minute.
 	go func() {
-		ctx := context.Background()
+		ticker := time.NewTicker(1 * time.Minute)
+		defer ticker.Stop()
 		for {
-			time.Sleep(1 * time.Minute)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
 			Log(ctx).Debug("cloudflare stats",
 				"queueSize", cb.metrics.batchesWaitingGet(),
 			)
@@ -7,15 +7,21 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"io"
 	"iter"
 	"maps"
+	"math"
 	"math/rand/v2"
 	"net/http"
 	"net/url"
+	"reflect"
+	"regexp"
+	"runtime/debug"
+	"runtime/metrics"
 	"slices"
+	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/blampe/isbn"
@@ -23,6 +29,10 @@ import (
 	"github.com/bytedance/sonic/option"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap/buffer"
 	"golang.org/x/sync/errgroup"
 	"golang.org/x/sync/singleflight"
 )
@@ -40,11 +50,23 @@ var (
 
 	_seriesTTL = 14 * 24 * time.Hour // 2 weeks
 
+	// _authorStubTTL is how long a minimal author is served while the full
+	// author loads in the background.
+	_authorStubTTL = time.Minute
+
 	// _missing is a sentinel value we cache for 404 responses.
 	_missing = []byte{0}
 
 	// _missingTTL is how long we'll wait before retrying a 404.
 	_missingTTL = 7 * 24 * time.Hour
+
+	// _denormRetries caps how many times a failed denormalization is retried.
+	_denormRetries = 5
+	// _denormBackoff is how long we wait before retrying a failed
+	// denormalization. It doubles with every attempt.
+	_denormBackoff = 30 * time.Second
+	// _denormRetryLimit bounds how many retries can be waiting at once.
+	_denormRetryLimit int32 = 1000
 )
 
 // unknownAuthor author corresponds to the "unknown" or "anonymous" authors
@@ -86,13 +108,457 @@ type Controller struct {
 	refreshG errgroup.Group
 	// refreshC collects author refreshes.
 	refreshC chan refreshAuthor
+	// refreshing holds the IDs of authors with a refresh in-flight, so the
+	// same author is never refreshed twice at once.
+	refreshing sync.Map
 
 	// workG collects work refreshes.
 	workG errgroup.Group
 
+	// saveG limits how many batches of editions we persist concurrently.
+	// Callers block once it's full, which keeps large author refreshes from
+	// spawning an unbounded number of goroutines.
+	saveG errgroup.Group
+
+	// denormRetries counts failed denormalizations waiting to be retried.
+	denormRetries atomic.Int32
+
+	// denormActive counts edges currently being denormalized.
+	denormActive atomic.Int32
+
+	// denormPending counts children waiting to be denormalized, whether
+	// they're buffered, parked for a busy worker or being handled.
+	// denormPendingMu orders updates so the gauge never lags behind it.
+	denormPending   atomic.Int64
+	denormPendingMu sync.Mutex
+
+	// background counts goroutines started by spawn, which caps them at
+	// backgroundWorkers.
+	background atomic.Int64
+
+	// opts holds optional behavior. It's swapped atomically by Reconfigure.
+	opts atomic.Pointer[controllerOptions]
+
+	// notifier collects updates to send to the webhook, if any.
+	notifier *notifier
+
+	// decoded holds recently denormalized authors so consecutive edges for
+	// the same author don't need to decode it again.
+	decoded *lru[int64, decodedAuthor]
+
+	// codec (de)serializes resources during denormalization.
+	codec sonic.API
+
+	// tracer records spans for loads and denormalization.
+	tracer trace.Tracer
+
 	metrics *controllerMetrics
 }
 
+// ControllerOption configures optional Controller behavior.
+type ControllerOption func(*controllerOptions)
+
+type controllerOptions struct {
+	// maxAuthorWorks caps how many editions we'll load when refreshing an
+	// author. Getters should yield the most popular editions first so these
+	// are the ones we keep.
+	maxAuthorWorks int
+
+	// embeddedWorks caps how many works are embedded in an author, ranked by
+	// recency and rating. Unlike maxAuthorWorks it doesn't affect which works
+	// are loaded. Zero means unlimited.
+	embeddedWorks int
+
+	// embeddedRecency weighs recency against rating when ranking embedded
+	// works, from 0 (only rating) to 1 (only recency).
+	embeddedRecency float64
+
+	// relaxEditionAuthors keeps editions whose primary author doesn't match
+	// the work's, as long as the work's author is credited somewhere on the
+	// edition.
+	relaxEditionAuthors bool
+
+	// authorAliases redirects merged-away author IDs to the surviving author.
+	authorAliases map[int64]int64
+
+	// maxEditions caps how many editions a work holds. Zero means unlimited.
+	maxEditions int
+
+	// maxAuthorSeries caps how many series are fetched when denormalizing an
+	// author. Zero means unlimited.
+	maxAuthorSeries int
+
+	// maxSeriesLinks caps how many works are listed by each series embedded
+	// in an author. Zero means unlimited.
+	maxSeriesLinks int
+
+	// authorSeriesOnly lists only the author's own works in series embedded
+	// in an author.
+	authorSeriesOnly bool
+
+	// searchRank re-orders search results by these signals, in priority
+	// order. Results keep the provider's order when empty.
+	searchRank []SearchSignal
+
+	// refreshMemoryThreshold pauses new author refreshes while heap usage
+	// exceeds this fraction of the Go memory limit. Zero disables it.
+	refreshMemoryThreshold float64
+
+	// authorStubs returns a minimal author on a cold cache while the full
+	// author loads in the background.
+	authorStubs bool
+
+	// denormWorkers is how many parents are denormalized in parallel. It's
+	// only read when the controller starts running.
+	denormWorkers int
+
+	// workAuthors corrects the primary author of misattributed works.
+	workAuthors map[int64]int64
+
+	// continuingMonths is how recently an author must have released a work
+	// to be considered continuing. Zero disables it.
+	continuingMonths int
+
+	// searchISBN includes each search result's ISBN-13, at the cost of
+	// resolving every result's edition.
+	searchISBN bool
+
+	// editionsPerPass caps how many editions are denormalized onto a work in
+	// one pass. The rest are re-enqueued. Zero means unlimited.
+	editionsPerPass int
+
+	// minEditionYear drops editions released before this year, except the
+	// best edition. Zero disables it.
+	minEditionYear int
+
+	// excludeOmnibus drops box sets and omnibus editions, except the best
+	// edition.
+	excludeOmnibus bool
+
+	// seriesSubtitles always includes the subtitle of works in a series,
+	// even if their title is already unique.
+	seriesSubtitles bool
+
+	// backgroundWorkers caps how many fire-and-forget goroutines the
+	// controller runs at once across all call sites.
+	backgroundWorkers int
+
+	// notifyURL receives a POST when an author or work changes. Empty
+	// disables it.
+	notifyURL string
+
+	// notifyDebounce is how long updates are collected before they're sent
+	// to notifyURL.
+	notifyDebounce time.Duration
+
+	// revalidateWorkers bounds how many of a work's cached editions are
+	// re-fetched in parallel when the work is refreshed.
+	revalidateWorkers int
+
+	// keepEmptyWorks lists works on their author even if none of their
+	// editions survived filtering.
+	keepEmptyWorks bool
+
+	// maxStale is how long after expiring data can still be served while
+	// upstream is failing. Zero disables it.
+	maxStale time.Duration
+
+	// restartBackoff is how long supervise initially waits before restarting
+	// a consumer which panicked.
+	restartBackoff time.Duration
+}
+
+func newControllerOptions(opts ...ControllerOption) *controllerOptions {
+	o := &controllerOptions{maxAuthorWorks: 1000, embeddedRecency: 0.5, continuingMonths: 12, editionsPerPass: 25, notifyDebounce: 30 * time.Second, revalidateWorkers: 8, backgroundWorkers: _backgroundWorkers, seriesSubtitles: true, restartBackoff: time.Second}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// WithMaxAuthorWorks limits how many editions are loaded when refreshing an
+// author. Non-positive values are ignored.
+func WithMaxAuthorWorks(n int) ControllerOption {
+	return func(o *controllerOptions) {
+		if n > 0 {
+			o.maxAuthorWorks = n
+		}
+	}
+}
+
+// WithEmbeddedWorks caps how many works are embedded in an author to the n
+// best, ranked by a weighted combination of recency and rating. recency is
+// clamped to [0, 1]: 0 ranks only by rating and 1 only by release date. Works
+// left out can still be fetched directly. Non-positive n means unlimited.
+func WithEmbeddedWorks(n int, recency float64) ControllerOption {
+	return func(o *controllerOptions) {
+		o.embeddedWorks = max(n, 0)
+		o.embeddedRecency = min(max(recency, 0), 1)
+	}
+}
+
+// WithRelaxedEditionAuthors keeps co-authored or omnibus editions which would
+// otherwise be excluded because their primary author doesn't match the work's.
+func WithRelaxedEditionAuthors(relax bool) ControllerOption {
+	return func(o *controllerOptions) {
+		o.relaxEditionAuthors = relax
+	}
+}
+
+// WithMaxEditionsPerWork limits how many editions are kept on a work. The
+// least relevant editions are dropped first. Non-positive values mean no limit.
+func WithMaxEditionsPerWork(n int) ControllerOption {
+	return func(o *controllerOptions) {
+		if n > 0 {
+			o.maxEditions = n
+		}
+	}
+}
+
+// WithMaxAuthorSeries limits how many series are fetched when denormalizing an
+// author. Series containing more of the author's works are preferred.
+// Non-positive values mean no limit.
+func WithMaxAuthorSeries(n int) ControllerOption {
+	return func(o *controllerOptions) {
+		if n > 0 {
+			o.maxAuthorSeries = n
+		}
+	}
+}
+
+// WithMaxSeriesLinks limits how many works are listed by each series embedded
+// in an author. The author's own works are kept first. /series/{id} always
+// lists every work. Non-positive values mean no limit.
+func WithMaxSeriesLinks(n int) ControllerOption {
+	return func(o *controllerOptions) {
+		if n > 0 {
+			o.maxSeriesLinks = n
+		}
+	}
+}
+
+// WithAuthorSeriesOnly lists only the author's own works in series embedded
+// in an author. /series/{id} always lists every work.
+func WithAuthorSeriesOnly(enabled bool) ControllerOption {
+	return func(o *controllerOptions) {
+		o.authorSeriesOnly = enabled
+	}
+}
+
+// WithAuthorAliases serves the author keyed by the value whenever the author
+// keyed by the key is requested. Use this when upstream merges duplicate
+// authors and the old ID stops resolving.
+func WithAuthorAliases(aliases map[int64]int64) ControllerOption {
+	return func(o *controllerOptions) {
+		o.authorAliases = aliases
+	}
+}
+
+// SearchSignal is something search results can be ranked by.
+type SearchSignal string
+
+// Supported search ranking signals.
+const (
+	// SearchByTitle ranks results whose title exactly matches the query first.
+	SearchByTitle SearchSignal = "title"
+	// SearchByRatings ranks results with more ratings first.
+	SearchByRatings SearchSignal = "ratings"
+	// SearchByRecency ranks more recently released results first.
+	SearchByRecency SearchSignal = "recency"
+)
+
+// WithSearchRanking re-orders search results by the given signals. Earlier
+// signals take priority and later ones break ties.
+func WithSearchRanking(signals ...SearchSignal) ControllerOption {
+	return func(o *controllerOptions) {
+		o.searchRank = signals
+	}
+}
+
+// WithRefreshMemoryThreshold pauses new author refreshes while heap usage
+// exceeds the given fraction of the Go memory limit, resuming once it drops.
+// Values outside (0, 1] are ignored.
+func WithRefreshMemoryThreshold(fraction float64) ControllerOption {
+	return func(o *controllerOptions) {
+		if fraction > 0 && fraction <= 1 {
+			o.refreshMemoryThreshold = fraction
+		}
+	}
+}
+
+// WithAuthorStubs returns a minimal author (name and image, no works) on a
+// cold cache instead of blocking on the full author. The full author is loaded
+// in the background and replaces the stub once it's ready.
+func WithAuthorStubs(enabled bool) ControllerOption {
+	return func(o *controllerOptions) {
+		o.authorStubs = enabled
+	}
+}
+
+// WithDenormWorkers sets how many parents (authors or works) are denormalized
+// in parallel. Edges for the same parent are always handled serially. Values
+// below 1 are ignored. Changes take effect on restart.
+func WithDenormWorkers(n int) ControllerOption {
+	return func(o *controllerOptions) {
+		if n >= 1 {
+			o.denormWorkers = n
+		}
+	}
+}
+
+// WithWorkAuthors corrects the primary author of works which are misattributed
+// upstream. Overrides are keyed by work ID.
+func WithWorkAuthors(overrides map[int64]int64) ControllerOption {
+	return func(o *controllerOptions) {
+		o.workAuthors = overrides
+	}
+}
+
+// WithContinuingMonths sets how recently an author must have released a work
+// to be marked as continuing. Zero disables it, and negative values are
+// ignored.
+func WithContinuingMonths(n int) ControllerOption {
+	return func(o *controllerOptions) {
+		if n >= 0 {
+			o.continuingMonths = n
+		}
+	}
+}
+
+// WithSearchISBN includes the ISBN-13 of each search result's edition, so
+// clients can match results against their library. Every result's edition is
+// resolved, which is slower on a cold cache.
+func WithSearchISBN(enabled bool) ControllerOption {
+	return func(o *controllerOptions) {
+		o.searchISBN = enabled
+	}
+}
+
+// WithEditionsPerPass caps how many editions are added to a work in a single
+// denormalization pass. Larger batches are split across passes so each one
+// finishes within its timeout instead of being cancelled partway. Zero means
+// unlimited and negative values are ignored.
+func WithEditionsPerPass(n int) ControllerOption {
+	return func(o *controllerOptions) {
+		if n >= 0 {
+			o.editionsPerPass = n
+		}
+	}
+}
+
+// WithKeepEmptyWorks keeps works on their author when all of their editions
+// were filtered out, instead of dropping them. They're listed without any
+// editions.
+func WithKeepEmptyWorks(keep bool) ControllerOption {
+	return func(o *controllerOptions) {
+		o.keepEmptyWorks = keep
+	}
+}
+
+// WithRevalidateWorkers bounds how many of a work's cached editions are
+// re-fetched in parallel when the work is refreshed. Non-positive values are
+// ignored.
+func WithRevalidateWorkers(n int) ControllerOption {
+	return func(o *controllerOptions) {
+		if n > 0 {
+			o.revalidateWorkers = n
+		}
+	}
+}
+
+// WithSeriesSubtitles controls whether works in a series always include their
+// subtitle, like "Baz: The Baz Series #3". When disabled, series works only
+// include it if another of the author's works shares their title.
+func WithSeriesSubtitles(always bool) ControllerOption {
+	return func(o *controllerOptions) {
+		o.seriesSubtitles = always
+	}
+}
+
+// WithBackgroundWorkers caps how many goroutines the controller spawns for
+// background work at once. Work beyond the cap is dropped. Non-positive values
+// are ignored.
+func WithBackgroundWorkers(n int) ControllerOption {
+	return func(o *controllerOptions) {
+		if n > 0 {
+			o.backgroundWorkers = n
+		}
+	}
+}
+
+// WithMinEditionYear excludes editions released before the given year from a
+// work's editions, since they're almost always data errors (e.g. year 1). The
+// best edition is always kept and the work's own release date is unaffected.
+// Non-positive values disable it.
+func WithMinEditionYear(year int) ControllerOption {
+	return func(o *controllerOptions) {
+		o.minEditionYear = year
+	}
+}
+
+// WithExcludeOmnibus excludes box sets and omnibus editions (e.g. "The
+// Complete Trilogy", "Books 1-3") from a work's editions, since they're
+// usually attached to the first book of a series. They can still be fetched
+// directly, and the best edition is always kept.
+func WithExcludeOmnibus(exclude bool) ControllerOption {
+	return func(o *controllerOptions) {
+		o.excludeOmnibus = exclude
+	}
+}
+
+// WithNotifyURL POSTs a {"type", "foreignId"} notification to url whenever an
+// author or work changes, so clients can refresh it without waiting for their
+// next poll. Updates are collected for the debounce interval and each
+// resource is only sent once per interval. Non-positive intervals are
+// ignored.
+func WithNotifyURL(url string, debounce time.Duration) ControllerOption {
+	return func(o *controllerOptions) {
+		o.notifyURL = url
+		if debounce > 0 {
+			o.notifyDebounce = debounce
+		}
+	}
+}
+
+// WithServeStale serves data up to maxStale past its expiry when upstream
+// fails with a transient error, like a 5XX or timeout, instead of erroring.
+// Non-positive values disable it.
+func WithServeStale(maxStale time.Duration) ControllerOption {
+	return func(o *controllerOptions) {
+		o.maxStale = max(maxStale, 0)
+	}
+}
+
+// WithRestartBackoff sets how long a consumer which panicked initially waits
+// before it's restarted. The wait doubles with every consecutive panic, up to a
+// minute. Non-positive values are ignored.
+func WithRestartBackoff(backoff time.Duration) ControllerOption {
+	return func(o *controllerOptions) {
+		if backoff > 0 {
+			o.restartBackoff = backoff
+		}
+	}
+}
+
+// authorStubber is optionally implemented by getters which can return a
+// minimal author more cheaply than GetAuthor.
+type authorStubber interface {
+	GetAuthorStub(ctx context.Context, authorID int64) ([]byte, error)
+}
+
+// slugResolver is optionally implemented by getters whose works can be looked
+// up by the slug in their public URLs.
+type slugResolver interface {
+	GetWorkIDBySlug(ctx context.Context, slug string) (int64, error)
+}
+
+// hostServer is optionally implemented by getters to tell whether a public
+// URL's host belongs to their upstream. Upstreams don't share IDs, so URLs
+// from another one can't be resolved.
+type hostServer interface {
+	servesHost(host string) bool
+}
+
 // getter allows alternative implementations of the core logic to be injected.
 // Don't write to the cache if you use it.
 type getter interface {
@@ -130,21 +596,288 @@ type getter interface {
 	// A serialied searchResource is returned.
 	Search(ctx context.Context, query string) ([]SearchResource, error)
 
-	// Recommendations returns a list of work IDs which are trending or popular.
-	// Eventually we may consider implementing OAuth in order to return
-	// custom-tailored recommendations.
-	Recommendations(ctx context.Context, page int64) (RecommentationsResource, error)
+	// Recommendations returns a list of work IDs which are trending or popular,
+	// optionally narrowed by the filter. Eventually we may consider
+	// implementing OAuth in order to return custom-tailored recommendations.
+	//
+	// Pages are 1-based. A page below 1 is a bad request (see checkPage), and
+	// a page past the last one the upstream offers is empty rather than an
+	// error.
+	Recommendations(ctx context.Context, page int64, filter recommendationsFilter) (RecommentationsResource, error)
+}
+
+// checkPage enforces the 1-based paging contract shared by getters.
+func checkPage(page int64) error {
+	if page < 1 {
+		return errors.Join(fmt.Errorf("invalid page %d: pages start at 1", page), errBadRequest)
+	}
+	return nil
+}
+
+// recommendationsFilter narrows recommendations. The zero value returns
+// trending works.
+type recommendationsFilter struct {
+	genre string        // genre limits results to popular works in this genre.
+	seed  *workResource // seed limits results to works similar to this one.
+}
+
+// GetterOption configures optional behavior shared by getter implementations.
+type GetterOption func(*getterOptions)
+
+type getterOptions struct {
+	// skipEbooks excludes ebook editions when saving a work's editions. They
+	// can still be fetched directly.
+	skipEbooks bool
+
+	// audioFormats are the (lowercase) edition formats treated as audiobooks.
+	audioFormats set[string]
+
+	// audioNarrators keeps one audiobook edition per narrator instead of only
+	// the most popular one.
+	audioNarrators bool
+
+	// allEditions keeps every edition of a work instead of one per title,
+	// language and format.
+	allEditions bool
+
+	// poisonWorks, poisonBooks and poisonAuthors are IDs known to break the
+	// upstream. They're never requested.
+	poisonWorks   set[int64]
+	poisonBooks   set[int64]
+	poisonAuthors set[int64]
+
+	// emptyAuthors returns authors whose works are all filtered out with no
+	// works, instead of reporting them as not found.
+	emptyAuthors bool
+
+	// rawTTL is how long raw upstream responses are cached for debugging.
+	// Zero disables it.
+	rawTTL time.Duration
+
+	// kcaTTL is how long resolved author KCAs are cached. Zero disables it.
+	kcaTTL time.Duration
+
+	// authorProbe is how many of a cold author's works are considered when
+	// choosing their initial work.
+	authorProbe int
+
+	metrics *upstreamMetrics
+}
+
+// _audioFormats are the edition formats treated as audiobooks by default.
+var _audioFormats = []string{
+	"Audible Audio",
+	"Audio CD",
+	"MP3 CD",
+	"Audiobook",
+	"Audio Cassette",
+}
+
+// WithSkipEbooks excludes ebook editions from the editions saved for a work.
+func WithSkipEbooks(skip bool) GetterOption {
+	return func(o *getterOptions) {
+		o.skipEbooks = skip
+	}
+}
+
+// WithAudioFormats sets which edition formats are considered audiobooks.
+// Audiobooks are kept as distinct editions instead of being deduped with
+// print editions of the same title. Matching is case-insensitive.
+func WithAudioFormats(formats ...string) GetterOption {
+	return func(o *getterOptions) {
+		if len(formats) == 0 {
+			return
+		}
+		o.audioFormats = newSet[string]()
+		for _, f := range formats {
+			o.audioFormats[strings.ToLower(strings.TrimSpace(f))] = struct{}{}
+		}
+	}
+}
+
+// WithAudioNarrators keeps one audiobook edition per narrator, crediting the
+// narrator as a contributor. Otherwise only the most popular audiobook edition
+// of a title is kept.
+func WithAudioNarrators(enabled bool) GetterOption {
+	return func(o *getterOptions) {
+		o.audioNarrators = enabled
+	}
+}
+
+// WithAllEditions keeps every edition upstream returns for a work instead of
+// deduping similar editions, for cataloging. Works can become very large.
+func WithAllEditions(enabled bool) GetterOption {
+	return func(o *getterOptions) {
+		o.allEditions = enabled
+	}
+}
+
+// WithGetterMetrics registers the getter's upstream metrics.
+func WithGetterMetrics(reg *prometheus.Registry) GetterOption {
+	return func(o *getterOptions) {
+		o.metrics = newUpstreamMetrics(reg)
+	}
+}
+
+// WithPoisonIDs prevents upstream requests for works, books (editions) and
+// authors known to break the upstream. They're treated as not found instead.
+func WithPoisonIDs(workIDs, bookIDs, authorIDs []int64) GetterOption {
+	return func(o *getterOptions) {
+		o.poisonWorks = union(o.poisonWorks, newSet(workIDs...))
+		o.poisonBooks = union(o.poisonBooks, newSet(bookIDs...))
+		o.poisonAuthors = union(o.poisonAuthors, newSet(authorIDs...))
+	}
+}
+
+// WithEmptyAuthors returns authors who exist but have no valid works, for
+// example because they're only credited as an editor or co-author, with empty
+// works instead of reporting them as not found. This lets them be added.
+func WithEmptyAuthors(enabled bool) GetterOption {
+	return func(o *getterOptions) {
+		o.emptyAuthors = enabled
+	}
+}
+
+// WithRawResponses caches raw upstream responses for the given duration,
+// alongside the resources mapped from them. This helps tell mapping bugs apart
+// from bad upstream data. Non-positive durations disable it.
+func WithRawResponses(ttl time.Duration) GetterOption {
+	return func(o *getterOptions) {
+		o.rawTTL = ttl
+	}
+}
+
+// _authorKCATTL is how long resolved author KCAs are cached by default. They
+// essentially never change.
+var _authorKCATTL = 90 * 24 * time.Hour
+
+// WithAuthorKCATTL caches an author's resolved KCA for the given duration, so
+// cold author loads don't need to resolve it again. Non-positive durations
+// disable it.
+func WithAuthorKCATTL(ttl time.Duration) GetterOption {
+	return func(o *getterOptions) {
+		o.kcaTTL = ttl
+	}
+}
+
+// _authorProbe is how many of a cold author's works are considered for their
+// initial work by default.
+var _authorProbe = 20
+
+// WithAuthorProbe sets how many of a cold author's works are considered when
+// choosing the initial work returned before the full author loads. The most
+// popular of them by the author wins. Non-positive values use the default.
+func WithAuthorProbe(n int) GetterOption {
+	return func(o *getterOptions) {
+		if n <= 0 {
+			n = _authorProbe
+		}
+		o.authorProbe = n
+	}
+}
+
+// keepRaw caches the upstream response behind the resource with the given
+// key, if enabled.
+func (o getterOptions) keepRaw(ctx context.Context, c cache[[]byte], key string, resp any) {
+	if o.rawTTL <= 0 {
+		return
+	}
+	out, err := json.Marshal(resp)
+	if err != nil {
+		Log(ctx).Debug("problem marshaling raw response", "err", err, "key", key)
+		return
+	}
+	c.Set(ctx, RawKey(key), out, o.rawTTL)
+}
+
+func newGetterOptions(opts ...GetterOption) getterOptions {
+	o := getterOptions{
+		poisonWorks:   newSet[int64](),
+		poisonBooks:   newSet[int64](),
+		poisonAuthors: newSet[int64](),
+		metrics:       newUpstreamMetrics(nil),
+		kcaTTL:        _authorKCATTL,
+		authorProbe:   _authorProbe,
+	}
+	WithAudioFormats(_audioFormats...)(&o)
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// isAudio returns true if the edition format is an audiobook format.
+func (o getterOptions) isAudio(format string) bool {
+	_, ok := o.audioFormats[strings.ToLower(strings.TrimSpace(format))]
+	return ok
+}
+
+// isEbook returns true if the edition format is an ebook format.
+func isEbook(format string) bool {
+	switch strings.ToLower(strings.TrimSpace(format)) {
+	case "ebook", "kindle edition":
+		return true
+	}
+	return false
+}
+
+// isNarrator returns true if the contributor role credits a narrator.
+func isNarrator(role string) bool {
+	switch strings.ToLower(strings.TrimSpace(role)) {
+	case "narrator", "reading", "read by", "reader":
+		return true
+	}
+	return false
+}
+
+// poisoned returns errNotFound if the ID is known to break the upstream.
+func poisoned(ids set[int64], id int64) error {
+	if _, ok := ids[id]; ok {
+		return errors.Join(errNotFound, fmt.Errorf("poisoned ID %d", id))
+	}
+	return nil
+}
+
+// TransportOption tunes the connection pool of an upstream transport.
+type TransportOption func(*http.Transport)
+
+// WithIdleConns configures how many idle connections are kept for reuse, in
+// total and per host, and how long they're kept around. The default of 2 idle
+// connections per host bottlenecks background refreshes.
+func WithIdleConns(maxIdle, maxIdlePerHost int, timeout time.Duration) TransportOption {
+	return func(t *http.Transport) {
+		t.MaxIdleConns = maxIdle
+		t.MaxIdleConnsPerHost = maxIdlePerHost
+		t.IdleConnTimeout = timeout
+	}
+}
+
+// WithProxy sends requests through the given HTTP proxy.
+func WithProxy(proxy *url.URL) TransportOption {
+	return func(t *http.Transport) {
+		t.Proxy = http.ProxyURL(proxy)
+	}
+}
+
+// NewTransport returns a dedicated transport based on http.DefaultTransport.
+func NewTransport(opts ...TransportOption) *http.Transport {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	for _, opt := range opts {
+		opt(t)
+	}
+	return t
 }
 
 // NewUpstream creates a new http.Client with middleware appropriate for use
 // with an upstream.
-func NewUpstream(host string, proxy string) (*http.Client, error) {
+func NewUpstream(host string, proxy string, opts ...TransportOption) (*http.Client, error) {
+	transport := NewTransport(opts...)
 	upstream := &http.Client{
 		Transport: throttledTransport{
 			ticker: time.NewTicker(time.Second / 3),
 			RoundTripper: ScopedTransport{
 				Host:         host,
-				RoundTripper: errorProxyTransport{http.DefaultTransport},
+				RoundTripper: errorProxyTransport{otelhttp.NewTransport(transport)},
 			},
 		},
 		CheckRedirect: func(req *http.Request, _ []*http.Request) error {
@@ -161,8 +894,7 @@ func NewUpstream(host string, proxy string) (*http.Client, error) {
 		if err != nil {
 			return nil, fmt.Errorf("invalid proxy url: %w", err)
 		}
-		// TODO: This doesn't work.
-		upstream.Transport.(*http.Transport).Proxy = http.ProxyURL(url)
+		WithProxy(url)(transport)
 	}
 
 	return upstream, nil
@@ -170,7 +902,7 @@ func NewUpstream(host string, proxy string) (*http.Client, error) {
 
 // NewController creates a new controller. Background jobs to load author works
 // and editions is bounded to at most 10 concurrent tasks.
-func NewController(cache cache[[]byte], getter getter, persister persister, reg *prometheus.Registry) (*Controller, error) {
+func NewController(cache cache[[]byte], getter getter, persister persister, reg *prometheus.Registry, opts ...ControllerOption) (*Controller, error) {
 	metrics := newControllerMetrics(reg)
 	c := &Controller{
 		cache:     cache,
@@ -180,44 +912,124 @@ func NewController(cache cache[[]byte], getter getter, persister persister, reg
 
 		denormC:  make(chan edge),
 		refreshC: make(chan refreshAuthor),
+		notifier: newNotifier(),
+		decoded:  newLRU[int64, decodedAuthor](_decodedAuthors),
+		codec:    sonic.ConfigStd,
+		tracer:   newTracer(),
 	}
 	if persister != nil {
 		c.persister = persister
 	}
+	c.opts.Store(newControllerOptions(opts...))
 
 	c.refreshG.SetLimit(30)
 	c.workG.SetLimit(25) // Sure why not.
+	c.saveG.SetLimit(10)
 
 	return c, nil
 }
 
+// Reconfigure replaces the controller's options. Options not given revert to
+// their defaults. In-flight operations may still observe the old options.
+func (c *Controller) Reconfigure(opts ...ControllerOption) {
+	c.opts.Store(newControllerOptions(opts...))
+}
+
+// options returns the controller's current options.
+func (c *Controller) options() *controllerOptions {
+	return c.opts.Load()
+}
+
+// coalesce runs fn once for concurrent callers with the same key. A panic in
+// fn is recovered and returned to every caller as an error, so one bad
+// resource can't take down everyone waiting on it.
+func coalesce[T any](ctx context.Context, c *Controller, key string, fn func() (T, error)) (T, error) {
+	out, err, _ := c.group.Do(key, func() (_ any, err error) {
+		c.metrics.inflightKeysAdd(1)
+		defer c.metrics.inflightKeysAdd(-1)
+		defer func() {
+			if r := recover(); r != nil {
+				c.metrics.coalescedPanicsInc()
+				Log(ctx).Error("panic", "key", key, "details", r, "stack", string(debug.Stack()))
+				err = fmt.Errorf("panic loading %q: %v", key, r)
+			}
+		}()
+		return fn()
+	})
+	v, _ := out.(T)
+	return v, err
+}
+
+// spawn runs fn on a new goroutine without blocking the caller. Every
+// fire-and-forget goroutine shares one budget of backgroundWorkers, so a spike
+// in traffic can't pile up an unbounded number of them. If the budget is
+// exhausted fn is dropped and false is returned. Callers whose work produces
+// denormalization edges must fall back to enqueueing them.
+func (c *Controller) spawn(ctx context.Context, name string, fn func()) bool {
+	if c.background.Add(1) > int64(c.options().backgroundWorkers) {
+		c.background.Add(-1)
+		c.metrics.backgroundDroppedInc()
+		Log(ctx).Debug("dropping background work", "name", name)
+		return false
+	}
+	c.metrics.backgroundAdd(1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				Log(ctx).Error("panic", "name", name, "details", r, "stack", string(debug.Stack()))
+			}
+			c.background.Add(-1)
+			c.metrics.backgroundAdd(-1)
+		}()
+		fn()
+	}()
+	return true
+}
+
+// enqueue hands edges to the denormalization loop without blocking the
+// caller. Unlike other background work the edges can't be dropped, so they're
+// sent inline if the background budget is exhausted.
+func (c *Controller) enqueue(ctx context.Context, edges ...edge) {
+	send := func() {
+		for _, e := range edges {
+			c.denormC <- e
+		}
+	}
+	if !c.spawn(ctx, "enqueue", send) {
+		send()
+	}
+}
+
 // GetBook loads a book (edition) or returns a cached value if one exists.
 // TODO: This should only return a book!
-func (c *Controller) GetBook(ctx context.Context, bookID int64) ([]byte, time.Duration, error) {
-	p, err, _ := c.group.Do(BookKey(bookID), func() (any, error) {
+func (c *Controller) GetBook(ctx context.Context, bookID int64) (_ []byte, _ time.Duration, err error) {
+	ctx, span := startSpan(ctx, c.tracer, "GetBook", attribute.Int64("book.id", bookID))
+	defer func() { endSpan(span, err) }()
+
+	pair, err := coalesce(ctx, c, BookKey(bookID), func() (ttlpair, error) {
 		return c.getBook(ctx, bookID)
 	})
-	pair := p.(ttlpair)
 	return pair.bytes, pair.ttl, err
 }
 
 // Search queries the metadata provider.
 func (c *Controller) Search(ctx context.Context, query string) ([]SearchResource, error) {
-	if _asin.Match([]byte(query)) {
+	if asin := normalizeASIN(query); _asin.MatchString(asin) {
 		// Try an ASIN lookup and fall back to regular search if that doesn't work.
-		if results := c.searchASIN(ctx, query); len(results) > 0 {
-			return results, nil
+		if results := c.searchASIN(ctx, asin); len(results) > 0 {
+			return c.withISBN13(ctx, results), nil
 		}
 	}
-	if isbn, err := isbn.Parse(query); err == nil && isbn != nil {
+	if isbn, err := parseISBN(query); err == nil && isbn != nil {
 		if results := c.searchISBN(ctx, *isbn); len(results) > 0 {
-			return results, nil
+			return c.withISBN13(ctx, results), nil
 		}
 	}
 	results, err := c.getter.Search(ctx, query)
 	if err != nil {
 		return nil, err
 	}
+	results = append(c.searchAlias(ctx, query), results...)
 
 	seenWorks := map[int64]struct{}{}
 	seenBooks := map[int64]struct{}{}
@@ -233,12 +1045,147 @@ func (c *Controller) Search(ctx context.Context, query string) ([]SearchResource
 		seenWorks[r.WorkID] = struct{}{}
 		deduped = append(deduped, r)
 	}
-	return deduped, nil
+	rankSearch(deduped, query, c.options().searchRank)
+	return c.withISBN13(ctx, deduped), nil
 }
 
-// Recommendations returns recommended work IDs.
-func (c *Controller) Recommendations(ctx context.Context, page int64) (RecommentationsResource, error) {
-	recs, err := c.getter.Recommendations(ctx, page)
+// _aliasSearchResults is how many of an author's works are returned when a
+// search matches one of their aliases.
+const _aliasSearchResults = 10
+
+// searchAlias returns the most popular works of the author known to publish
+// under the given name, if any. Upstream search doesn't connect pseudonyms to
+// the author behind them.
+func (c *Controller) searchAlias(ctx context.Context, name string) []SearchResource {
+	bytes, ok := c.cache.Get(ctx, aliasKey(name))
+	if !ok {
+		return nil
+	}
+	var alias SearchResourceAuthor
+	if err := json.Unmarshal(bytes, &alias); err != nil || alias.ID == 0 {
+		return nil
+	}
+
+	authorBytes, _, err := c.GetAuthor(ctx, alias.ID)
+	if err != nil {
+		Log(ctx).Debug("problem loading aliased author", "err", err, "authorID", alias.ID)
+		return nil
+	}
+	var author AuthorResource
+	if err := json.Unmarshal(authorBytes, &author); err != nil {
+		return nil
+	}
+
+	works := slices.Clone(author.Works)
+	slices.SortStableFunc(works, func(a, b workResource) int {
+		return -cmp.Compare(a.RatingCount, b.RatingCount)
+	})
+	results := []SearchResource{}
+	for _, w := range works {
+		if len(results) >= _aliasSearchResults {
+			break
+		}
+		if w.BestBookID == 0 {
+			continue
+		}
+		results = append(results, SearchResource{
+			BookID:      w.BestBookID,
+			WorkID:      w.ForeignID,
+			Author:      SearchResourceAuthor{ID: alias.ID},
+			title:       w.Title,
+			ratingCount: w.RatingCount,
+			releaseDate: w.ReleaseDate,
+		})
+	}
+	return results
+}
+
+// setAlias records that the author publishes under the given name.
+func (c *Controller) setAlias(ctx context.Context, name string, authorID int64) {
+	if strings.TrimSpace(name) == "" {
+		return
+	}
+	bytes, err := json.Marshal(SearchResourceAuthor{ID: authorID})
+	if err != nil {
+		return
+	}
+	c.cache.Set(ctx, aliasKey(name), bytes, fuzz(_authorTTL, 1.5))
+}
+
+// withISBN13 fills in each search result's ISBN-13 from its edition, if
+// enabled. Results whose edition can't be loaded are left without one.
+func (c *Controller) withISBN13(ctx context.Context, results []SearchResource) []SearchResource {
+	if !c.options().searchISBN {
+		return results
+	}
+
+	wg := sync.WaitGroup{}
+	for i := range results {
+		wg.Go(func() {
+			out, _, err := c.GetBook(ctx, results[i].BookID)
+			if err != nil {
+				return
+			}
+			var work workResource
+			if err := json.Unmarshal(out, &work); err != nil {
+				return
+			}
+			for _, b := range work.Books {
+				if b.ForeignID == results[i].BookID {
+					results[i].ISBN13 = b.Isbn13
+					break
+				}
+			}
+		})
+	}
+	wg.Wait()
+
+	return results
+}
+
+// rankSearch stably sorts search results by the given signals.
+func rankSearch(results []SearchResource, query string, signals []SearchSignal) {
+	if len(signals) == 0 {
+		return
+	}
+	query = strings.TrimSpace(query)
+	slices.SortStableFunc(results, func(a, b SearchResource) int {
+		for _, signal := range signals {
+			var c int
+			switch signal {
+			case SearchByTitle:
+				c = compareBool(strings.EqualFold(a.title, query), strings.EqualFold(b.title, query))
+			case SearchByRatings:
+				c = -cmp.Compare(a.ratingCount, b.ratingCount)
+			case SearchByRecency:
+				c = -cmp.Compare(a.releaseDate, b.releaseDate)
+			}
+			if c != 0 {
+				return c
+			}
+		}
+		return 0
+	})
+}
+
+// Recommendations returns recommended work IDs. If a genre is given only
+// works in that genre are returned, and if a seed work ID is given only works
+// similar to it are returned.
+func (c *Controller) Recommendations(ctx context.Context, page int64, genre string, seedID int64) (RecommentationsResource, error) {
+	filter := recommendationsFilter{genre: genre}
+	if seedID != 0 {
+		out, _, err := c.GetWork(ctx, seedID)
+		if err != nil {
+			return RecommentationsResource{}, fmt.Errorf("getting seed work: %w", err)
+		}
+		var seed workResource
+		if err := json.Unmarshal(out, &seed); err != nil {
+			return RecommentationsResource{}, fmt.Errorf("unmarshaling seed work: %w", err)
+		}
+		filter.seed = &seed
+	}
+
+	recs, err := c.getter.Recommendations(ctx, page, filter)
 	if err != nil {
 		return recs, err
 	}
@@ -249,8 +1196,10 @@ func (c *Controller) Recommendations(ctx context.Context, page int64) (Recomment
 	workIDs := []int64{}
 
 	for _, workID := range recs.WorkIDs {
-		wg.Add(1)
-		go func() {
+		if filter.seed != nil && workID == filter.seed.ForeignID {
+			continue // Don't recommend the seed to itself.
+		}
+		wg.Go(func() {
 			_, _, err := c.GetWork(ctx, workID)
 			if err != nil {
 				return
@@ -258,8 +1207,9 @@ func (c *Controller) Recommendations(ctx context.Context, page int64) (Recomment
 			mu.Lock()
 			defer mu.Unlock()
 			workIDs = append(workIDs, workID)
-		}()
+		})
 	}
+	wg.Wait()
 	recs.WorkIDs = workIDs
 	return recs, nil
 }
@@ -317,42 +1267,49 @@ func (c *Controller) searchISBN(ctx context.Context, isbn isbn.ISBN) []SearchRes
 }
 
 // GetWork loads a work or returns a cached value if one exists.
-func (c *Controller) GetWork(ctx context.Context, workID int64) ([]byte, time.Duration, error) {
-	p, err, _ := c.group.Do(WorkKey(workID), func() (any, error) {
+func (c *Controller) GetWork(ctx context.Context, workID int64) (_ []byte, _ time.Duration, err error) {
+	ctx, span := startSpan(ctx, c.tracer, "GetWork", attribute.Int64("work.id", workID))
+	defer func() { endSpan(span, err) }()
+
+	pair, err := coalesce(ctx, c, WorkKey(workID), func() (ttlpair, error) {
 		return c.getWork(ctx, workID)
 	})
-	pair := p.(ttlpair)
 	return pair.bytes, pair.ttl, err
 }
 
 // GetAuthor loads an author or returns a cached value if one exists.
-func (c *Controller) GetAuthor(ctx context.Context, authorID int64) ([]byte, time.Duration, error) {
+func (c *Controller) GetAuthor(ctx context.Context, authorID int64) (_ []byte, _ time.Duration, err error) {
+	ctx, span := startSpan(ctx, c.tracer, "GetAuthor", attribute.Int64("author.id", authorID))
+	defer func() { endSpan(span, err) }()
+
+	if canonicalID, ok := c.options().authorAliases[authorID]; ok {
+		Log(ctx).Info("redirecting aliased author", "authorID", authorID, "canonicalID", canonicalID)
+		authorID = canonicalID
+	}
 	// The "unknown author" ID is never loadable, so we can short-circuit.
 	if unknownAuthor(authorID) {
 		return nil, _missingTTL, errNotFound
 	}
-	p, err, _ := c.group.Do(AuthorKey(authorID), func() (any, error) {
+	pair, err := coalesce(ctx, c, AuthorKey(authorID), func() (ttlpair, error) {
 		return c.getAuthor(ctx, authorID)
 	})
-	pair := p.(ttlpair)
 	return pair.bytes, pair.ttl, err
 }
 
 // GetSeries returns a cached series if one exists.
 func (c *Controller) GetSeries(ctx context.Context, seriesID int64) ([]byte, error) {
-	out, err, _ := c.group.Do(seriesKey(seriesID), func() (any, error) {
+	return coalesce(ctx, c, seriesKey(seriesID), func() ([]byte, error) {
 		return c.getSeries(ctx, seriesID)
 	})
-	return out.([]byte), err
 }
 
 // GetASIN returns the best known edition ID for the given ASIN, or a not found
 // error if there is none.
 func (c *Controller) GetASIN(ctx context.Context, asin string) (int64, error) {
-	out, err, _ := c.group.Do(asin, func() (any, error) {
+	asin = normalizeASIN(asin)
+	return coalesce(ctx, c, asin, func() (int64, error) {
 		return c.getASIN(ctx, asin)
 	})
-	return out.(int64), err
 }
 
 func (c *Controller) getASIN(ctx context.Context, asin string) (int64, error) {
@@ -382,10 +1339,9 @@ func (c *Controller) setASIN(ctx context.Context, asin string, editionID int64)
 // GetISBN returns the best known edition ID for the given ISBN13, or a not found
 // error if there is none.
 func (c *Controller) GetISBN(ctx context.Context, isbn isbn.ISBN) (int64, error) {
-	out, err, _ := c.group.Do(isbn.Canonical(), func() (any, error) {
+	return coalesce(ctx, c, isbn.Canonical(), func() (int64, error) {
 		return c.getISBN(ctx, isbn)
 	})
-	return out.(int64), err
 }
 
 func (c *Controller) getISBN(ctx context.Context, isbn isbn.ISBN) (int64, error) {
@@ -403,6 +1359,49 @@ func (c *Controller) getISBN(ctx context.Context, isbn isbn.ISBN) (int64, error)
 	return asinRsc.EditionID, nil
 }
 
+// GetWorkIDBySlug returns the ID of the work with the given URL slug. A bad
+// request is returned if the getter doesn't use slugs.
+func (c *Controller) GetWorkIDBySlug(ctx context.Context, slug string) (int64, error) {
+	resolver, ok := c.getter.(slugResolver)
+	if !ok {
+		return 0, errors.Join(errBadRequest, errors.ErrUnsupported)
+	}
+	return coalesce(ctx, c, "slug:"+slug, func() (int64, error) {
+		return resolver.GetWorkIDBySlug(ctx, slug)
+	})
+}
+
+// servesHost returns true if a public URL with the given host can be
+// resolved against our getter. Getters which don't say are assumed to serve
+// any host.
+func (c *Controller) servesHost(host string) bool {
+	hs, ok := c.getter.(hostServer)
+	if !ok {
+		return true
+	}
+	return hs.servesHost(strings.ToLower(strings.TrimSuffix(host, ".")))
+}
+
+// getStale returns expired data for key if err looks like an upstream outage
+// and the data hasn't been expired for longer than we're willing to serve.
+func (c *Controller) getStale(ctx context.Context, key string, err error) ([]byte, bool) {
+	maxStale := c.options().maxStale
+	if maxStale <= 0 || errors.Is(err, errNotFound) || !transient(err) {
+		return nil, false
+	}
+	sg, ok := c.cache.(staleGetter)
+	if !ok {
+		return nil, false
+	}
+	out, age, ok := sg.GetStale(ctx, key)
+	if !ok || age > maxStale || slices.Equal(out, _missing) {
+		return nil, false
+	}
+	Log(ctx).Warn("serving stale data", "key", key, "age", age, "err", err)
+	c.metrics.staleServedInc()
+	return out, true
+}
+
 func (c *Controller) setISBN(ctx context.Context, isbn isbn.ISBN, editionID int64) error {
 	bytes, err := json.Marshal(lookupResource{EditionID: editionID})
 	if err != nil {
@@ -432,12 +1431,17 @@ func (c *Controller) getBook(ctx context.Context, bookID int64) (ttlpair, error)
 		return ttlpair{}, err
 	}
 
+	if _, ok := c.options().workAuthors[workID]; ok {
+		workBytes, authorID = c.overrideWorkAuthorBytes(ctx, workBytes, authorID)
+	}
+
+	workBytes = c.guardWorkBytes(ctx, workID, workBytes)
 	ttl = fuzz(_editionTTL, 2.0)
 	c.cache.Set(ctx, BookKey(bookID), workBytes, ttl)
 
 	if workID > 0 {
 		// Ensure the edition/book is included with the work, but don't block the response.
-		go func() {
+		spawned := c.spawn(ctx, "book-work", func() {
 			// Decouple our context from the request.
 			ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), time.Minute)
 			defer cancel()
@@ -462,12 +1466,50 @@ func (c *Controller) getBook(ctx context.Context, bookID int64) (ttlpair, error)
 				}
 			}
 			c.denormC <- edge{kind: workEdge, parentID: workID, childIDs: newSet(bookID)}
-		}()
+		})
+		if !spawned {
+			// We're too busy to fetch the work and author first, but the edge
+			// can't be dropped. Denormalization fetches whatever it needs.
+			c.enqueue(ctx, edge{kind: workEdge, parentID: workID, childIDs: newSet(bookID)})
+		}
 	}
 
 	return ttlpair{bytes: workBytes, ttl: ttl}, nil
 }
 
+// revalidateEditions ensures a work's previously cached editions are still
+// fetched, returning the IDs of those which are. Editions still within their
+// TTL are kept as-is and the rest are re-fetched in parallel.
+func (c *Controller) revalidateEditions(ctx context.Context, books []bookResource) []int64 {
+	var mu sync.Mutex
+	bookIDs := []int64{}
+	keep := func(bookID int64) {
+		mu.Lock()
+		defer mu.Unlock()
+		bookIDs = append(bookIDs, bookID)
+	}
+
+	var g errgroup.Group
+	g.SetLimit(c.options().revalidateWorkers)
+	for _, b := range books {
+		if cachedBytes, ttl, ok := c.cache.GetWithTTL(ctx, BookKey(b.ForeignID)); ok && ttl > 0 && !slices.Equal(cachedBytes, _missing) {
+			c.metrics.editionsRevalidationSkippedInc()
+			keep(b.ForeignID)
+			continue
+		}
+		g.Go(func() error {
+			c.metrics.editionsRevalidatedInc()
+			if _, _, err := c.GetBook(ctx, b.ForeignID); err == nil {
+				keep(b.ForeignID)
+			}
+			return nil
+		})
+	}
+	_ = g.Wait()
+
+	return bookIDs
+}
+
 func (c *Controller) getWork(ctx context.Context, workID int64) (ttlpair, error) {
 	cachedBytes, ttl, ok := c.cache.GetWithTTL(ctx, WorkKey(workID))
 	if ok && ttl > 0 {
@@ -488,11 +1530,16 @@ func (c *Controller) getWork(ctx context.Context, workID int64) (ttlpair, error)
 		return ttlpair{}, err
 	}
 
+	if _, ok := c.options().workAuthors[workID]; ok {
+		workBytes, authorID = c.overrideWorkAuthorBytes(ctx, workBytes, authorID)
+	}
+	workBytes = c.guardWorkBytes(ctx, workID, workBytes)
+
 	ttl = fuzz(_workTTL, 1.5)
 	c.cache.Set(ctx, WorkKey(workID), workBytes, ttl)
 
 	// Ensuring relationships doesn't block.
-	go func() {
+	spawned := c.spawn(ctx, "refresh-work", func() {
 		c.workG.Go(func() error {
 			ctx := context.WithValue(context.Background(), middleware.RequestIDKey, fmt.Sprintf("refresh-work-%d", workID))
 
@@ -506,12 +1553,7 @@ func (c *Controller) getWork(ctx context.Context, workID int64) (ttlpair, error)
 			var cached workResource
 			_ = json.Unmarshal(cachedBytes, &cached)
 
-			cachedBookIDs := []int64{}
-			for _, b := range cached.Books {
-				if _, _, err := c.GetBook(ctx, b.ForeignID); err == nil { // Ensure fetched.
-					cachedBookIDs = append(cachedBookIDs, b.ForeignID)
-				}
-			}
+			cachedBookIDs := c.revalidateEditions(ctx, cached.Books)
 
 			if authorID > 0 {
 				_, _, _ = c.GetAuthor(ctx, authorID) // Ensure fetched.
@@ -525,7 +1567,22 @@ func (c *Controller) getWork(ctx context.Context, workID int64) (ttlpair, error)
 			}
 			return nil
 		})
-	}()
+	})
+	if !spawned {
+		// We're too busy to revalidate editions first, but the edges can't be
+		// dropped. Denormalization fetches whatever it needs.
+		var cached workResource
+		_ = json.Unmarshal(cachedBytes, &cached)
+		cachedBookIDs := make([]int64, 0, len(cached.Books))
+		for _, b := range cached.Books {
+			cachedBookIDs = append(cachedBookIDs, b.ForeignID)
+		}
+		edges := []edge{{kind: workEdge, parentID: workID, childIDs: newSet(cachedBookIDs...)}}
+		if authorID > 0 {
+			edges = append(edges, edge{kind: authorEdge, parentID: authorID, childIDs: newSet(workID)})
+		}
+		c.enqueue(ctx, edges...)
+	}
 
 	// Return the last cached value to give the refresh time to complete.
 	if len(cachedBytes) > 0 {
@@ -562,12 +1619,16 @@ func (c *Controller) getSeries(ctx context.Context, seriesID int64) ([]byte, err
 	return out, nil
 }
 
+// saveEditions caches editions in the background and schedules them for
+// denormalization onto their work. It blocks if too many saves are already in
+// flight.
 func (c *Controller) saveEditions(grBooks ...workResource) {
-	go func() {
+	c.saveG.Go(func() error {
 		ctx := context.WithValue(context.Background(), middleware.RequestIDKey, fmt.Sprintf("save-editions-%d", time.Now().Unix()))
 
 		var grWorkID int64
 		grBookIDs := []int64{}
+		excluded := 0
 
 		for _, w := range grBooks {
 			if len(w.Books) != 1 {
@@ -598,13 +1659,13 @@ func (c *Controller) saveEditions(grBooks ...workResource) {
 			}
 			book := w.Books[0]
 
-			if book.Asin != "" && _asin.Match([]byte(book.Asin)) {
-				Log(ctx).Debug("found asin", "editionID", book.ForeignID, "asin", book.Asin)
-				if err := c.setASIN(ctx, book.Asin, book.ForeignID); err != nil {
+			if asin := normalizeASIN(book.Asin); asin != "" && _asin.MatchString(asin) {
+				Log(ctx).Debug("found asin", "editionID", book.ForeignID, "asin", asin)
+				if err := c.setASIN(ctx, asin, book.ForeignID); err != nil {
 					Log(ctx).Warn("problem persisting asin", "editionID", book.ForeignID, "asin", book.Asin)
 				}
 			}
-			if isbn, err := isbn.Parse(book.Isbn13); err == nil && isbn != nil {
+			if isbn, err := parseISBN(book.Isbn13); err == nil && isbn != nil {
 				if err := c.setISBN(ctx, *isbn, book.ForeignID); err != nil {
 					Log(ctx).Warn("problem persisting isbn", "editionID", book.ForeignID, "isbn", book.Isbn13)
 				}
@@ -614,24 +1675,34 @@ func (c *Controller) saveEditions(grBooks ...workResource) {
 				Log(ctx).Warn("missing contributors", "workID", w.ForeignID, "editionID", book.ForeignID)
 				continue
 			}
-			if book.Contributors[0].ForeignID != authorID {
-				continue // Skip editions not attributed to this author.
+			if workAuthorID := book.Contributors[0].ForeignID; workAuthorID != authorID {
+				if !c.options().relaxEditionAuthors || !slices.Contains(book.contributorIDs, workAuthorID) {
+					excluded++
+					c.metrics.editionsExcludedInc()
+					continue // Skip editions not attributed to this author.
+				}
 			}
 
 			out, err := json.Marshal(w)
 			if err != nil {
 				continue
 			}
+			out = c.guardWorkBytes(ctx, w.ForeignID, out)
 			c.cache.Set(ctx, BookKey(book.ForeignID), out, fuzz(_editionTTL, 2.0))
 			grBookIDs = append(grBookIDs, book.ForeignID)
 		}
 
+		if excluded > 0 {
+			Log(ctx).Debug("excluded editions with mismatched authors", "workID", grWorkID, "count", excluded)
+		}
+
 		if grWorkID == 0 || len(grBookIDs) == 0 {
-			return // Shouldn't happen.
+			return nil // Shouldn't happen.
 		}
 
 		c.denormC <- edge{kind: workEdge, parentID: grWorkID, childIDs: newSet(grBookIDs...)}
-	}()
+		return nil
+	})
 }
 
 // getAuthor returns an AuthorResource with up to 20 works populated on first
@@ -645,6 +1716,8 @@ func (c *Controller) getAuthor(ctx context.Context, authorID int64) (ttlpair, er
 		if slices.Equal(preRefreshBytes, _missing) {
 			return ttlpair{}, errNotFound
 		}
+		// This only limits how long clients cache the response. The key
+		// itself lives until the refresh finishes.
 		return ttlpair{bytes: preRefreshBytes, ttl: time.Hour}, nil
 	}
 
@@ -658,7 +1731,51 @@ func (c *Controller) getAuthor(ctx context.Context, authorID int64) (ttlpair, er
 		return ttlpair{bytes: cachedBytes, ttl: ttl}, nil
 	}
 
-	// Cache miss. Fetch new data.
+	// On a cold cache, optionally return a stub right away and load the full
+	// author in the background.
+	if len(cachedBytes) == 0 && c.options().authorStubs {
+		if p, err := c.getAuthorStub(ctx, authorID); err == nil || errors.Is(err, errNotFound) {
+			return p, err
+		}
+	}
+
+	return c.loadAuthor(ctx, authorID, cachedBytes)
+}
+
+// getAuthorStub caches and returns a minimal author, if the getter supports
+// it, and kicks off a full load in the background. An error is returned if a
+// stub isn't available, in which case the caller should load the author
+// normally.
+func (c *Controller) getAuthorStub(ctx context.Context, authorID int64) (ttlpair, error) {
+	stubber, ok := c.getter.(authorStubber)
+	if !ok {
+		return ttlpair{}, errors.ErrUnsupported
+	}
+
+	stubBytes, err := stubber.GetAuthorStub(ctx, authorID)
+	if errors.Is(err, errNotFound) {
+		c.cache.Set(ctx, AuthorKey(authorID), _missing, _missingTTL)
+		return ttlpair{}, err
+	}
+	if err != nil {
+		Log(ctx).Debug("problem getting author stub", "err", err, "authorID", authorID)
+		return ttlpair{}, err
+	}
+
+	stubBytes = c.guardAuthorBytes(ctx, authorID, stubBytes)
+	c.cache.Set(ctx, AuthorKey(authorID), stubBytes, _authorStubTTL)
+
+	ctx = context.WithValue(context.WithoutCancel(ctx), middleware.RequestIDKey, fmt.Sprintf("author-stub-%d", authorID))
+	c.spawn(ctx, "author-stub", func() {
+		_, _ = c.loadAuthor(ctx, authorID, nil)
+	})
+
+	return ttlpair{bytes: stubBytes, ttl: _authorStubTTL}, nil
+}
+
+// loadAuthor fetches the author from the getter and kicks off a refresh.
+// cachedBytes holds the author's last known state, if any.
+func (c *Controller) loadAuthor(ctx context.Context, authorID int64, cachedBytes []byte) (ttlpair, error) {
 	authorBytes, err := c.getter.GetAuthor(ctx, authorID)
 	if errors.Is(err, errNotFound) {
 		c.cache.Set(ctx, AuthorKey(authorID), _missing, _missingTTL)
@@ -669,7 +1786,9 @@ func (c *Controller) getAuthor(ctx context.Context, authorID int64) (ttlpair, er
 		return ttlpair{}, err
 	}
 
-	ttl = fuzz(_authorTTL, 1.5)
+	authorBytes = c.guardAuthorBytes(ctx, authorID, authorBytes)
+
+	ttl := fuzz(_authorTTL, 1.5)
 	c.cache.Set(ctx, AuthorKey(authorID), authorBytes, ttl)
 
 	// From here we'll prefer to use the last-known state. If this is the first
@@ -691,11 +1810,139 @@ func (c *Controller) getAuthor(ctx context.Context, authorID int64) (ttlpair, er
 	return ttlpair{bytes: cachedBytes, ttl: ttl}, nil
 }
 
+// overrideWorkAuthor replaces the work's primary author if it's been
+// corrected. Editions credited to the upstream author are credited to the
+// corrected author instead. It returns true if the work was modified.
+func (c *Controller) overrideWorkAuthor(ctx context.Context, work *workResource) bool {
+	authorID, ok := c.options().workAuthors[work.ForeignID]
+	if !ok || len(work.Authors) == 0 || work.Authors[0].ForeignID == authorID {
+		return false
+	}
+
+	upstreamID := work.Authors[0].ForeignID
+	Log(ctx).Info("overriding work author", "workID", work.ForeignID, "upstreamAuthorID", upstreamID, "authorID", authorID)
+
+	corrected := work.Authors[0]
+	corrected.ForeignID = authorID
+	if authorBytes, _, err := c.GetAuthor(ctx, authorID); err == nil {
+		var a AuthorResource
+		if err := json.Unmarshal(authorBytes, &a); err == nil {
+			corrected.Name = a.Name
+			corrected.Description = a.Description
+			corrected.ImageURL = a.ImageURL
+			corrected.URL = a.URL
+			corrected.KCA = a.KCA
+		}
+	}
+	work.Authors[0] = corrected
+
+	for i := range work.Books {
+		for j := range work.Books[i].Contributors {
+			if work.Books[i].Contributors[j].ForeignID == upstreamID {
+				work.Books[i].Contributors[j].ForeignID = authorID
+			}
+		}
+	}
+
+	return true
+}
+
+// overrideWorkAuthorBytes applies overrideWorkAuthor to a serialized work. It
+// returns the (possibly) modified work and its primary author.
+func (c *Controller) overrideWorkAuthorBytes(ctx context.Context, workBytes []byte, authorID int64) ([]byte, int64) {
+	var work workResource
+	if err := json.Unmarshal(workBytes, &work); err != nil {
+		return workBytes, authorID
+	}
+	if !c.overrideWorkAuthor(ctx, &work) {
+		return workBytes, authorID
+	}
+	out, err := json.Marshal(work)
+	if err != nil {
+		Log(ctx).Warn("problem overriding work author", "err", err, "workID", work.ForeignID)
+		return workBytes, authorID
+	}
+	return out, work.Authors[0].ForeignID
+}
+
+// guardNulls records null fields which were repaired before caching a
+// resource. The client crashes on these, so they're worth knowing about.
+func (c *Controller) guardNulls(ctx context.Context, kind string, id int64, repaired []string) {
+	if len(repaired) == 0 {
+		return
+	}
+	c.metrics.nullsRepairedInc()
+	Log(ctx).Warn("repaired null fields", "kind", kind, "id", id, "fields", repaired)
+}
+
+// guardWorkBytes repairs null fields on a serialized work before it's cached.
+// The original bytes are returned if nothing needed repairing.
+func (c *Controller) guardWorkBytes(ctx context.Context, workID int64, workBytes []byte) []byte {
+	var work workResource
+	if err := c.decodeJSON(ctx, workBytes, &work); err != nil {
+		Log(ctx).Warn("problem checking work", "err", err, "workID", workID)
+		return workBytes
+	}
+	repaired := work.repairNulls()
+	if len(repaired) == 0 {
+		return workBytes
+	}
+	buf := _buffers.Get()
+	defer buf.Free()
+	if err := c.encodeJSON(ctx, buf, work); err != nil {
+		Log(ctx).Warn("problem repairing work", "err", err, "workID", workID)
+		return workBytes
+	}
+	c.guardNulls(ctx, "work", workID, repaired)
+	return bytes.Clone(buf.Bytes())
+}
+
+// guardAuthorBytes repairs null fields on a serialized author before it's
+// cached. The original bytes are returned if nothing needed repairing.
+func (c *Controller) guardAuthorBytes(ctx context.Context, authorID int64, authorBytes []byte) []byte {
+	var author AuthorResource
+	if err := c.decodeJSON(ctx, authorBytes, &author); err != nil {
+		Log(ctx).Warn("problem checking author", "err", err, "authorID", authorID)
+		return authorBytes
+	}
+	repaired := author.repairNulls()
+	if len(repaired) == 0 {
+		return authorBytes
+	}
+	buf := _buffers.Get()
+	defer buf.Free()
+	if err := c.encodeJSON(ctx, buf, author); err != nil {
+		Log(ctx).Warn("problem repairing author", "err", err, "authorID", authorID)
+		return authorBytes
+	}
+	c.guardNulls(ctx, "author", authorID, repaired)
+	return bytes.Clone(buf.Bytes())
+}
+
 type refreshAuthor struct {
 	id    int64
 	state []byte
 }
 
+// startRefresh refreshes an author on the bounded worker pool, unless a
+// refresh for the same author is already in-flight. Duplicates happen when
+// concurrent cold requests, recovered refreshes and cache busts race each
+// other, and refreshing twice would only double our upstream load.
+func (c *Controller) startRefresh(ctx context.Context, r refreshAuthor) {
+	if _, inflight := c.refreshing.LoadOrStore(r.id, struct{}{}); inflight {
+		Log(ctx).Debug("author refresh already in-flight", "authorID", r.id)
+		c.metrics.refreshesDedupedInc()
+		return
+	}
+	c.waitForMemory(ctx)
+	c.metrics.refreshWaitingAdd(1)
+	c.refreshG.Go(func() error {
+		defer c.refreshing.Delete(r.id)
+		c.refreshAuthor(ctx, r.id, r.state)
+		return nil
+	})
+}
+
 func (c *Controller) refreshAuthor(ctx context.Context, authorID int64, cachedBytes []byte) {
 	ctx = context.WithValue(ctx, middleware.RequestIDKey, fmt.Sprintf("refresh-author-%d", authorID))
 
@@ -712,10 +1959,6 @@ func (c *Controller) refreshAuthor(ctx context.Context, authorID int64, cachedBy
 	workIDSToDenormalize := []int64{}
 
 	for bookID := range c.getter.GetAuthorBooks(ctx, authorID) {
-		if n > 1000 {
-			Log(ctx).Warn("found too many editions", "authorID", authorID)
-			break // Some authors (e.g. Wikipedia) have an obscene number of works. Give up.
-		}
 		bookBytes, _, err := c.GetBook(ctx, bookID)
 		if err != nil {
 			Log(ctx).Warn("problem getting book for author", "authorID", authorID, "bookID", bookID, "err", err)
@@ -734,87 +1977,309 @@ func (c *Controller) refreshAuthor(ctx context.Context, authorID int64, cachedBy
 			workIDSToDenormalize = append(workIDSToDenormalize, workID)
 		}
 		n++
+
+		// Some authors (e.g. Wikipedia) have an obscene number of works.
+		// Stop as soon as we hit the cap so the getter doesn't fetch
+		// another page we won't use.
+		if maxWorks := c.options().maxAuthorWorks; n >= maxWorks {
+			Log(ctx).Warn("found too many editions", "authorID", authorID, "max", maxWorks)
+			break
+		}
 	}
 
 	slices.Sort(workIDSToDenormalize)
 	workIDSToDenormalize = slices.Compact(workIDSToDenormalize)
 
-	if len(workIDSToDenormalize) > 0 {
-		c.denormC <- edge{kind: authorEdge, parentID: authorID, childIDs: newSet(workIDSToDenormalize...)}
+	if len(workIDSToDenormalize) > 0 {
+		c.denormC <- edge{kind: authorEdge, parentID: authorID, childIDs: newSet(workIDSToDenormalize...)}
+	}
+	c.denormC <- edge{kind: refreshDone, parentID: authorID}
+	Log(ctx).Info("fetched all works for author", "authorID", authorID, "count", len(workIDSToDenormalize), "duration", time.Since(start).String())
+}
+
+// Run is responsible for denormalizing data and handling our worker pools.
+// It returns once the context is cancelled and its background loops and any
+// in-flight author refreshes have stopped.
+func (c *Controller) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	defer func() { _ = c.refreshG.Wait() }()
+
+	// Log controller stats every minute until we're cancelled.
+	wg.Go(func() {
+		ctx := context.WithValue(ctx, middleware.RequestIDKey, "stats")
+		ticker := time.NewTicker(1 * time.Minute)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			Log(ctx).Debug("controller stats",
+				"refreshWaiting", c.metrics.refreshWaitingGet(),
+				"denormWaiting", c.metrics.denormWaitingGet(),
+				"denormRetrying", c.metrics.denormRetryWaitingGet(),
+				"etagMatches", c.metrics.etagMatchesGet(),
+				"etagRatio", c.metrics.etagRatioGet(),
+			)
+		}
+	})
+
+	// Retry any author refreshes that were in-flight when we last shut down.
+	wg.Go(func() {
+		ctx := context.WithValue(ctx, middleware.RequestIDKey, "recovery")
+		authorIDs, err := c.persister.Persisted(ctx)
+		if err != nil {
+			Log(ctx).Error("problem retrying in-flight refreshes", "err", err)
+		}
+		for _, authorID := range authorIDs {
+			Log(ctx).Debug("resuming author refresh", "authorID", authorID)
+			c.refreshC <- refreshAuthor{id: authorID}
+		}
+	})
+
+	// Send webhook notifications for updated authors and works.
+	wg.Go(func() { c.runNotifier(context.WithValue(ctx, middleware.RequestIDKey, "notify")) })
+
+	// Hand author refreshes to the bounded worker pool.
+	refreshes := accumulate(c.refreshC, &slicebuffer[refreshAuthor]{})
+	wg.Go(func() {
+		ctx := context.WithValue(ctx, middleware.RequestIDKey, "refresh")
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case r := <-refreshes:
+				c.startRefresh(ctx, r)
+			}
+		}
+	})
+
+	// Denormalize edges with the same parent serially, since each one
+	// re-serializes the parent, but different parents in parallel.
+	// Children are counted once they're buffered, and parked edges are
+	// merged too, so children already waiting aren't counted twice.
+	denormBuf := &edgebuf{pushed: func(_ edge, added int) { c.addDenormPending(added) }}
+	parked := func() bbuffer[edge] {
+		return &edgebuf{pushed: func(e edge, added int) { c.addDenormPending(added - len(e.childIDs)) }}
+	}
+	denorms := accumulate(c.denormC, denormBuf)
+	c.metrics.denormHeartbeatSet(time.Now())
+	wg.Go(func() {
+		ticker := time.NewTicker(_heartbeatInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				c.heartbeatIfIdle(int(c.denormPending.Load()))
+			}
+		}
+	})
+	c.supervise(ctx, "denormalize", func() {
+		partition(ctx, denorms, c.options().denormWorkers, func(e edge) int64 { return e.parentID }, parked, func(e edge) {
+			c.denormActive.Add(1)
+			defer c.denormActive.Add(-1)
+			defer c.addDenormPending(-len(e.childIDs))
+			c.denormalize(ctx, e)
+			c.metrics.denormHeartbeatSet(time.Now())
+		})
+	})
+}
+
+// addDenormPending adjusts how many children are waiting to be denormalized
+// and updates the gauge to match.
+func (c *Controller) addDenormPending(n int) {
+	c.denormPendingMu.Lock()
+	defer c.denormPendingMu.Unlock()
+	c.metrics.denormWaitingSet(int(c.denormPending.Add(int64(n))))
+}
+
+// _heartbeatInterval is how often an idle denormalization loop refreshes its
+// heartbeat.
+var _heartbeatInterval = 30 * time.Second
+
+// heartbeatIfIdle refreshes the denormalization heartbeat if there's nothing
+// queued or in progress. Otherwise the heartbeat only advances as edges are
+// processed, so it goes stale when denormalization is stuck but not when it's
+// idle.
+func (c *Controller) heartbeatIfIdle(queued int) {
+	if queued == 0 && c.denormActive.Load() == 0 {
+		c.metrics.denormHeartbeatSet(time.Now())
+	}
+}
+
+// _restartMaxBackoff bounds how long supervise waits before restarting a
+// consumer which panicked.
+const _restartMaxBackoff = time.Minute
+
+// supervise runs a long-lived consumer, restarting it with a capped
+// exponential backoff if it panics. Otherwise a single bad edge would stop
+// denormalization and we'd quietly serve increasingly stale data. It returns
+// once the consumer exits normally or ctx is cancelled.
+func (c *Controller) supervise(ctx context.Context, name string, run func()) {
+	backoff := c.options().restartBackoff
+	for {
+		start := time.Now()
+		if !panics(ctx, name, run) {
+			return
+		}
+		c.metrics.consumerRestartsInc()
+
+		// A consumer which was healthy for a while starts over.
+		if time.Since(start) > _restartMaxBackoff {
+			backoff = c.options().restartBackoff
+		}
+		Log(ctx).Error("restarting consumer", "consumer", name, "backoff", backoff)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(2*backoff, _restartMaxBackoff)
 	}
-	c.denormC <- edge{kind: refreshDone, parentID: authorID}
-	Log(ctx).Info("fetched all works for author", "authorID", authorID, "count", len(workIDSToDenormalize), "duration", time.Since(start).String())
 }
 
-// Run is responsible for denormalizing data and handling our worker pools.
-func (c *Controller) Run(ctx context.Context) {
-	// Log controller stats every minute.
-	go func() {
-		ctx := context.WithValue(ctx, middleware.RequestIDKey, "stats")
-		for {
-			time.Sleep(1 * time.Minute)
-			Log(ctx).Debug("controller stats",
-				"refreshWaiting", c.metrics.refreshWaitingGet(),
-				"denormWaiting", c.metrics.denormWaitingGet(),
-				"etagMatches", c.metrics.etagMatchesGet(),
-				"etagRatio", c.metrics.etagRatioGet(),
-			)
+// panics runs fn and returns true if it panicked.
+func panics(ctx context.Context, name string, fn func()) (panicked bool) {
+	defer func() {
+		if r := recover(); r != nil {
+			Log(ctx).Error("panic", "consumer", name, "details", r)
+			panicked = true
 		}
 	}()
+	fn()
+	return false
+}
 
-	// Retry any author refreshes that were in-flight when we last shut down.
-	go func() {
-		ctx := context.WithValue(ctx, middleware.RequestIDKey, "recovery")
-		authorIDs, err := c.persister.Persisted(ctx)
-		if err != nil {
-			Log(ctx).Error("problem retrying in-flight refreshes", "err", err)
+// denormalize handles a single edge.
+func (c *Controller) denormalize(ctx context.Context, edge edge) {
+	ctx, cancel := context.WithTimeout(ctx, 1*time.Minute)
+	defer cancel()
+	ctx = context.WithValue(ctx, middleware.RequestIDKey, fmt.Sprintf("denorm-%d-%d", edge.kind, edge.parentID))
+	ctx, span := startSpan(ctx, c.tracer, "denormalize",
+		attribute.Int("edge.kind", int(edge.kind)),
+		attribute.Int64("edge.parent", edge.parentID),
+		attribute.Int("edge.children", len(edge.childIDs)),
+	)
+	defer span.End()
+
+	switch edge.kind {
+	case authorEdge:
+		if unknownAuthor(edge.parentID) {
+			break
 		}
-		for _, authorID := range authorIDs {
-			Log(ctx).Debug("resuming author refresh", "authorID", authorID)
-			c.refreshC <- refreshAuthor{id: authorID}
+		if err := c.denormalizeWorks(ctx, edge.parentID, slices.Collect(maps.Keys(edge.childIDs))...); err != nil {
+			Log(ctx).Warn("problem ensuring work", "err", err, "authorID", edge.parentID, "workIDs", edge.childIDs)
+			c.retryDenorm(ctx, edge, err)
 		}
-	}()
-
-	// Hand author refreshes to the bounded worker pool.
-	refreshes := accumulate(c.refreshC, &slicebuffer[refreshAuthor]{})
-	go func() {
-		ctx := context.WithValue(ctx, middleware.RequestIDKey, "refresh")
-		for r := range refreshes {
-			c.metrics.refreshWaitingAdd(1)
-			c.refreshG.Go(func() error {
-				c.refreshAuthor(ctx, r.id, r.state)
-				return nil
-			})
+	case workEdge:
+		if rest, ok := edge.split(c.options().editionsPerPass); ok {
+			// Leave the remainder for another pass so this one finishes
+			// within its timeout.
+			Log(ctx).Debug("splitting editions across passes", "workID", edge.parentID, "now", len(edge.childIDs), "later", len(rest.childIDs))
+			c.enqueue(ctx, rest)
 		}
-	}()
+		if err := c.denormalizeEditions(ctx, edge.parentID, slices.Collect(maps.Keys(edge.childIDs))...); err != nil {
+			Log(ctx).Warn("problem ensuring edition", "err", err, "workID", edge.parentID, "bookIDs", edge.childIDs)
+			c.retryDenorm(ctx, edge, err)
+		}
+	case refreshDone:
+		c.metrics.refreshWaitingAdd(-1)
+		if err := c.persister.Delete(ctx, edge.parentID); err != nil {
+			Log(ctx).Warn("problem un-persisting refresh", "err", err)
+		}
+	}
+}
 
-	denormBuf := &edgebuf{}
-	denorms := accumulate(c.denormC, denormBuf)
-	for edge := range denorms {
-		ctx, cancel := context.WithTimeout(ctx, 1*time.Minute)
-		ctx = context.WithValue(ctx, middleware.RequestIDKey, fmt.Sprintf("denorm-%d-%d", edge.kind, edge.parentID))
+// waitForMemory blocks while heap usage exceeds the configured fraction of the
+// Go memory limit. The refresh worker pool only bounds how many authors are
+// loaded at once, and a handful of very large authors can still exhaust
+// memory on a constrained host.
+func (c *Controller) waitForMemory(ctx context.Context) {
+	threshold := c.options().refreshMemoryThreshold
+	if threshold <= 0 {
+		return
+	}
+	limit := _memoryLimit()
+	if limit <= 0 || limit == math.MaxInt64 {
+		return // No limit configured.
+	}
+	budget := uint64(float64(limit) * threshold)
+
+	paused := false
+	for heap := _heapBytes(); heap > budget; heap = _heapBytes() {
+		if !paused {
+			Log(ctx).Warn("pausing refreshes for memory", "heap", heap, "budget", budget)
+			c.metrics.refreshPausedSet(true)
+			paused = true
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(_memoryPollInterval):
+		}
+	}
+	if paused {
+		Log(ctx).Info("resuming refreshes")
+		c.metrics.refreshPausedSet(false)
+	}
+}
 
-		switch edge.kind {
-		case authorEdge:
-			if unknownAuthor(edge.parentID) {
-				break
-			}
-			if err := c.denormalizeWorks(ctx, edge.parentID, slices.Collect(maps.Keys(edge.childIDs))...); err != nil {
-				Log(ctx).Warn("problem ensuring work", "err", err, "authorID", edge.parentID, "workIDs", edge.childIDs)
-			}
-		case workEdge:
-			if err := c.denormalizeEditions(ctx, edge.parentID, slices.Collect(maps.Keys(edge.childIDs))...); err != nil {
-				Log(ctx).Warn("problem ensuring edition", "err", err, "workID", edge.parentID, "bookIDs", edge.childIDs)
-			}
-		case refreshDone:
-			c.metrics.refreshWaitingAdd(-1)
-			if err := c.persister.Delete(ctx, edge.parentID); err != nil {
-				Log(ctx).Warn("problem un-persisting refresh", "err", err)
-			}
+var (
+	// _memoryPollInterval is how often we check whether heap usage has
+	// dropped while refreshes are paused.
+	_memoryPollInterval = time.Second
+
+	// _memoryLimit returns the Go memory limit without changing it.
+	_memoryLimit = func() int64 { return debug.SetMemoryLimit(-1) }
+
+	// _heapBytes returns how many bytes are occupied by live and
+	// not-yet-swept heap objects.
+	_heapBytes = func() uint64 {
+		sample := []metrics.Sample{{Name: "/memory/classes/heap/objects:bytes"}}
+		metrics.Read(sample)
+		if sample[0].Value.Kind() != metrics.KindUint64 {
+			return 0
 		}
-		cancel()
-		c.metrics.denormWaitingSet(denormBuf.len())
+		return sample[0].Value.Uint64()
+	}
+)
+
+// retryDenorm re-enqueues a failed edge after backing off, so transient
+// upstream problems don't leave relationships permanently missing. Errors
+// that aren't transient are dropped, as are edges which have already been
+// retried too many times.
+func (c *Controller) retryDenorm(ctx context.Context, e edge, err error) {
+	if !transient(err) {
+		return
+	}
+	if e.attempts >= _denormRetries {
+		Log(ctx).Warn("giving up on denormalization", "kind", e.kind, "parentID", e.parentID, "attempts", e.attempts, "err", err)
+		c.metrics.denormExhaustedInc()
+		return
 	}
+	if c.denormRetries.Load() >= _denormRetryLimit {
+		Log(ctx).Warn("too many pending denormalization retries", "kind", e.kind, "parentID", e.parentID)
+		c.metrics.denormExhaustedInc()
+		return
+	}
+
+	delay := max(fuzz(_denormBackoff<<e.attempts, 1.5), retryAfter(err))
+	e.attempts++
+
+	Log(ctx).Debug("retrying denormalization", "kind", e.kind, "parentID", e.parentID, "attempt", e.attempts, "delay", delay)
+
+	c.denormRetries.Add(1)
+	c.metrics.denormRetryWaitingAdd(1)
+	time.AfterFunc(delay, func() {
+		c.denormRetries.Add(-1)
+		c.metrics.denormRetryWaitingAdd(-1)
+		c.denormC <- e
+	})
 }
 
 // Shutdown waits for all refresh and denormalization goroutines to finish
@@ -853,16 +2318,22 @@ func (c *Controller) denormalizeEditions(ctx context.Context, workID int64, book
 	}
 
 	old := newETagWriter()
-	r := io.TeeReader(bytes.NewReader(workBytes), old)
+	_, _ = old.Write(workBytes)
 
 	var work workResource
-	err = sonic.ConfigStd.NewDecoder(r).Decode(&work)
+	err = c.decodeJSON(ctx, workBytes, &work)
 	if err != nil {
 		Log(ctx).Debug("problem unmarshaling work", "err", err, "workID", workID)
 		_ = c.cache.Expire(ctx, WorkKey(workID))
 		return err
 	}
 
+	// Editions are saved in presentation order, so restore their ID order
+	// before looking them up.
+	slices.SortStableFunc(work.Books, func(a, b bookResource) int {
+		return cmp.Compare(a.ForeignID, b.ForeignID)
+	})
+
 	Log(ctx).Debug("ensuring work-edition edges", "workID", workID, "bookIDs", bookIDs)
 
 	for _, bookID := range bookIDs {
@@ -873,7 +2344,7 @@ func (c *Controller) denormalizeEditions(ctx context.Context, workID int64, book
 			continue
 		}
 		var w workResource
-		err = sonic.ConfigStd.Unmarshal(workBytes, &w)
+		err = c.decodeJSON(ctx, workBytes, &w)
 		if err != nil {
 			Log(ctx).Warn("problem unmarshaling book", "err", err, "bookID", bookID)
 			_ = c.cache.Expire(ctx, BookKey(bookID))
@@ -899,14 +2370,43 @@ func (c *Controller) denormalizeEditions(ctx context.Context, workID int64, book
 		}
 	}
 
+	if minYear := c.options().minEditionYear; minYear > 0 {
+		var dropped int
+		work.Books, dropped = dropOldEditions(work.Books, work.BestBookID, minYear)
+		for range dropped {
+			c.metrics.editionsExcludedInc()
+		}
+	}
+
+	if c.options().excludeOmnibus {
+		var dropped int
+		work.Books, dropped = dropOmnibusEditions(work.Books, work.BestBookID, work.FullTitle)
+		for range dropped {
+			c.metrics.editionsExcludedInc()
+		}
+	}
+
+	if maxEditions := c.options().maxEditions; maxEditions > 0 && len(work.Books) > maxEditions {
+		Log(ctx).Debug("trimming editions", "workID", workID, "count", len(work.Books), "max", maxEditions)
+		work.Books = trimEditions(work.Books, work.BestBookID, maxEditions)
+	}
+
+	// A work was originally published with its earliest edition, which isn't
+	// necessarily the best one.
+	work.ReleaseDate = earliestReleaseDate(work.ReleaseDate, work.Books)
+
+	orderEditions(&work)
+
+	c.guardNulls(ctx, "work", workID, work.repairNulls())
+
 	buf := _buffers.Get()
 	defer buf.Free()
-	neww := newETagWriter()
-	w := io.MultiWriter(buf, neww)
-	err = sonic.ConfigStd.NewEncoder(w).Encode(work)
+	err = c.encodeJSON(ctx, buf, work)
 	if err != nil {
 		return err
 	}
+	neww := newETagWriter()
+	_, _ = neww.Write(buf.Bytes())
 
 	if neww.ETag() == old.ETag() {
 		// The work didn't change, so we're done.
@@ -919,18 +2419,327 @@ func (c *Controller) denormalizeEditions(ctx context.Context, workID int64, book
 	out := bytes.Clone(buf.Bytes())
 
 	c.cache.Set(ctx, WorkKey(workID), out, fuzz(_workTTL, 1.5))
+	c.notify(ctx, "work", workID)
 
 	// We modified the work, so the author also needs to be updated. Remove the
 	// relationship so it doesn't no-op during the denormalization.
-	go func() {
-		for _, author := range work.Authors {
-			c.denormC <- edge{kind: authorEdge, parentID: author.ForeignID, childIDs: newSet(workID)}
-		}
-	}()
+	edges := make([]edge, 0, len(work.Authors))
+	for _, author := range work.Authors {
+		edges = append(edges, edge{kind: authorEdge, parentID: author.ForeignID, childIDs: newSet(workID)})
+	}
+	c.enqueue(ctx, edges...)
 
 	return nil
 }
 
+// dropOldEditions removes editions released before minYear, returning how
+// many were dropped. The best edition and editions without a release date are
+// kept. Release dates always start with a zero-padded year.
+func dropOldEditions(books []bookResource, bestBookID int64, minYear int) ([]bookResource, int) {
+	before := len(books)
+	books = slices.DeleteFunc(books, func(b bookResource) bool {
+		if b.ForeignID == bestBookID || len(b.ReleaseDate) < 4 {
+			return false
+		}
+		year, err := strconv.Atoi(b.ReleaseDate[:4])
+		return err == nil && year < minYear
+	})
+	return books, before - len(books)
+}
+
+// _omnibusTitle matches edition titles which usually mean several books
+// bound together.
+var _omnibusTitle = regexp.MustCompile(`(?i)\b(` + strings.Join([]string{
+	`box(ed)?[\s-]*set`,
+	`omnibus`,
+	`bundle`,
+	`complete\s+(series|collection|trilogy|saga|chronicles|novels|set)`,
+	`books?\s+\d+\s*(-|–|—|&|and|to|through)\s*\d+`,
+	`\d+[\s-]+in[\s-]+1`,
+	`\d+[\s-]+book\s+(set|collection)`,
+}, "|") + `)\b`)
+
+// _omnibusPages is how many times longer than the work's median an edition
+// needs to be before it's considered an omnibus. The median is only trusted
+// with more than _omnibusEditions page counts to go on.
+const (
+	_omnibusPages    = 2.5
+	_omnibusEditions = 3
+)
+
+// dropOmnibusEditions removes editions which look like box sets or omnibuses,
+// either by title or because they're far longer than the work's other
+// editions. It returns how many were dropped. The best edition is kept, and
+// titles aren't considered if the work itself looks like an omnibus.
+func dropOmnibusEditions(books []bookResource, bestBookID int64, workTitle string) ([]bookResource, int) {
+	checkTitles := !_omnibusTitle.MatchString(workTitle)
+
+	pages := []int64{}
+	for _, b := range books {
+		if b.NumPages > 0 {
+			pages = append(pages, b.NumPages)
+		}
+	}
+	slices.Sort(pages)
+	var median int64
+	if len(pages) > _omnibusEditions {
+		median = pages[len(pages)/2]
+	}
+
+	before := len(books)
+	books = slices.DeleteFunc(books, func(b bookResource) bool {
+		if b.ForeignID == bestBookID {
+			return false
+		}
+		if checkTitles && (_omnibusTitle.MatchString(b.FullTitle) || _omnibusTitle.MatchString(b.Title)) {
+			return true
+		}
+		return median > 0 && float64(b.NumPages) > _omnibusPages*float64(median)
+	})
+	return books, before - len(books)
+}
+
+// trimEditions keeps the n most relevant editions, sorted by ID. The best
+// edition is always kept, followed by editions in the primary language, then
+// editions in the same language as the best edition and then the most rated.
+func trimEditions(books []bookResource, bestBookID int64, n int) []bookResource {
+	var bestLanguage string
+	for _, b := range books {
+		if b.ForeignID == bestBookID {
+			bestLanguage = b.Language
+			break
+		}
+	}
+
+	ranked := slices.Clone(books)
+	slices.SortFunc(ranked, func(a, b bookResource) int {
+		return cmp.Or(
+			compareBool(a.ForeignID == bestBookID, b.ForeignID == bestBookID),
+			compareBool(primaryLanguage(a.Language), primaryLanguage(b.Language)),
+			compareBool(a.Language == bestLanguage, b.Language == bestLanguage),
+			cmp.Compare(b.RatingCount, a.RatingCount),
+			cmp.Compare(a.ForeignID, b.ForeignID),
+		)
+	})
+
+	kept := ranked[:min(n, len(ranked))]
+	slices.SortFunc(kept, func(a, b bookResource) int {
+		return cmp.Compare(a.ForeignID, b.ForeignID)
+	})
+	return kept
+}
+
+// Edition release dates outside of these bounds are placeholders or typos
+// (year 1, 9999, etc.) and shouldn't be used to date a work.
+const (
+	_earliestEditionYear  = 1450
+	_plausibleFutureYears = 10
+)
+
+// plausibleReleaseDate returns true if the date starts with a year an edition
+// could actually have been released in.
+func plausibleReleaseDate(date string) bool {
+	if len(date) < 4 {
+		return false
+	}
+	year, err := strconv.Atoi(date[:4])
+	if err != nil {
+		return false
+	}
+	return year >= _earliestEditionYear && year <= time.Now().Year()+cmp.Or(_maxFutureYears, _plausibleFutureYears)
+}
+
+// earliestReleaseDate returns the earliest of the given date and the editions'
+// release dates. Empty and implausible edition dates are ignored. Dates from
+// the same getter share a zero-padded format, so they can be compared
+// lexically.
+func earliestReleaseDate(date string, books []bookResource) string {
+	for _, b := range books {
+		if plausibleReleaseDate(b.ReleaseDate) && (date == "" || b.ReleaseDate < date) {
+			date = b.ReleaseDate
+		}
+	}
+	return date
+}
+
+// continuing returns true if any of the works was first released within the
+// given number of months before now, or is yet to be released. Release dates
+// always start with a zero-padded date, so they can be compared lexically.
+func continuing(works []workResource, months int, now time.Time) bool {
+	if months <= 0 {
+		return false
+	}
+	cutoff := now.AddDate(0, -months, 0).Format(time.DateOnly)
+	for _, w := range works {
+		if w.ReleaseDate != "" && w.ReleaseDate >= cutoff {
+			return true
+		}
+	}
+	return false
+}
+
+// topWorks keeps the n best works, ranked by a weighted combination of their
+// release date rank and their rating rank. Works keep their ID order.
+func topWorks(works []workResource, n int, recency float64) []workResource {
+	if n <= 0 || len(works) <= n {
+		return works
+	}
+
+	ranks := func(less func(a, b workResource) int) []int {
+		idx := make([]int, len(works))
+		for i := range idx {
+			idx[i] = i
+		}
+		slices.SortStableFunc(idx, func(a, b int) int { return less(works[a], works[b]) })
+		rank := make([]int, len(works))
+		for r, i := range idx {
+			rank[i] = r
+		}
+		return rank
+	}
+	// Release dates start with a zero-padded date, so they sort lexically.
+	byDate := ranks(func(a, b workResource) int { return cmp.Compare(b.ReleaseDate, a.ReleaseDate) })
+	byRating := ranks(func(a, b workResource) int { return cmp.Compare(popularity(b), popularity(a)) })
+
+	idx := make([]int, len(works))
+	for i := range idx {
+		idx[i] = i
+	}
+	score := func(i int) float64 {
+		return recency*float64(byDate[i]) + (1-recency)*float64(byRating[i])
+	}
+	slices.SortStableFunc(idx, func(a, b int) int { return cmp.Compare(score(a), score(b)) })
+
+	kept := idx[:n]
+	slices.Sort(kept)
+	out := make([]workResource, 0, n)
+	for _, i := range kept {
+		out = append(out, works[i])
+	}
+	return out
+}
+
+// popularity scores a work by its average rating, weighted by how many
+// ratings it has. GR stores ratings on editions rather than works.
+func popularity(w workResource) float64 {
+	count, sum := w.RatingCount, w.RatingSum
+	if count == 0 {
+		for _, b := range w.Books {
+			count += b.RatingCount
+			sum += b.RatingSum
+		}
+	}
+	if count == 0 {
+		return 0
+	}
+	return float64(sum) / float64(count) * math.Log1p(float64(count))
+}
+
+// disambiguateTitles includes subtitles (i.e. FullTitle) on works which share
+// the same short title, as counted by titles. Works in a series always get
+// their subtitle if seriesSubtitles is set, otherwise only when their title is
+// ambiguous.
+func disambiguateTitles(works []workResource, titles map[string]int, seriesSubtitles bool) {
+	for idx := range works {
+		shortTitle := works[idx].Title
+		if works[idx].ShortTitle != "" {
+			shortTitle = works[idx].ShortTitle
+		}
+		inSeries := seriesSubtitles && len(works[idx].Series) > 0
+		if !inSeries && titles[strings.ToUpper(shortTitle)] <= 1 {
+			// If the short title is already unique there's nothing to do.
+			continue
+		}
+		if works[idx].FullTitle == "" {
+			continue
+		}
+		works[idx].Title = works[idx].FullTitle
+		for bidx := range works[idx].Books {
+			if works[idx].Books[bidx].FullTitle == "" {
+				continue
+			}
+			works[idx].Books[bidx].Title = works[idx].Books[bidx].FullTitle
+		}
+	}
+}
+
+// topGenres returns the n genres shared by the most works, most common
+// first. The placeholder used for works without genres is never included.
+func topGenres(counts map[string]int, n int) []string {
+	genres := make([]string, 0, len(counts))
+	for g := range counts {
+		if g == "" || g == _genrePlaceholder {
+			continue
+		}
+		genres = append(genres, g)
+	}
+	slices.SortFunc(genres, func(a, b string) int {
+		return cmp.Or(cmp.Compare(counts[b], counts[a]), cmp.Compare(a, b))
+	})
+	return genres[:min(n, len(genres))]
+}
+
+// pickSeries returns at most n series IDs, preferring series containing more
+// of the author's works. Non-positive n means no limit.
+func pickSeries(seriesWorks map[int64]int, n int) []int64 {
+	seriesIDs := slices.Collect(maps.Keys(seriesWorks))
+	slices.SortFunc(seriesIDs, func(a, b int64) int {
+		return cmp.Or(cmp.Compare(seriesWorks[b], seriesWorks[a]), cmp.Compare(a, b))
+	})
+	if n > 0 && len(seriesIDs) > n {
+		seriesIDs = seriesIDs[:n]
+	}
+	return seriesIDs
+}
+
+// trimSeriesLinks limits the works listed by a series embedded in an author.
+// Other authors' works are dropped if ownOnly is set, and at most n works are
+// kept, preferring the author's own. Series order is preserved.
+func trimSeriesLinks(links []seriesWorkLinkResource, authorWorks map[int64]struct{}, ownOnly bool, n int) []seriesWorkLinkResource {
+	own := func(l seriesWorkLinkResource) bool {
+		_, ok := authorWorks[l.ForeignWorkID]
+		return ok
+	}
+	if ownOnly {
+		links = slices.DeleteFunc(slices.Clone(links), func(l seriesWorkLinkResource) bool { return !own(l) })
+	}
+	if n <= 0 || len(links) <= n {
+		return links
+	}
+
+	keep := map[int]struct{}{}
+	for _, wantOwn := range []bool{true, false} {
+		for idx, l := range links {
+			if len(keep) == n {
+				break
+			}
+			if own(l) == wantOwn {
+				keep[idx] = struct{}{}
+			}
+		}
+	}
+
+	trimmed := make([]seriesWorkLinkResource, 0, n)
+	for idx, l := range links {
+		if _, ok := keep[idx]; ok {
+			trimmed = append(trimmed, l)
+		}
+	}
+	return trimmed
+}
+
+// compareBool orders true before false.
+func compareBool(a, b bool) int {
+	switch {
+	case a == b:
+		return 0
+	case a:
+		return -1
+	default:
+		return 1
+	}
+}
+
 // denormalizeWorks ensures that the given works exist on the author. This is a
 // no-op if our cached work already includes the work's ID. This is meant to be
 // invoked in the background, and it's what allows us to support large authors.
@@ -949,14 +2758,21 @@ func (c *Controller) denormalizeWorks(ctx context.Context, authorID int64, workI
 	}
 
 	old := newETagWriter()
-	r := io.TeeReader(bytes.NewReader(authorBytes), old)
+	_, _ = old.Write(authorBytes)
 
+	// Decoding is the most expensive part of denormalizing a large author, so
+	// re-use what we wrote last time if nothing else has touched it since.
 	var author AuthorResource
-	err = sonic.ConfigStd.NewDecoder(r).Decode(&author)
-	if err != nil {
-		Log(ctx).Debug("problem unmarshaling author", "err", err, "authorID", authorID)
-		_ = c.cache.Expire(ctx, AuthorKey(authorID))
-		return err
+	if d, ok := c.decoded.take(authorID); ok && d.etag == old.ETag() {
+		author = d.author
+		c.metrics.authorDecodesSkippedInc()
+	} else {
+		err = c.decodeJSON(ctx, authorBytes, &author)
+		if err != nil {
+			Log(ctx).Debug("problem unmarshaling author", "err", err, "authorID", authorID)
+			_ = c.cache.Expire(ctx, AuthorKey(authorID))
+			return err
+		}
 	}
 
 	Log(ctx).Debug("ensuring author-work edges", "authorID", authorID, "workIDs", workIDs)
@@ -969,7 +2785,7 @@ func (c *Controller) denormalizeWorks(ctx context.Context, authorID int64, workI
 			continue
 		}
 		var work workResource
-		err = sonic.ConfigStd.Unmarshal(workBytes, &work)
+		err = c.decodeJSON(ctx, workBytes, &work)
 		if err != nil {
 			Log(ctx).Warn("problem unmarshaling work", "err", err, "workID", workID)
 			_ = c.cache.Expire(ctx, WorkKey(workID))
@@ -981,9 +2797,24 @@ func (c *Controller) denormalizeWorks(ctx context.Context, authorID int64, workI
 			return cmp.Compare(w.ForeignID, id)
 		})
 
+		if overrideID, ok := c.options().workAuthors[workID]; ok {
+			if overrideID != authorID {
+				// The work was misattributed to this author.
+				if found {
+					author.Works = slices.Delete(author.Works, idx, idx+1)
+				}
+				continue
+			}
+			c.overrideWorkAuthor(ctx, &work)
+		}
+
 		if len(work.Books) == 0 {
-			Log(ctx).Warn("work had no editions", "workID", workID)
-			continue
+			if !c.options().keepEmptyWorks {
+				Log(ctx).Warn("work had no editions", "workID", workID)
+				continue
+			}
+			// Clients tolerate empty editions, but not null.
+			work.Books = []bookResource{}
 		}
 
 		if found {
@@ -1001,6 +2832,13 @@ func (c *Controller) denormalizeWorks(ctx context.Context, authorID int64, workI
 	// Keep track of any duplicated titles so we can disambiguate them with subtitles.
 	titles := map[string]int{}
 
+	// Count how many of the author's works are in each series.
+	seriesWorks := map[int64]int{}
+	authorWorks := map[int64]struct{}{}
+
+	// Count how many of the author's works have each genre.
+	genres := map[string]int{}
+
 	ratingSum := int64(0)
 	ratingCount := int64(0)
 	for _, w := range author.Works {
@@ -1019,71 +2857,85 @@ func (c *Controller) denormalizeWorks(ctx context.Context, authorID int64, workI
 			}
 		}
 		for _, s := range w.Series {
-			// Fetch the complete series since we might not derive it correctly from works alone.
-			wg.Go(func() {
-				s, err := c.GetSeries(ctx, s.ForeignID)
-				if err != nil {
-					return
-				}
+			seriesWorks[s.ForeignID]++
+		}
+		for _, g := range w.Genres {
+			genres[g]++
+		}
+		authorWorks[w.ForeignID] = struct{}{}
+		for _, a := range w.Authors {
+			if a.ForeignID == authorID {
+				author.Aliases = mergeAliases(author.Aliases, a.Aliases...)
+			}
+		}
+	}
 
-				var ss SeriesResource
-				err = json.Unmarshal(s, &ss)
-				if err != nil {
-					return
-				}
+	author.Continuing = continuing(author.Works, c.options().continuingMonths, time.Now())
+	author.Genres = topGenres(genres, _authorGenres)
 
-				mu.Lock()
-				defer mu.Unlock()
+	if n := c.options().embeddedWorks; n > 0 && len(author.Works) > n {
+		Log(ctx).Debug("trimming embedded works", "authorID", authorID, "count", len(author.Works), "max", n)
+		author.Works = topWorks(author.Works, n, c.options().embeddedRecency)
+	}
 
-				idx, found := slices.BinarySearchFunc(author.Series, ss.ForeignID, func(s SeriesResource, id int64) int {
-					return cmp.Compare(s.ForeignID, id)
-				})
+	// Fetch the complete series since we might not derive them correctly from
+	// works alone.
+	maxSeries := c.options().maxAuthorSeries
+	seriesIDs := pickSeries(seriesWorks, maxSeries)
+	if len(seriesIDs) < len(seriesWorks) {
+		Log(ctx).Debug("skipping series", "authorID", authorID, "series", len(seriesWorks), "max", maxSeries)
+	}
+	c.metrics.seriesFetchedObserve(len(seriesIDs))
 
-				if !found {
-					author.Series = slices.Insert(author.Series, idx, ss)
-				}
+	for _, seriesID := range seriesIDs {
+		wg.Go(func() {
+			s, err := c.GetSeries(ctx, seriesID)
+			if err != nil {
+				return
+			}
+
+			var ss SeriesResource
+			err = json.Unmarshal(s, &ss)
+			if err != nil {
+				return
+			}
+			ss.LinkItems = trimSeriesLinks(ss.LinkItems, authorWorks, c.options().authorSeriesOnly, c.options().maxSeriesLinks)
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			idx, found := slices.BinarySearchFunc(author.Series, ss.ForeignID, func(s SeriesResource, id int64) int {
+				return cmp.Compare(s.ForeignID, id)
 			})
-		}
-	}
 
-	// Disambiguate works which share the same title by including subtitles.
-	for idx := range author.Works {
-		shortTitle := author.Works[idx].Title
-		if author.Works[idx].ShortTitle != "" {
-			shortTitle = author.Works[idx].ShortTitle
-		}
-		// If this is part of a series, always include the subtitle.
-		inSeries := len(author.Works[idx].Series) > 0
-		if !inSeries && titles[strings.ToUpper(shortTitle)] <= 1 {
-			// If the short title is already unique there's nothing to do.
-			continue
-		}
-		if author.Works[idx].FullTitle == "" {
-			continue
-		}
-		author.Works[idx].Title = author.Works[idx].FullTitle
-		for bidx := range author.Works[idx].Books {
-			if author.Works[idx].Books[bidx].FullTitle == "" {
-				continue
+			if !found {
+				author.Series = slices.Insert(author.Series, idx, ss)
 			}
-			author.Works[idx].Books[bidx].Title = author.Works[idx].Books[bidx].FullTitle
-		}
+		})
 	}
+
+	disambiguateTitles(author.Works, titles, c.options().seriesSubtitles)
 	if ratingCount != 0 {
 		author.RatingCount = ratingCount
 		author.AverageRating = float32(ratingSum) / float32(ratingCount)
+	} else if author.RatingCount == 0 {
+		author.AverageRating = float32(_unratedRating)
 	}
 
 	wg.Wait()
 
+	c.guardNulls(ctx, "author", authorID, author.repairNulls())
+
 	buf := _buffers.Get()
 	defer buf.Free()
-	neww := newETagWriter()
-	w := io.MultiWriter(buf, neww)
-	err = sonic.ConfigStd.NewEncoder(w).Encode(author)
+	err = c.encodeJSON(ctx, buf, author)
 	if err != nil {
 		return err
 	}
+	neww := newETagWriter()
+	_, _ = neww.Write(buf.Bytes())
+
+	c.decoded.set(authorID, decodedAuthor{etag: neww.ETag(), author: author})
 
 	if neww.ETag() == old.ETag() {
 		// The author didn't change, so we're done.
@@ -1096,10 +2948,35 @@ func (c *Controller) denormalizeWorks(ctx context.Context, authorID int64, workI
 	out := bytes.Clone(buf.Bytes())
 
 	c.cache.Set(ctx, AuthorKey(authorID), out, fuzz(_authorTTL, 1.5))
+	c.notify(ctx, "author", authorID)
+
+	for _, alias := range author.Aliases {
+		c.setAlias(ctx, alias.Name, authorID)
+	}
 
 	return nil
 }
 
+// _decodedAuthors bounds how many denormalized authors are kept decoded in
+// memory. Edges are partitioned by author, so this only needs to cover the
+// authors currently being worked on.
+var _decodedAuthors = 16
+
+// _authorGenres is how many of their works' most common genres are listed on
+// an author.
+var _authorGenres = 5
+
+// _backgroundWorkers is the default cap on goroutines spawned for background
+// work.
+var _backgroundWorkers = 1000
+
+// decodedAuthor is an author along with the ETag of its serialized form. It's
+// only valid while the cached bytes still have the same ETag.
+type decodedAuthor struct {
+	etag   string
+	author AuthorResource
+}
+
 // editionsCallback can be used by a Getter to trigger async loading of
 // additional editions.
 type editionsCallback func(...workResource)
@@ -1124,3 +3001,38 @@ func init() {
 	option.DefaultDecoderBufferSize = 1024 * 1024 // 1MB
 	option.DefaultEncoderBufferSize = 1024 * 1024 // 1MB
 }
+
+// decodeJSON unmarshals data with sonic, falling back to encoding/json if
+// sonic fails. sonic is much faster but has internal limits which
+// pathologically large authors can exceed, whereas encoding/json is slow but
+// unbounded.
+func (c *Controller) decodeJSON(ctx context.Context, data []byte, v any) error {
+	err := c.codec.Unmarshal(data, v)
+	if err == nil {
+		return nil
+	}
+
+	// Discard anything sonic partially decoded.
+	reflect.ValueOf(v).Elem().SetZero()
+	if err := json.Unmarshal(data, v); err != nil {
+		return err
+	}
+	Log(ctx).Warn("sonic decoding failed, fell back to encoding/json", "err", err, "size", len(data))
+	return nil
+}
+
+// encodeJSON marshals v into buf with sonic, falling back to encoding/json if
+// sonic fails. Both produce the same output.
+func (c *Controller) encodeJSON(ctx context.Context, buf *buffer.Buffer, v any) error {
+	err := c.codec.NewEncoder(buf).Encode(v)
+	if err == nil {
+		return nil
+	}
+
+	buf.Reset()
+	if err := json.NewEncoder(buf).Encode(v); err != nil {
+		return err
+	}
+	Log(ctx).Warn("sonic encoding failed, fell back to encoding/json", "err", err, "size", buf.Len())
+	return nil
+}
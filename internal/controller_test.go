@@ -5,11 +5,21 @@ package internal
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"iter"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"os"
+	"slices"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/bytedance/sonic"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
@@ -49,7 +59,7 @@ func TestIncrementalDenormalization(t *testing.T) {
 	ctrl, err := NewController(cache, getter, nil, nil)
 	require.NoError(t, err)
 
-	go ctrl.Run(t.Context())
+	runController(t, ctrl)
 	t.Cleanup(func() { ctrl.Shutdown(t.Context()) })
 
 	// TODO: Generalize this into a test helper.
@@ -291,7 +301,7 @@ func TestSubtitles(t *testing.T) {
 	cache := newMemoryCache()
 
 	ctrl, err := NewController(cache, getter, nil, nil)
-	go ctrl.Run(t.Context())
+	runController(t, ctrl)
 	require.NoError(t, err)
 
 	getter.EXPECT().GetAuthor(gomock.Any(), author.ForeignID).DoAndReturn(func(ctx context.Context, authorID int64) ([]byte, error) {
@@ -355,7 +365,8 @@ func TestSubtitles(t *testing.T) {
 		LinkItems: []seriesWorkLinkResource{},
 	}, nil)
 
-	getter.EXPECT().GetAuthorBooks(gomock.Any(), author.ForeignID).Return(iter.Seq[int64](func(func(int64) bool) {}))
+	// Loading the author kicks off a refresh in the background.
+	expectRefresh(t, getter, author.ForeignID, func(func(int64) bool) {})
 
 	err = ctrl.denormalizeWorks(ctx, author.ForeignID, workDupe1.ForeignID, workDupe2.ForeignID, workUnique.ForeignID)
 	require.NoError(t, err)
@@ -397,6 +408,60 @@ func TestSubtitles(t *testing.T) {
 	assert.Equal(t, "Baz: The Baz Series #3", author.Works[5].Books[0].Title)
 }
 
+func TestSubtitlesInSeries(t *testing.T) {
+	// Series works can keep their short title unless it's ambiguous.
+
+	works := func() []workResource {
+		return []workResource{
+			{
+				ForeignID: 1, Title: "Baz", ShortTitle: "Baz", FullTitle: "Baz: The Baz Series #3",
+				Books:  []bookResource{{ForeignID: 10, Title: "Baz", FullTitle: "Baz: The Baz Series #3"}},
+				Series: []SeriesResource{{ForeignID: 1234}},
+			},
+			{
+				ForeignID: 2, Title: "Foo", ShortTitle: "Foo", FullTitle: "Foo: The Foo Series #1",
+				Books:  []bookResource{{ForeignID: 20, Title: "Foo", FullTitle: "Foo: The Foo Series #1"}},
+				Series: []SeriesResource{{ForeignID: 5678}},
+			},
+			{
+				ForeignID: 3, Title: "Foo", FullTitle: "Foo: A Novella",
+				Books: []bookResource{{ForeignID: 30, Title: "Foo", FullTitle: "Foo: A Novella"}},
+			},
+		}
+	}
+	titles := map[string]int{"BAZ": 1, "FOO": 2}
+
+	tests := []struct {
+		name            string
+		seriesSubtitles bool
+		want            []string
+	}{
+		{
+			name:            "always",
+			seriesSubtitles: true,
+			want:            []string{"Baz: The Baz Series #3", "Foo: The Foo Series #1", "Foo: A Novella"},
+		},
+		{
+			name:            "only when ambiguous",
+			seriesSubtitles: false,
+			want:            []string{"Baz", "Foo: The Foo Series #1", "Foo: A Novella"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			given := works()
+			disambiguateTitles(given, titles, tt.seriesSubtitles)
+
+			got := []string{}
+			for _, w := range given {
+				got = append(got, w.Title)
+				assert.Equal(t, w.Title, w.Books[0].Title)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
 func TestMergedEditions(t *testing.T) {
 	// GetBook(X) and GetBook(Y) can both return an edition with ID X if the
 	// editions were merged. That shouldn't manifest as a work containing two
@@ -441,6 +506,51 @@ func TestMergedEditions(t *testing.T) {
 	assert.Len(t, work.Books, 1)
 }
 
+func TestWorkReleaseDate(t *testing.T) {
+	// A work's release date should be its earliest edition's, even if the
+	// best edition is a newer reprint.
+	ctx := t.Context()
+	getter := NewMockgetter(gomock.NewController(t))
+	ctrl, err := NewController(newMemoryCache(), getter, nil, nil)
+	require.NoError(t, err)
+
+	workID := int64(10)
+	authorID := int64(100)
+	reprintID := int64(1)
+	firstID := int64(2)
+
+	edition := func(bookID int64, date string) []byte {
+		out, err := json.Marshal(workResource{
+			ForeignID:   workID,
+			BestBookID:  reprintID,
+			ReleaseDate: date,
+			Books:       []bookResource{{ForeignID: bookID, ReleaseDate: date}},
+		})
+		require.NoError(t, err)
+		return out
+	}
+
+	getter.EXPECT().GetWork(gomock.Any(), workID, nil).Return(edition(reprintID, "2019-04-02 00:00:00"), authorID, nil)
+	getter.EXPECT().GetBook(gomock.Any(), reprintID, nil).Return(edition(reprintID, "2019-04-02 00:00:00"), workID, authorID, nil)
+	getter.EXPECT().GetBook(gomock.Any(), firstID, nil).Return(edition(firstID, "1954-07-29 00:00:00"), workID, authorID, nil)
+
+	require.NoError(t, ctrl.denormalizeEditions(ctx, workID, reprintID, firstID))
+
+	workBytes, _, err := ctrl.GetWork(ctx, workID)
+	require.NoError(t, err)
+
+	var work workResource
+	require.NoError(t, json.Unmarshal(workBytes, &work))
+	assert.Equal(t, reprintID, work.BestBookID)
+	assert.Equal(t, "1954-07-29 00:00:00", work.ReleaseDate)
+
+	assert.Equal(t, "", earliestReleaseDate("", nil))
+	assert.Equal(t, "2001-01-01", earliestReleaseDate("", []bookResource{{ReleaseDate: "2001-01-01"}, {}}))
+	assert.Equal(t, "2001-01-01", earliestReleaseDate("", []bookResource{{ReleaseDate: "0001-01-01"}, {ReleaseDate: "2001-01-01"}}))
+	assert.Equal(t, "2001-01-01", earliestReleaseDate("2001-01-01", []bookResource{{ReleaseDate: "1000"}, {ReleaseDate: "unknown"}}))
+	assert.Equal(t, "", earliestReleaseDate("", []bookResource{{ReleaseDate: "9999-12-31"}}))
+}
+
 func TestMergedWorks(t *testing.T) {
 	// Same principle as TestMergedEditions.
 
@@ -449,7 +559,7 @@ func TestMergedWorks(t *testing.T) {
 	cache := newMemoryCache()
 	ctrl, err := NewController(cache, getter, nil, nil)
 	require.NoError(t, err)
-	go ctrl.Run(t.Context())
+	runController(t, ctrl)
 
 	workID := int64(1)
 	mergedID := int64(2)
@@ -471,7 +581,7 @@ func TestMergedWorks(t *testing.T) {
 	getter.EXPECT().GetWork(gomock.Any(), mergedID, nil).Return(workBytes, authorID, nil)
 
 	getter.EXPECT().GetAuthor(gomock.Any(), authorID).Return(authorBytes, nil)
-	getter.EXPECT().GetAuthorBooks(gomock.Any(), authorID).Return(nil)
+	expectRefresh(t, getter, authorID, func(func(int64) bool) {})
 
 	err = ctrl.denormalizeWorks(ctx, authorID, workID, mergedID)
 	require.NoError(t, err)
@@ -486,6 +596,207 @@ func TestMergedWorks(t *testing.T) {
 	assert.Len(t, author.Works, 1)
 }
 
+func TestRelaxedEditionAuthors(t *testing.T) {
+	// Co-authored editions are excluded unless we relax author matching.
+
+	workID := int64(1)
+	authorID := int64(100)
+	coAuthorID := int64(200)
+
+	edition := func(editionID, primaryAuthorID int64) workResource {
+		return workResource{
+			ForeignID: workID,
+			Authors:   []AuthorResource{{ForeignID: primaryAuthorID}},
+			Books: []bookResource{{
+				ForeignID:      editionID,
+				Contributors:   []contributorResource{{ForeignID: authorID}},
+				contributorIDs: []int64{primaryAuthorID, authorID},
+			}},
+		}
+	}
+
+	for _, relax := range []bool{false, true} {
+		t.Run(fmt.Sprint(relax), func(t *testing.T) {
+			cache := newMemoryCache()
+			for _, id := range []int64{authorID, coAuthorID} {
+				authorBytes, err := json.Marshal(AuthorResource{ForeignID: id})
+				require.NoError(t, err)
+				cache.Set(t.Context(), AuthorKey(id), authorBytes, time.Hour)
+			}
+
+			ctrl, err := NewController(cache, NewMockgetter(gomock.NewController(t)), nil, nil, WithRelaxedEditionAuthors(relax))
+			require.NoError(t, err)
+
+			ctrl.saveEditions(edition(10, authorID), edition(20, coAuthorID))
+
+			e := <-ctrl.denormC
+			assert.Equal(t, workEdge, e.kind)
+			if relax {
+				assert.Equal(t, newSet[int64](10, 20), e.childIDs)
+				assert.Equal(t, 0.0, ctrl.metrics.editionsExcludedGet())
+			} else {
+				assert.Equal(t, newSet[int64](10), e.childIDs)
+				assert.Equal(t, 1.0, ctrl.metrics.editionsExcludedGet())
+			}
+		})
+	}
+}
+
+func TestAuthorAliases(t *testing.T) {
+	// Requests for a merged-away author should serve the surviving author.
+
+	ctx := t.Context()
+	oldID := int64(1)
+	newID := int64(2)
+
+	cache := newMemoryCache()
+	authorBytes, err := json.Marshal(AuthorResource{ForeignID: newID})
+	require.NoError(t, err)
+	cache.Set(ctx, AuthorKey(newID), authorBytes, time.Hour)
+
+	ctrl, err := NewController(cache, NewMockgetter(gomock.NewController(t)), nil, nil, WithAuthorAliases(map[int64]int64{oldID: newID}))
+	require.NoError(t, err)
+
+	out, _, err := ctrl.GetAuthor(ctx, oldID)
+	require.NoError(t, err)
+
+	var author AuthorResource
+	require.NoError(t, json.Unmarshal(out, &author))
+	assert.Equal(t, newID, author.ForeignID)
+}
+
+func TestEditionsPerPass(t *testing.T) {
+	workBytes, err := json.Marshal(workResource{ForeignID: 10, Books: []bookResource{}})
+	require.NoError(t, err)
+
+	getter := NewMockgetter(gomock.NewController(t))
+	getter.EXPECT().GetWork(gomock.Any(), int64(10), nil).Return(workBytes, 0, nil)
+	// Only the first pass's editions are fetched.
+	getter.EXPECT().GetBook(gomock.Any(), int64(1), nil).Return(nil, 0, 0, errNotFound)
+	getter.EXPECT().GetBook(gomock.Any(), int64(2), nil).Return(nil, 0, 0, errNotFound)
+
+	ctrl, err := NewController(newMemoryCache(), getter, nil, nil, WithEditionsPerPass(2))
+	require.NoError(t, err)
+
+	ctrl.denormalize(t.Context(), edge{kind: workEdge, parentID: 10, childIDs: newSet[int64](3, 1, 2)})
+
+	// The rest is left for another pass.
+	rest := <-ctrl.denormC
+	assert.Equal(t, edge{kind: workEdge, parentID: 10, childIDs: newSet[int64](3)}, rest)
+
+	// Small edges aren't split.
+	e := edge{kind: workEdge, parentID: 10, childIDs: newSet[int64](1, 2)}
+	_, ok := e.split(2)
+	assert.False(t, ok)
+	_, ok = e.split(0)
+	assert.False(t, ok)
+}
+
+func TestDropOldEditions(t *testing.T) {
+	books := []bookResource{
+		{ForeignID: 1, ReleaseDate: "0001-01-01"}, // Best edition.
+		{ForeignID: 2, ReleaseDate: "0001-01-01 00:00:00"},
+		{ForeignID: 3, ReleaseDate: "1449-12-31"},
+		{ForeignID: 4, ReleaseDate: "1450-01-01"},
+		{ForeignID: 5, ReleaseDate: ""},
+		{ForeignID: 6, ReleaseDate: "2001-05-01 00:00:00"},
+	}
+
+	ids := func(books []bookResource) []int64 {
+		out := []int64{}
+		for _, b := range books {
+			out = append(out, b.ForeignID)
+		}
+		return out
+	}
+
+	kept, dropped := dropOldEditions(slices.Clone(books), 1, 1450)
+	assert.Equal(t, 2, dropped)
+	assert.Equal(t, []int64{1, 4, 5, 6}, ids(kept))
+
+	kept, dropped = dropOldEditions(slices.Clone(books), 6, 1451)
+	assert.Equal(t, 4, dropped)
+	assert.Equal(t, []int64{5, 6}, ids(kept))
+}
+
+func TestDropOmnibusEditions(t *testing.T) {
+	for _, title := range []string{
+		"The Chronicles of Narnia Box Set",
+		"Harry Potter Boxed Set",
+		"The Lord of the Rings Omnibus",
+		"Mistborn: The Complete Trilogy",
+		"The Hunger Games Trilogy (Books 1-3)",
+		"Discworld Book 1 & 2",
+		"Three Body Problem 3-in-1",
+		"Wheel of Time 4-Book Set",
+	} {
+		_, dropped := dropOmnibusEditions([]bookResource{{ForeignID: 1}, {ForeignID: 2, Title: title}}, 1, "A Game of Thrones")
+		assert.Equal(t, 1, dropped, title)
+	}
+	for _, title := range []string{
+		"The Fellowship of the Ring",
+		"The Complete Guide to Gardening", // Not a series.
+		"Catch-22",
+		"Setting Sun",
+	} {
+		_, dropped := dropOmnibusEditions([]bookResource{{ForeignID: 1}, {ForeignID: 2, Title: title}}, 1, "A Game of Thrones")
+		assert.Equal(t, 0, dropped, title)
+	}
+
+	books := []bookResource{
+		{ForeignID: 1, NumPages: 1500, Title: "Books 1-3"}, // Best edition.
+		{ForeignID: 2, NumPages: 320},
+		{ForeignID: 3, NumPages: 350},
+		{ForeignID: 4, NumPages: 1400},
+		{ForeignID: 5, NumPages: 300},
+		{ForeignID: 6},
+	}
+	kept, dropped := dropOmnibusEditions(slices.Clone(books), 1, "Title")
+	assert.Equal(t, 1, dropped)
+	assert.Len(t, kept, 5)
+	assert.Equal(t, int64(1), kept[0].ForeignID)
+
+	// Too few page counts to tell what's normal.
+	_, dropped = dropOmnibusEditions(slices.Clone(books[2:]), 1, "Title")
+	assert.Equal(t, 0, dropped)
+
+	// Titles don't count against works which are omnibuses themselves.
+	_, dropped = dropOmnibusEditions([]bookResource{{ForeignID: 1}, {ForeignID: 2, Title: "The Complete Trilogy"}}, 1, "The Complete Trilogy")
+	assert.Equal(t, 0, dropped)
+}
+
+func TestTrimEditions(t *testing.T) {
+	books := []bookResource{
+		{ForeignID: 1, Language: "ger", RatingCount: 500},
+		{ForeignID: 2, Language: "eng", RatingCount: 1}, // Best.
+		{ForeignID: 3, Language: "eng", RatingCount: 10},
+		{ForeignID: 4, Language: "eng", RatingCount: 20},
+		{ForeignID: 5, Language: "fre", RatingCount: 100},
+	}
+
+	ids := func(books []bookResource) []int64 {
+		out := []int64{}
+		for _, b := range books {
+			out = append(out, b.ForeignID)
+		}
+		return out
+	}
+
+	assert.Equal(t, []int64{2}, ids(trimEditions(books, 2, 1)))
+	assert.Equal(t, []int64{2, 3, 4}, ids(trimEditions(books, 2, 3)))
+	assert.Equal(t, []int64{1, 2, 3, 4}, ids(trimEditions(books, 2, 4)))
+	assert.Equal(t, []int64{1, 2, 3, 4, 5}, ids(trimEditions(books, 2, 10)))
+
+	t.Run("primary language", func(t *testing.T) {
+		require.NoError(t, SetPrimaryLanguage("fre"))
+		t.Cleanup(func() { _ = SetPrimaryLanguage("") })
+
+		// The primary language is kept ahead of the best edition's language.
+		assert.Equal(t, []int64{2, 5}, ids(trimEditions(books, 2, 2)))
+		assert.Equal(t, []int64{2, 4, 5}, ids(trimEditions(books, 2, 3)))
+	})
+}
+
 func TestFuzz(t *testing.T) {
 	fuzzed := fuzz(_authorTTL, 2)
 	assert.Less(t, fuzzed, _authorTTL*2)
@@ -506,3 +817,982 @@ func waitForDenorm(ctrl *Controller) {
 		time.Sleep(100 * time.Millisecond)
 	}
 }
+
+func TestNewTransport(t *testing.T) {
+	transport := NewTransport(WithIdleConns(50, 20, time.Minute))
+	assert.Equal(t, 50, transport.MaxIdleConns)
+	assert.Equal(t, 20, transport.MaxIdleConnsPerHost)
+	assert.Equal(t, time.Minute, transport.IdleConnTimeout)
+
+	// The default transport is left alone.
+	assert.NotSame(t, http.DefaultTransport, transport)
+	assert.Equal(t, 0, http.DefaultTransport.(*http.Transport).MaxIdleConnsPerHost)
+
+	proxy, err := url.Parse("http://proxy.local:3128")
+	require.NoError(t, err)
+	transport = NewTransport(WithProxy(proxy))
+	got, err := transport.Proxy(httptest.NewRequest("GET", "https://api.hardcover.app/v1/graphql", nil))
+	require.NoError(t, err)
+	assert.Equal(t, proxy, got)
+}
+
+func TestRankSearch(t *testing.T) {
+	results := func() []SearchResource {
+		return []SearchResource{
+			{BookID: 1, title: "The Crossing Guard", ratingCount: 5000, releaseDate: "2001-01-01"},
+			{BookID: 2, title: "The Crossing", ratingCount: 100, releaseDate: "2015-11-03"},
+			{BookID: 3, title: "the crossing", ratingCount: 2000, releaseDate: "1994-01-01"},
+			{BookID: 4, title: "Crossings", ratingCount: 5000, releaseDate: "2020-01-01"},
+		}
+	}
+	ids := func(results []SearchResource) []int64 {
+		out := []int64{}
+		for _, r := range results {
+			out = append(out, r.BookID)
+		}
+		return out
+	}
+
+	tests := []struct {
+		name    string
+		signals []SearchSignal
+		want    []int64
+	}{
+		{name: "provider order", want: []int64{1, 2, 3, 4}},
+		{name: "title", signals: []SearchSignal{SearchByTitle}, want: []int64{2, 3, 1, 4}},
+		{name: "ratings", signals: []SearchSignal{SearchByRatings}, want: []int64{1, 4, 3, 2}},
+		{name: "recency", signals: []SearchSignal{SearchByRecency}, want: []int64{4, 2, 1, 3}},
+		{name: "title then ratings", signals: []SearchSignal{SearchByTitle, SearchByRatings}, want: []int64{3, 2, 1, 4}},
+		{name: "ratings then recency", signals: []SearchSignal{SearchByRatings, SearchByRecency}, want: []int64{4, 1, 3, 2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := results()
+			rankSearch(r, " The Crossing ", tt.signals)
+			assert.Equal(t, tt.want, ids(r))
+		})
+	}
+}
+
+func TestPickSeries(t *testing.T) {
+	seriesWorks := map[int64]int{
+		10: 1,
+		20: 5,
+		30: 2,
+		40: 5,
+	}
+
+	assert.Equal(t, []int64{20, 40, 30, 10}, pickSeries(seriesWorks, 0))
+	assert.Equal(t, []int64{20, 40}, pickSeries(seriesWorks, 2))
+	assert.Equal(t, []int64{20, 40, 30, 10}, pickSeries(seriesWorks, 10))
+	assert.Empty(t, pickSeries(map[int64]int{}, 2))
+}
+
+func TestAuthorGenres(t *testing.T) {
+	// Authors list their works' most common genres, without the placeholder
+	// for works which have none.
+	ctx := t.Context()
+
+	authorBytes, err := json.Marshal(AuthorResource{ForeignID: 1, Works: []workResource{}})
+	require.NoError(t, err)
+	cache := newMemoryCache()
+	cache.Set(ctx, AuthorKey(1), authorBytes, time.Hour)
+
+	getter := NewMockgetter(gomock.NewController(t))
+	for workID, genres := range map[int64][]string{
+		2: {"fantasy", "romance"},
+		3: {"fantasy", "horror"},
+		4: {"horror", "fantasy"},
+		5: {_genrePlaceholder},
+	} {
+		workBytes, err := json.Marshal(workResource{ForeignID: workID, Genres: genres, Books: []bookResource{{ForeignID: workID * 10}}})
+		require.NoError(t, err)
+		getter.EXPECT().GetWork(gomock.Any(), workID, nil).Return(workBytes, int64(1), nil)
+	}
+
+	ctrl, err := NewController(cache, getter, nil, nil)
+	require.NoError(t, err)
+	require.NoError(t, ctrl.denormalizeWorks(ctx, 1, 2, 3, 4, 5))
+
+	out, ok := cache.Get(ctx, AuthorKey(1))
+	require.True(t, ok)
+	var author AuthorResource
+	require.NoError(t, json.Unmarshal(out, &author))
+	assert.Equal(t, []string{"fantasy", "horror", "romance"}, author.Genres)
+
+	counts := map[string]int{"a": 1, "b": 3, "c": 2, "d": 2}
+	assert.Equal(t, []string{"b", "c"}, topGenres(counts, 2))
+	assert.Equal(t, []string{"b", "c", "d", "a"}, topGenres(counts, 10))
+	assert.Empty(t, topGenres(map[string]int{_genrePlaceholder: 4}, 2))
+}
+
+func TestTopWorks(t *testing.T) {
+	works := []workResource{
+		{ForeignID: 1, ReleaseDate: "1990-01-01", RatingCount: 1000, RatingSum: 4500}, // Old classic.
+		{ForeignID: 2, ReleaseDate: "2024-01-01", RatingCount: 10, RatingSum: 30},     // New, few ratings.
+		{ForeignID: 3, ReleaseDate: "2000-01-01", RatingCount: 1, RatingSum: 2},       // Old and obscure.
+		{ForeignID: 4, ReleaseDate: "2020-01-01", Books: []bookResource{
+			{RatingCount: 300, RatingSum: 1200}, // GR rates editions.
+		}},
+	}
+
+	ids := func(works []workResource) []int64 {
+		out := []int64{}
+		for _, w := range works {
+			out = append(out, w.ForeignID)
+		}
+		return out
+	}
+
+	assert.Equal(t, []int64{1, 4}, ids(topWorks(works, 2, 0)))      // Only rating.
+	assert.Equal(t, []int64{2, 4}, ids(topWorks(works, 2, 1)))      // Only recency.
+	assert.Equal(t, []int64{1, 2, 4}, ids(topWorks(works, 3, 0.5))) // Balanced.
+	assert.Equal(t, []int64{1, 2, 3, 4}, ids(topWorks(works, 10, 0.5)))
+	assert.Equal(t, []int64{1, 2, 3, 4}, ids(topWorks(works, 0, 0.5)))
+}
+
+func TestTrimSeriesLinks(t *testing.T) {
+	links := []seriesWorkLinkResource{
+		{ForeignWorkID: 1}, {ForeignWorkID: 2}, {ForeignWorkID: 3}, {ForeignWorkID: 4},
+	}
+	authorWorks := map[int64]struct{}{2: {}, 4: {}}
+	ids := func(links []seriesWorkLinkResource) []int64 {
+		out := []int64{}
+		for _, l := range links {
+			out = append(out, l.ForeignWorkID)
+		}
+		return out
+	}
+
+	assert.Equal(t, []int64{1, 2, 3, 4}, ids(trimSeriesLinks(links, authorWorks, false, 0)))
+	assert.Equal(t, []int64{2, 4}, ids(trimSeriesLinks(links, authorWorks, true, 0)))
+	assert.Equal(t, []int64{2}, ids(trimSeriesLinks(links, authorWorks, true, 1)))
+	// The author's own works are kept first, in series order.
+	assert.Equal(t, []int64{1, 2, 4}, ids(trimSeriesLinks(links, authorWorks, false, 3)))
+	assert.Equal(t, []int64{2, 4}, ids(trimSeriesLinks(links, authorWorks, false, 2)))
+	// The original slice isn't modified.
+	assert.Equal(t, []int64{1, 2, 3, 4}, ids(links))
+}
+
+func TestContinuing(t *testing.T) {
+	now := time.Date(2025, 6, 15, 0, 0, 0, 0, time.UTC)
+
+	old := []workResource{{ReleaseDate: "2020-01-01"}, {}}
+	assert.False(t, continuing(old, 12, now))
+
+	recent := append(old, workResource{ReleaseDate: "2024-12-01 00:00:00"})
+	assert.True(t, continuing(recent, 12, now))
+	assert.False(t, continuing(recent, 6, now))
+	assert.False(t, continuing(recent, 0, now))
+
+	upcoming := append(old, workResource{ReleaseDate: "2026-03-01"})
+	assert.True(t, continuing(upcoming, 1, now))
+}
+
+func TestReconfigure(t *testing.T) {
+	// Reconfiguring should take effect immediately and keep the warm cache.
+
+	ctx := t.Context()
+	oldID := int64(1)
+	newID := int64(2)
+
+	cache := newMemoryCache()
+	authorBytes, err := json.Marshal(AuthorResource{ForeignID: newID})
+	require.NoError(t, err)
+	cache.Set(ctx, AuthorKey(newID), authorBytes, time.Hour)
+
+	ctrl, err := NewController(cache, NewMockgetter(gomock.NewController(t)), nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 1000, ctrl.options().maxAuthorWorks)
+
+	ctrl.Reconfigure(WithMaxAuthorWorks(10), WithAuthorAliases(map[int64]int64{oldID: newID}))
+	assert.Equal(t, 10, ctrl.options().maxAuthorWorks)
+
+	out, _, err := ctrl.GetAuthor(ctx, oldID)
+	require.NoError(t, err)
+
+	var author AuthorResource
+	require.NoError(t, json.Unmarshal(out, &author))
+	assert.Equal(t, newID, author.ForeignID)
+
+	// Omitted options revert to their defaults.
+	ctrl.Reconfigure()
+	assert.Equal(t, 1000, ctrl.options().maxAuthorWorks)
+	assert.Empty(t, ctrl.options().authorAliases)
+}
+
+func TestSaveEditionsBackpressure(t *testing.T) {
+	// Saving editions should block once the worker pool is saturated instead
+	// of spawning more goroutines.
+
+	authorID := int64(100)
+	edition := func(workID, editionID int64) workResource {
+		return workResource{
+			ForeignID: workID,
+			Authors:   []AuthorResource{{ForeignID: authorID}},
+			Books: []bookResource{{
+				ForeignID:    editionID,
+				Contributors: []contributorResource{{ForeignID: authorID}},
+			}},
+		}
+	}
+
+	cache := newMemoryCache()
+	authorBytes, err := json.Marshal(AuthorResource{ForeignID: authorID})
+	require.NoError(t, err)
+	cache.Set(t.Context(), AuthorKey(authorID), authorBytes, time.Hour)
+
+	ctrl, err := NewController(cache, NewMockgetter(gomock.NewController(t)), nil, nil)
+	require.NoError(t, err)
+	ctrl.saveG.SetLimit(1)
+
+	// The first save occupies the only worker until we drain its edge, so
+	// there's no room for another.
+	ctrl.saveEditions(edition(1, 10))
+	assert.False(t, ctrl.saveG.TryGo(func() error { return nil }))
+
+	saved := make(chan struct{})
+	go func() {
+		ctrl.saveEditions(edition(2, 20))
+		close(saved)
+	}()
+
+	e := <-ctrl.denormC
+	assert.Equal(t, int64(1), e.parentID)
+	<-saved
+
+	e = <-ctrl.denormC
+	assert.Equal(t, int64(2), e.parentID)
+}
+
+func TestRetryDenorm(t *testing.T) {
+	backoff := _denormBackoff
+	_denormBackoff = time.Millisecond
+	t.Cleanup(func() { _denormBackoff = backoff })
+
+	ctrl, err := NewController(newMemoryCache(), NewMockgetter(gomock.NewController(t)), nil, nil)
+	require.NoError(t, err)
+
+	e := edge{kind: authorEdge, parentID: 1, childIDs: newSet[int64](2)}
+
+	// Transient failures are retried with an incremented attempt count.
+	ctrl.retryDenorm(t.Context(), e, statusErr(http.StatusTooManyRequests))
+	retried := <-ctrl.denormC
+	assert.Equal(t, 1, retried.attempts)
+	assert.Equal(t, e.childIDs, retried.childIDs)
+
+	// Missing resources aren't retried.
+	ctrl.retryDenorm(t.Context(), e, errNotFound)
+
+	// Retries are capped.
+	e.attempts = _denormRetries
+	ctrl.retryDenorm(t.Context(), e, statusErr(http.StatusTooManyRequests))
+	assert.Equal(t, 1.0, ctrl.metrics.denormExhaustedGet())
+
+	select {
+	case unexpected := <-ctrl.denormC:
+		t.Fatalf("unexpected retry: %+v", unexpected)
+	case <-time.After(50 * time.Millisecond):
+	}
+	assert.Equal(t, int32(0), ctrl.denormRetries.Load())
+}
+
+func TestWaitForMemory(t *testing.T) {
+	limit, heap, poll := _memoryLimit, _heapBytes, _memoryPollInterval
+	t.Cleanup(func() { _memoryLimit, _heapBytes, _memoryPollInterval = limit, heap, poll })
+
+	var used atomic.Uint64
+	_memoryLimit = func() int64 { return 1000 }
+	_heapBytes = used.Load
+	_memoryPollInterval = time.Millisecond
+
+	ctrl, err := NewController(newMemoryCache(), NewMockgetter(gomock.NewController(t)), nil, nil, WithRefreshMemoryThreshold(0.8))
+	require.NoError(t, err)
+
+	// Under budget: no waiting.
+	used.Store(700)
+	ctrl.waitForMemory(t.Context())
+
+	// Over budget: wait until usage drops.
+	used.Store(900)
+	done := make(chan struct{})
+	go func() {
+		ctrl.waitForMemory(t.Context())
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		t.Fatal("expected refreshes to be paused")
+	case <-time.After(50 * time.Millisecond):
+	}
+	assert.Equal(t, 1.0, ctrl.metrics.refreshPausedGet())
+
+	used.Store(500)
+	<-done
+	assert.Equal(t, 0.0, ctrl.metrics.refreshPausedGet())
+
+	// Disabled by default.
+	ctrl.Reconfigure()
+	used.Store(900)
+	ctrl.waitForMemory(t.Context())
+}
+
+func TestDenormHeartbeat(t *testing.T) {
+	ctrl, err := NewController(newMemoryCache(), NewMockgetter(gomock.NewController(t)), nil, nil)
+	require.NoError(t, err)
+	assert.Zero(t, ctrl.metrics.denormHeartbeatGet())
+
+	// Busy: the heartbeat only advances as edges complete.
+	ctrl.heartbeatIfIdle(1)
+	assert.Zero(t, ctrl.metrics.denormHeartbeatGet())
+	ctrl.denormActive.Add(1)
+	ctrl.heartbeatIfIdle(0)
+	assert.Zero(t, ctrl.metrics.denormHeartbeatGet())
+
+	// Idle: the heartbeat advances on its own.
+	ctrl.denormActive.Add(-1)
+	ctrl.heartbeatIfIdle(0)
+	assert.InDelta(t, float64(time.Now().Unix()), ctrl.metrics.denormHeartbeatGet(), 1)
+}
+
+type stubGetter struct {
+	*Mockgetter
+	stub []byte
+}
+
+func (g stubGetter) GetAuthorStub(context.Context, int64) ([]byte, error) {
+	return g.stub, nil
+}
+
+func TestAuthorStubs(t *testing.T) {
+	// A cold author should return a stub right away and load the full author
+	// in the background.
+
+	ctx := t.Context()
+	authorID := int64(1)
+
+	stubBytes, err := json.Marshal(AuthorResource{ForeignID: authorID, Name: "stub", Works: []workResource{}, Series: []SeriesResource{}})
+	require.NoError(t, err)
+	fullBytes, err := json.Marshal(AuthorResource{ForeignID: authorID, Name: "full", Works: []workResource{}, Series: []SeriesResource{}})
+	require.NoError(t, err)
+
+	release := make(chan struct{})
+	getter := NewMockgetter(gomock.NewController(t))
+	getter.EXPECT().GetAuthor(gomock.Any(), authorID).DoAndReturn(func(context.Context, int64) ([]byte, error) {
+		<-release
+		return fullBytes, nil
+	})
+
+	cache := newMemoryCache()
+	ctrl, err := NewController(cache, stubGetter{Mockgetter: getter, stub: stubBytes}, nil, nil, WithAuthorStubs(true))
+	require.NoError(t, err)
+
+	out, ttl, err := ctrl.GetAuthor(ctx, authorID)
+	require.NoError(t, err)
+	assert.Equal(t, stubBytes, out)
+	assert.Equal(t, _authorStubTTL, ttl)
+
+	// The full author replaces the stub once it's loaded.
+	close(release)
+	refresh := <-ctrl.refreshC
+	assert.Equal(t, authorID, refresh.id)
+
+	out, _, err = ctrl.GetAuthor(ctx, authorID)
+	require.NoError(t, err)
+	assert.Equal(t, fullBytes, out)
+}
+
+func TestGuardNulls(t *testing.T) {
+	// Null slices crash the client, so they're repaired before caching.
+
+	ctx := t.Context()
+	ctrl, err := NewController(newMemoryCache(), NewMockgetter(gomock.NewController(t)), nil, nil)
+	require.NoError(t, err)
+
+	authorBytes := []byte(`{"ForeignId":1,"Works":[{"ForeignId":2,"Books":null,"Genres":["fiction"],"RelatedWorks":[],"Series":[],"Authors":[]}],"Series":[]}`)
+	out := ctrl.guardAuthorBytes(ctx, 1, authorBytes)
+	assert.NotContains(t, string(out), "null")
+	assert.Equal(t, 1.0, ctrl.metrics.nullsRepairedGet())
+
+	var author AuthorResource
+	require.NoError(t, json.Unmarshal(out, &author))
+	assert.NotNil(t, author.Works[0].Books)
+	assert.Equal(t, []string{"fiction"}, author.Works[0].Genres)
+
+	// Valid resources are left alone.
+	assert.Equal(t, out, ctrl.guardAuthorBytes(ctx, 1, out))
+	assert.Equal(t, 1.0, ctrl.metrics.nullsRepairedGet())
+
+	work := workResource{Books: []bookResource{{ForeignID: 3}}}
+	assert.Equal(t, []string{"Genres", "RelatedWorks", "Series", "Authors", "Books.Contributors"}, work.repairNulls())
+	assert.Empty(t, work.repairNulls())
+
+	// Books are repaired too.
+	getter := NewMockgetter(gomock.NewController(t))
+	getter.EXPECT().GetBook(gomock.Any(), int64(3), gomock.Any()).Return([]byte(`{"ForeignId":0,"Books":[{"ForeignId":3}]}`), int64(0), int64(0), nil)
+	ctrl, err = NewController(newMemoryCache(), getter, nil, nil)
+	require.NoError(t, err)
+
+	out, _, err = ctrl.GetBook(ctx, 3)
+	require.NoError(t, err)
+	assert.NotContains(t, string(out), "null")
+	cached, ok := ctrl.cache.Get(ctx, BookKey(3))
+	require.True(t, ok)
+	assert.Equal(t, out, cached)
+	assert.Equal(t, 1.0, ctrl.metrics.nullsRepairedGet())
+}
+
+func TestWorkAuthorOverride(t *testing.T) {
+	// Misattributed works can be corrected to another author.
+
+	ctx := t.Context()
+	wrongID, rightID := int64(1), int64(2)
+	workID, bookID := int64(10), int64(100)
+
+	cache := newMemoryCache()
+	rightBytes, err := json.Marshal(AuthorResource{ForeignID: rightID, Name: "Right", Works: []workResource{}, Series: []SeriesResource{}})
+	require.NoError(t, err)
+	cache.Set(ctx, AuthorKey(rightID), rightBytes, time.Hour)
+
+	work := workResource{
+		ForeignID: workID,
+		Title:     "Misattributed",
+		Authors:   []AuthorResource{{ForeignID: wrongID, Name: "Wrong"}},
+		Books:     []bookResource{{ForeignID: bookID, Contributors: []contributorResource{{ForeignID: wrongID, Role: "Author"}}}},
+	}
+	workBytes, err := json.Marshal(work)
+	require.NoError(t, err)
+
+	wrongBytes, err := json.Marshal(AuthorResource{ForeignID: wrongID, Name: "Wrong", Works: []workResource{work}, Series: []SeriesResource{}})
+	require.NoError(t, err)
+	cache.Set(ctx, AuthorKey(wrongID), wrongBytes, time.Hour)
+
+	getter := NewMockgetter(gomock.NewController(t))
+	getter.EXPECT().GetBook(gomock.Any(), bookID, gomock.Any()).Return(workBytes, workID, wrongID, nil).AnyTimes()
+	getter.EXPECT().GetWork(gomock.Any(), workID, gomock.Any()).Return(workBytes, wrongID, nil).AnyTimes()
+
+	ctrl, err := NewController(cache, getter, nil, nil, WithWorkAuthors(map[int64]int64{workID: rightID}))
+	require.NoError(t, err)
+
+	out, _, err := ctrl.GetBook(ctx, bookID)
+	require.NoError(t, err)
+
+	var got workResource
+	require.NoError(t, json.Unmarshal(out, &got))
+	assert.Equal(t, rightID, got.Authors[0].ForeignID)
+	assert.Equal(t, "Right", got.Authors[0].Name)
+	assert.Equal(t, rightID, got.Books[0].Contributors[0].ForeignID)
+
+	// The work is removed from the wrong author when denormalizing.
+	require.NoError(t, ctrl.denormalizeWorks(ctx, wrongID, workID))
+
+	out, _, err = ctrl.GetAuthor(ctx, wrongID)
+	require.NoError(t, err)
+
+	var author AuthorResource
+	require.NoError(t, json.Unmarshal(out, &author))
+	assert.Empty(t, author.Works)
+}
+
+// brokenSonic fails every decode and writes partial output before failing
+// every encode.
+type brokenSonic struct{ sonic.API }
+
+func (brokenSonic) Unmarshal([]byte, any) error { return errors.New("exceeded limit") }
+
+func (brokenSonic) NewEncoder(w io.Writer) sonic.Encoder { return brokenEncoder{w: w} }
+
+type brokenEncoder struct {
+	sonic.Encoder
+	w io.Writer
+}
+
+func (e brokenEncoder) Encode(any) error {
+	_, _ = e.w.Write([]byte(`{"partial`))
+	return errors.New("exceeded limit")
+}
+
+func TestJSONFallback(t *testing.T) {
+	// Denormalization still works, slowly, if sonic can't handle an entry.
+
+	ctrl, err := NewController(newMemoryCache(), nil, nil, nil)
+	require.NoError(t, err)
+	ctrl.codec = brokenSonic{API: ctrl.codec}
+
+	ctx := t.Context()
+	author := AuthorResource{ForeignID: 1, Name: "Huge", Works: []workResource{}, Series: []SeriesResource{}}
+	want, err := json.Marshal(author)
+	require.NoError(t, err)
+
+	var got AuthorResource
+	require.NoError(t, ctrl.decodeJSON(ctx, want, &got))
+	assert.Equal(t, author, got)
+
+	buf := _buffers.Get()
+	defer buf.Free()
+	require.NoError(t, ctrl.encodeJSON(ctx, buf, author))
+	assert.JSONEq(t, string(want), buf.String())
+
+	assert.Error(t, ctrl.decodeJSON(ctx, []byte("{"), &got))
+}
+
+func TestRecommendationsSeed(t *testing.T) {
+	// Recommendations similar to a seed are resolved against the seed work,
+	// and never include the seed itself.
+
+	ctx := t.Context()
+	cache := newMemoryCache()
+	for _, id := range []int64{1, 2, 3} {
+		out, err := json.Marshal(workResource{ForeignID: id, Genres: []string{"Fantasy"}})
+		require.NoError(t, err)
+		cache.Set(ctx, WorkKey(id), out, time.Hour)
+	}
+
+	getter := NewMockgetter(gomock.NewController(t))
+	getter.EXPECT().Recommendations(gomock.Any(), int64(1), gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ int64, filter recommendationsFilter) (RecommentationsResource, error) {
+			require.NotNil(t, filter.seed)
+			assert.Equal(t, int64(1), filter.seed.ForeignID)
+			assert.Equal(t, []string{"Fantasy"}, filter.seed.Genres)
+			return RecommentationsResource{WorkIDs: []int64{1, 2, 3}}, nil
+		})
+
+	ctrl, err := NewController(cache, getter, nil, nil)
+	require.NoError(t, err)
+
+	recs, err := ctrl.Recommendations(ctx, 1, "", 1)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []int64{2, 3}, recs.WorkIDs)
+}
+
+func TestSearchISBN(t *testing.T) {
+	// ISBNs are only included in search results when enabled.
+
+	ctx := t.Context()
+	cache := newMemoryCache()
+	out, err := json.Marshal(workResource{
+		ForeignID: 1,
+		Books:     []bookResource{{ForeignID: 10, Isbn13: "9780316769488"}},
+		Authors:   []AuthorResource{{ForeignID: 100}},
+	})
+	require.NoError(t, err)
+	cache.Set(ctx, BookKey(10), out, time.Hour)
+
+	getter := NewMockgetter(gomock.NewController(t))
+	getter.EXPECT().Search(gomock.Any(), "catcher").Return([]SearchResource{
+		{BookID: 10, WorkID: 1, Author: SearchResourceAuthor{ID: 100}},
+	}, nil).Times(2)
+
+	ctrl, err := NewController(cache, getter, nil, nil)
+	require.NoError(t, err)
+
+	results, err := ctrl.Search(ctx, "catcher")
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Empty(t, results[0].ISBN13)
+
+	ctrl, err = NewController(cache, getter, nil, nil, WithSearchISBN(true))
+	require.NoError(t, err)
+
+	results, err = ctrl.Search(ctx, "catcher")
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, "9780316769488", results[0].ISBN13)
+}
+
+func TestSearchAlias(t *testing.T) {
+	// Searching for a pseudonym also finds works by the author behind it.
+
+	ctx := t.Context()
+	cache := newMemoryCache()
+	authorBytes, err := json.Marshal(AuthorResource{ForeignID: 1, Works: []workResource{}})
+	require.NoError(t, err)
+	cache.Set(ctx, AuthorKey(1), authorBytes, time.Hour)
+
+	workBytes, err := json.Marshal(workResource{
+		ForeignID:  10,
+		BestBookID: 100,
+		Books:      []bookResource{{ForeignID: 100}},
+		Authors:    []AuthorResource{{ForeignID: 1, Aliases: []AuthorAlias{{ForeignID: 2, Name: "Robert Galbraith"}}}},
+	})
+	require.NoError(t, err)
+
+	getter := NewMockgetter(gomock.NewController(t))
+	getter.EXPECT().GetWork(gomock.Any(), int64(10), nil).Return(workBytes, int64(1), nil)
+	getter.EXPECT().Search(gomock.Any(), gomock.Any()).Return([]SearchResource{
+		{BookID: 200, WorkID: 20, Author: SearchResourceAuthor{ID: 2}},
+	}, nil).Times(2)
+
+	ctrl, err := NewController(cache, getter, nil, nil)
+	require.NoError(t, err)
+
+	results, err := ctrl.Search(ctx, "robert galbraith")
+	require.NoError(t, err)
+	assert.Equal(t, []int64{200}, searchBookIDs(results))
+
+	require.NoError(t, ctrl.denormalizeWorks(ctx, 1, 10))
+
+	out, ok := cache.Get(ctx, AuthorKey(1))
+	require.True(t, ok)
+	var author AuthorResource
+	require.NoError(t, json.Unmarshal(out, &author))
+	assert.Equal(t, []AuthorAlias{{ForeignID: 2, Name: "Robert Galbraith"}}, author.Aliases)
+
+	results, err = ctrl.Search(ctx, "  Robert  GALBRAITH ")
+	require.NoError(t, err)
+	assert.Equal(t, []int64{100, 200}, searchBookIDs(results))
+	assert.Equal(t, int64(1), results[0].Author.ID)
+}
+
+func searchBookIDs(results []SearchResource) []int64 {
+	ids := []int64{}
+	for _, r := range results {
+		ids = append(ids, r.BookID)
+	}
+	return ids
+}
+
+func TestCoalescePanic(t *testing.T) {
+	// A panic while loading is returned as an error to every coalesced caller.
+
+	release := make(chan struct{})
+	getter := NewMockgetter(gomock.NewController(t))
+	getter.EXPECT().GetBook(gomock.Any(), int64(1), gomock.Any()).DoAndReturn(
+		func(context.Context, int64, editionsCallback) ([]byte, int64, int64, error) {
+			<-release
+			panic("boom")
+		}).Times(1)
+
+	ctrl, err := NewController(newMemoryCache(), getter, nil, nil)
+	require.NoError(t, err)
+
+	wg := sync.WaitGroup{}
+	for range 3 {
+		wg.Go(func() {
+			out, _, err := ctrl.GetBook(t.Context(), 1)
+			assert.ErrorContains(t, err, "boom")
+			assert.Nil(t, out)
+		})
+	}
+
+	assert.Eventually(t, func() bool { return ctrl.metrics.inflightKeysGet() == 1 }, time.Second, time.Millisecond)
+	time.Sleep(10 * time.Millisecond) // Let the other callers join.
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, 1.0, ctrl.metrics.coalescedPanicsGet())
+	assert.Equal(t, 0.0, ctrl.metrics.inflightKeysGet())
+}
+
+func TestSpawnBounded(t *testing.T) {
+	// Background work beyond the cap is dropped, and finished (or panicking)
+	// goroutines give their slot back.
+
+	ctrl, err := NewController(newMemoryCache(), nil, nil, nil, WithBackgroundWorkers(2))
+	require.NoError(t, err)
+
+	release := make(chan struct{})
+	assert.True(t, ctrl.spawn(t.Context(), "test", func() { <-release }))
+	assert.True(t, ctrl.spawn(t.Context(), "test", func() { <-release; panic("boom") }))
+	assert.False(t, ctrl.spawn(t.Context(), "test", func() { t.Error("shouldn't run") }))
+
+	assert.Equal(t, 2.0, ctrl.metrics.backgroundGet())
+	assert.Equal(t, 1.0, ctrl.metrics.backgroundDroppedGet())
+
+	close(release)
+	assert.Eventually(t, func() bool { return ctrl.metrics.backgroundGet() == 0 }, time.Second, time.Millisecond)
+
+	// Edges are never dropped, even when the budget is exhausted.
+	block := make(chan struct{})
+	t.Cleanup(func() { close(block) })
+	assert.True(t, ctrl.spawn(t.Context(), "test", func() { <-block }))
+	assert.True(t, ctrl.spawn(t.Context(), "test", func() { <-block }))
+
+	go ctrl.enqueue(t.Context(), edge{kind: workEdge, parentID: 1}, edge{kind: workEdge, parentID: 2})
+	assert.Equal(t, int64(1), (<-ctrl.denormC).parentID)
+	assert.Equal(t, int64(2), (<-ctrl.denormC).parentID)
+	assert.Equal(t, 2.0, ctrl.metrics.backgroundDroppedGet())
+}
+
+func TestSpawnFallback(t *testing.T) {
+	// Loads which can't spawn their follow-up work still enqueue its edges.
+
+	getter := NewMockgetter(gomock.NewController(t))
+	ctrl, err := NewController(newMemoryCache(), getter, nil, nil, WithBackgroundWorkers(1))
+	require.NoError(t, err)
+
+	block := make(chan struct{})
+	t.Cleanup(func() { close(block) })
+	assert.True(t, ctrl.spawn(t.Context(), "test", func() { <-block }))
+
+	workBytes, err := json.Marshal(workResource{ForeignID: 10, Books: []bookResource{{ForeignID: 100}}})
+	require.NoError(t, err)
+	getter.EXPECT().GetBook(gomock.Any(), int64(100), gomock.Any()).Return(workBytes, int64(10), int64(1), nil)
+	getter.EXPECT().GetWork(gomock.Any(), int64(20), gomock.Any()).Return(workBytes, int64(2), nil)
+
+	go func() { _, _, _ = ctrl.GetBook(t.Context(), 100) }()
+	e := <-ctrl.denormC
+	assert.Equal(t, workEdge, e.kind)
+	assert.Equal(t, int64(10), e.parentID)
+	assert.Equal(t, newSet(int64(100)), e.childIDs)
+
+	go func() { _, _, _ = ctrl.GetWork(t.Context(), 20) }()
+	e = <-ctrl.denormC
+	assert.Equal(t, workEdge, e.kind)
+	assert.Equal(t, int64(20), e.parentID)
+	e = <-ctrl.denormC
+	assert.Equal(t, authorEdge, e.kind)
+	assert.Equal(t, int64(2), e.parentID)
+	assert.Equal(t, newSet(int64(20)), e.childIDs)
+}
+
+func TestSuperviseRestarts(t *testing.T) {
+	// A consumer which panics is restarted on the same input, and stops once
+	// its input is closed.
+
+	ctrl, err := NewController(newMemoryCache(), NewMockgetter(gomock.NewController(t)), nil, nil, WithRestartBackoff(time.Millisecond))
+	require.NoError(t, err)
+
+	in := make(chan int64)
+	go func() {
+		for i := range int64(3) {
+			in <- i
+		}
+		// Only continue once the consumer has been restarted.
+		assert.Eventually(t, func() bool { return ctrl.metrics.consumerRestartsGet() == 1 }, 5*time.Second, time.Millisecond)
+		in <- 3
+		in <- 4
+		close(in)
+	}()
+
+	handled := []int64{}
+	ctrl.supervise(t.Context(), "test", func() {
+		partition(t.Context(), in, 1, func(i int64) int64 { return i }, nil, func(i int64) {
+			if i == 2 {
+				panic("bad value")
+			}
+			handled = append(handled, i)
+		})
+	})
+
+	assert.Equal(t, 1.0, ctrl.metrics.consumerRestartsGet())
+	// The value which caused the panic is dropped.
+	assert.Equal(t, []int64{0, 1, 3, 4}, handled)
+}
+
+func TestConcurrentAuthorRefresh(t *testing.T) {
+	// Concurrent cold requests and recovered refreshes for the same author
+	// only refresh it once.
+	ctx := t.Context()
+	getter := NewMockgetter(gomock.NewController(t))
+
+	authorBytes, err := json.Marshal(AuthorResource{ForeignID: 1, Works: []workResource{}})
+	require.NoError(t, err)
+	getter.EXPECT().GetAuthor(gomock.Any(), int64(1)).Return(authorBytes, nil).AnyTimes()
+
+	var refreshes atomic.Int32
+	release := make(chan struct{})
+	getter.EXPECT().GetAuthorBooks(gomock.Any(), int64(1)).DoAndReturn(func(context.Context, int64) iter.Seq[int64] {
+		refreshes.Add(1)
+		return func(func(int64) bool) { <-release }
+	}).AnyTimes()
+
+	ctrl, err := NewController(newMemoryCache(), getter, nil, nil)
+	require.NoError(t, err)
+	runController(t, ctrl)
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Go(func() {
+			_, _, err := ctrl.GetAuthor(ctx, 1)
+			assert.NoError(t, err)
+		})
+	}
+	wg.Wait()
+	require.Eventually(t, func() bool { return refreshes.Load() == 1 }, time.Second, time.Millisecond)
+
+	// The same author is recovered while it's still refreshing.
+	for range 3 {
+		ctrl.refreshC <- refreshAuthor{id: 1}
+	}
+	require.Eventually(t, func() bool { return ctrl.metrics.refreshesDedupedGet() == 3 }, time.Second, time.Millisecond)
+
+	close(release)
+	assert.Equal(t, int32(1), refreshes.Load())
+}
+
+func TestRevalidateEditions(t *testing.T) {
+	// Editions still within their TTL aren't re-fetched.
+	ctx := t.Context()
+	cache := newMemoryCache()
+	cache.Set(ctx, BookKey(1), []byte(`{"ForeignId":10}`), time.Hour)
+
+	getter := NewMockgetter(gomock.NewController(t))
+	getter.EXPECT().GetBook(gomock.Any(), int64(2), gomock.Any()).Return([]byte(`{"ForeignId":10}`), int64(0), int64(0), nil)
+	getter.EXPECT().GetBook(gomock.Any(), int64(3), gomock.Any()).Return(nil, int64(0), int64(0), errNotFound)
+
+	ctrl, err := NewController(cache, getter, nil, nil, WithRevalidateWorkers(2))
+	require.NoError(t, err)
+
+	got := ctrl.revalidateEditions(ctx, []bookResource{{ForeignID: 1}, {ForeignID: 2}, {ForeignID: 3}})
+	slices.Sort(got)
+	assert.Equal(t, []int64{1, 2}, got)
+	assert.Equal(t, 1.0, ctrl.metrics.editionsRevalidationSkippedGet())
+	assert.Equal(t, 2.0, ctrl.metrics.editionsRevalidatedGet())
+}
+
+func TestKeepEmptyWorks(t *testing.T) {
+	// A work whose editions were all filtered out is dropped from its author
+	// unless we're keeping empty works.
+	ctx := t.Context()
+
+	authorBytes, err := json.Marshal(AuthorResource{ForeignID: 1, Works: []workResource{}})
+	require.NoError(t, err)
+	workBytes, err := json.Marshal(workResource{ForeignID: 2, Title: "Empty"})
+	require.NoError(t, err)
+
+	for _, keep := range []bool{false, true} {
+		cache := newMemoryCache()
+		cache.Set(ctx, AuthorKey(1), authorBytes, time.Hour)
+
+		getter := NewMockgetter(gomock.NewController(t))
+		getter.EXPECT().GetWork(gomock.Any(), int64(2), nil).Return(workBytes, int64(1), nil)
+
+		ctrl, err := NewController(cache, getter, nil, nil, WithKeepEmptyWorks(keep))
+		require.NoError(t, err)
+		require.NoError(t, ctrl.denormalizeWorks(ctx, 1, 2))
+
+		out, ok := cache.Get(ctx, AuthorKey(1))
+		require.True(t, ok)
+		var author AuthorResource
+		require.NoError(t, json.Unmarshal(out, &author))
+
+		if !keep {
+			assert.Empty(t, author.Works)
+			continue
+		}
+		require.Len(t, author.Works, 1)
+		assert.NotNil(t, author.Works[0].Books)
+		assert.Empty(t, author.Works[0].Books)
+		assert.Contains(t, string(out), `"Books":[]`)
+	}
+}
+
+func TestDenormalizeReusesDecodedAuthor(t *testing.T) {
+	// Consecutive denormalizations of the same author only decode it again
+	// if something else changed it in the meantime.
+	ctx := t.Context()
+
+	authorBytes, err := json.Marshal(AuthorResource{ForeignID: 1, Works: []workResource{}})
+	require.NoError(t, err)
+	cache := newMemoryCache()
+	cache.Set(ctx, AuthorKey(1), authorBytes, time.Hour)
+
+	getter := NewMockgetter(gomock.NewController(t))
+	for _, workID := range []int64{2, 3, 4} {
+		workBytes, err := json.Marshal(workResource{ForeignID: workID, Books: []bookResource{{ForeignID: workID * 10}}})
+		require.NoError(t, err)
+		getter.EXPECT().GetWork(gomock.Any(), workID, nil).Return(workBytes, int64(1), nil)
+	}
+
+	ctrl, err := NewController(cache, getter, nil, nil)
+	require.NoError(t, err)
+
+	require.NoError(t, ctrl.denormalizeWorks(ctx, 1, 2))
+	require.NoError(t, ctrl.denormalizeWorks(ctx, 1, 3))
+	assert.Equal(t, 1.0, ctrl.metrics.authorDecodesSkippedGet())
+
+	// Change the author out from under us.
+	out, ok := cache.Get(ctx, AuthorKey(1))
+	require.True(t, ok)
+	var author AuthorResource
+	require.NoError(t, json.Unmarshal(out, &author))
+	author.Name = "Changed"
+	authorBytes, err = json.Marshal(author)
+	require.NoError(t, err)
+	cache.Set(ctx, AuthorKey(1), authorBytes, time.Hour)
+
+	require.NoError(t, ctrl.denormalizeWorks(ctx, 1, 4))
+	assert.Equal(t, 1.0, ctrl.metrics.authorDecodesSkippedGet())
+
+	out, ok = cache.Get(ctx, AuthorKey(1))
+	require.True(t, ok)
+	require.NoError(t, json.Unmarshal(out, &author))
+	assert.Equal(t, "Changed", author.Name)
+	assert.Len(t, author.Works, 3)
+}
+
+func BenchmarkDenormalizeWorks(b *testing.B) {
+	ctx := b.Context()
+
+	author := AuthorResource{ForeignID: 1, Name: "Prolific"}
+	for i := range 2000 {
+		w := workResource{ForeignID: int64(i + 100), Title: fmt.Sprint("Work ", i), FullTitle: fmt.Sprint("Work ", i, ": A Novel")}
+		for j := range 5 {
+			w.Books = append(w.Books, bookResource{ForeignID: int64(i*10 + j), Title: w.Title, Description: "Lorem ipsum dolor sit amet."})
+		}
+		author.Works = append(author.Works, w)
+	}
+	authorBytes, err := json.Marshal(author)
+	require.NoError(b, err)
+
+	getter := NewMockgetter(gomock.NewController(b))
+	getter.EXPECT().GetWork(gomock.Any(), gomock.Any(), nil).DoAndReturn(func(_ context.Context, workID int64, _ editionsCallback) ([]byte, int64, error) {
+		// Give every call a new title so the author always changes.
+		out, err := json.Marshal(workResource{ForeignID: workID, Title: fmt.Sprint(time.Now().UnixNano()), Books: []bookResource{{ForeignID: workID}}})
+		return out, 1, err
+	}).AnyTimes()
+
+	for _, reuse := range []bool{false, true} {
+		b.Run(fmt.Sprintf("reuse=%t", reuse), func(b *testing.B) {
+			cache := newMemoryCache()
+			cache.Set(ctx, AuthorKey(1), authorBytes, time.Hour)
+			ctrl, err := NewController(cache, getter, nil, nil)
+			require.NoError(b, err)
+
+			for b.Loop() {
+				if !reuse {
+					ctrl.decoded.delete(1)
+				}
+				require.NoError(b, ctrl.denormalizeWorks(ctx, 1, 100))
+			}
+		})
+	}
+}
+
+// runController runs the controller's background loops until the test
+// finishes, and waits for them to stop.
+func runController(t testing.TB, ctrl *Controller) {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.WithoutCancel(t.Context()))
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		ctrl.Run(ctx)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+}
+
+// expectRefresh expects the author to be refreshed in the background, and
+// waits for it before the controller is stopped. Must be called after
+// runController.
+func expectRefresh(t testing.TB, getter *Mockgetter, authorID int64, books iter.Seq[int64]) {
+	t.Helper()
+	refreshed := make(chan struct{})
+	getter.EXPECT().GetAuthorBooks(gomock.Any(), authorID).DoAndReturn(func(context.Context, int64) iter.Seq[int64] {
+		close(refreshed)
+		return books
+	})
+	t.Cleanup(func() {
+		select {
+		case <-refreshed:
+		case <-time.After(10 * time.Second):
+			t.Errorf("author %d was never refreshed", authorID)
+		}
+	})
+}
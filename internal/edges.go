@@ -1,5 +1,10 @@
 package internal
 
+import (
+	"maps"
+	"slices"
+)
+
 type edgeKind int
 
 const (
@@ -14,4 +19,19 @@ type edge struct {
 	kind     edgeKind
 	parentID int64
 	childIDs set[int64]
+
+	// attempts counts how many times denormalizing this edge has failed.
+	attempts int
+}
+
+// split keeps at most n of the edge's children, in ID order, and returns
+// another edge with the rest. False is returned if there was nothing to split
+// off. Non-positive n never splits.
+func (e *edge) split(n int) (edge, bool) {
+	if n <= 0 || len(e.childIDs) <= n {
+		return edge{}, false
+	}
+	ids := slices.Sorted(maps.Keys(e.childIDs))
+	e.childIDs = newSet(ids[:n]...)
+	return edge{kind: e.kind, parentID: e.parentID, childIDs: newSet(ids[n:]...)}, true
 }
@@ -0,0 +1,83 @@
+package internal
+
+import (
+	"bytes"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// _gzipMinBytes is the smallest response worth compressing. Anything smaller
+// fits in a packet either way.
+var _gzipMinBytes = 1024
+
+// acceptsGzip returns true if the client's Accept-Encoding allows gzip. An
+// explicit gzip (or x-gzip) entry wins over a wildcard, and q=0 rejects it.
+func acceptsGzip(r *http.Request) bool {
+	wildcard := false
+	for _, accept := range r.Header.Values("Accept-Encoding") {
+		for part := range strings.SplitSeq(accept, ",") {
+			coding, params, _ := strings.Cut(part, ";")
+			coding = strings.ToLower(strings.TrimSpace(coding))
+
+			q := 1.0
+			if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+				parsed, err := strconv.ParseFloat(v, 64)
+				if err != nil {
+					continue
+				}
+				q = parsed
+			}
+
+			switch coding {
+			case "gzip", "x-gzip":
+				return q > 0
+			case "*":
+				wildcard = q > 0
+			}
+		}
+	}
+	return wildcard
+}
+
+// vary adds values to the Vary header unless they're already present.
+func vary(w http.ResponseWriter, values ...string) {
+	present := map[string]bool{}
+	for _, v := range w.Header().Values("Vary") {
+		for part := range strings.SplitSeq(v, ",") {
+			present[strings.ToLower(strings.TrimSpace(part))] = true
+		}
+	}
+	missing := []string{}
+	for _, v := range values {
+		if !present[strings.ToLower(v)] {
+			missing = append(missing, v)
+		}
+	}
+	if len(missing) > 0 {
+		w.Header().Add("Vary", strings.Join(missing, ","))
+	}
+}
+
+// encode gzips the response body if compression is enabled and the client
+// accepts it, setting Content-Encoding to match. Once enabled the same URL can
+// be served either way, so Vary always includes Accept-Encoding -- otherwise a
+// shared CDN could hand a compressed body to a client which can't read it.
+func (h *Handler) encode(w http.ResponseWriter, r *http.Request, out []byte) []byte {
+	if !h.gzip {
+		return out
+	}
+	vary(w, "Accept-Encoding")
+	if len(out) < _gzipMinBytes || !acceptsGzip(r) || w.Header().Get("Content-Encoding") != "" {
+		return out
+	}
+
+	buf := _buffers.Get()
+	defer buf.Free()
+	if err := compress(bytes.NewReader(out), buf); err != nil {
+		Log(r.Context()).Warn("unable to compress response", "err", err)
+		return out
+	}
+	w.Header().Set("Content-Encoding", "gzip")
+	return bytes.Clone(buf.Bytes())
+}
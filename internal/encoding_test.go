@@ -0,0 +1,111 @@
+package internal
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestAcceptsGzip(t *testing.T) {
+	tests := []struct {
+		header string
+		want   bool
+	}{
+		{header: "", want: false},
+		{header: "gzip", want: true},
+		{header: "GZIP", want: true},
+		{header: "x-gzip", want: true},
+		{header: "deflate, gzip;q=0.5", want: true},
+		{header: "gzip;q=0", want: false},
+		{header: "identity", want: false},
+		{header: "br, deflate", want: false},
+		{header: "*", want: true},
+		{header: "*;q=0", want: false},
+		{header: "gzip;q=0, *", want: false}, // Explicit rejection wins.
+		{header: "gzip;q=nope", want: false},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest(http.MethodGet, "/author/1", nil)
+		if tt.header != "" {
+			r.Header.Set("Accept-Encoding", tt.header)
+		}
+		assert.Equal(t, tt.want, acceptsGzip(r), tt.header)
+	}
+}
+
+func TestGzipResponses(t *testing.T) {
+	// Compressed and uncompressed responses must never be confused by a
+	// shared cache, so both always vary on Accept-Encoding.
+
+	ctx := t.Context()
+	cache := newMemoryCache()
+	authorBytes, err := json.Marshal(AuthorResource{ForeignID: 1, Description: strings.Repeat("Prolific. ", 200)})
+	require.NoError(t, err)
+	cache.Set(ctx, AuthorKey(1), authorBytes, time.Hour)
+
+	ctrl, err := NewController(cache, NewMockgetter(gomock.NewController(t)), nil, nil)
+	require.NoError(t, err)
+	h := NewHandler(ctrl)
+	mux := NewMux(h, prometheus.NewRegistry())
+
+	tests := []struct {
+		enabled        bool
+		acceptEncoding string
+		wantGzip       bool
+	}{
+		{enabled: false, acceptEncoding: "gzip", wantGzip: false},
+		{enabled: true, acceptEncoding: "", wantGzip: false},
+		{enabled: true, acceptEncoding: "identity", wantGzip: false},
+		{enabled: true, acceptEncoding: "gzip;q=0, *", wantGzip: false},
+		{enabled: true, acceptEncoding: "gzip", wantGzip: true},
+		{enabled: true, acceptEncoding: "br, gzip;q=0.8", wantGzip: true},
+		{enabled: true, acceptEncoding: "*", wantGzip: true},
+	}
+	for _, tt := range tests {
+		h.SetCompression(tt.enabled)
+
+		r := httptest.NewRequest(http.MethodGet, "/author/1", nil)
+		if tt.acceptEncoding != "" {
+			r.Header.Set("Accept-Encoding", tt.acceptEncoding)
+		}
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, r)
+		require.Equal(t, http.StatusOK, w.Code, tt.acceptEncoding)
+
+		assert.Contains(t, strings.Join(w.Header().Values("Vary"), ","), "Accept-Encoding", tt.acceptEncoding)
+		assert.Equal(t, 1, strings.Count(strings.Join(w.Header().Values("Vary"), ","), "Accept-Encoding"), tt.acceptEncoding)
+
+		body := w.Body.Bytes()
+		if tt.wantGzip {
+			assert.Equal(t, "gzip", w.Header().Get("Content-Encoding"), tt.acceptEncoding)
+			zr, err := gzip.NewReader(w.Body)
+			require.NoError(t, err)
+			body, err = io.ReadAll(zr)
+			require.NoError(t, err)
+		} else {
+			assert.Empty(t, w.Header().Get("Content-Encoding"), tt.acceptEncoding)
+		}
+
+		var author AuthorResource
+		require.NoError(t, json.Unmarshal(body, &author), tt.acceptEncoding)
+		assert.Equal(t, int64(1), author.ForeignID)
+	}
+
+	// Small responses aren't worth compressing.
+	h.SetCompression(true)
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/author/changed", nil)
+	r.Header.Set("Accept-Encoding", "gzip")
+	mux.ServeHTTP(w, r)
+	assert.Empty(t, w.Header().Get("Content-Encoding"))
+}
@@ -1,15 +1,27 @@
 package internal
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net/http"
+	"strconv"
+	"time"
 )
 
 var (
 	errNotFound   = statusErr(http.StatusNotFound)
 	errBadRequest = statusErr(http.StatusBadRequest)
 
+	// errMalformed is returned when upstream responds successfully but with a
+	// body we can't make sense of. It's transient and shouldn't be cached.
+	errMalformed = statusErr(http.StatusBadGateway)
+
+	// errInvalid accompanies errors for upstream data we received but
+	// rejected, like an edition without an author. The status it's joined with
+	// still decides how it's served; this only tells it apart for metrics.
+	errInvalid = errors.New("invalid upstream data")
+
 	errMissingIDs = errors.Join(fmt.Errorf(`missing "ids"`), errBadRequest)
 )
 
@@ -24,3 +36,55 @@ func (s statusErr) Status() int {
 func (s statusErr) Error() string {
 	return fmt.Sprintf("HTTP %d: %s", s, http.StatusText(int(s)))
 }
+
+// errStatus returns the HTTP status for an error, defaulting to 500.
+func errStatus(err error) int {
+	var s statusErr
+	if errors.As(err, &s) {
+		return s.Status()
+	}
+	return http.StatusInternalServerError
+}
+
+// retryAfterErr accompanies a statusErr when upstream told us how long to wait
+// before trying again.
+type retryAfterErr time.Duration
+
+func (r retryAfterErr) Error() string {
+	return fmt.Sprintf("retry after %s", time.Duration(r))
+}
+
+// retryAfter returns how long upstream asked us to wait, or zero if it didn't.
+func retryAfter(err error) time.Duration {
+	var r retryAfterErr
+	if errors.As(err, &r) {
+		return time.Duration(r)
+	}
+	return 0
+}
+
+// parseRetryAfter parses a Retry-After header, which is either a number of
+// seconds or an HTTP date. Zero is returned if the header is missing or
+// invalid.
+func parseRetryAfter(header string) time.Duration {
+	if header == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(header); err == nil {
+		return max(time.Duration(seconds)*time.Second, 0)
+	}
+	if t, err := http.ParseTime(header); err == nil {
+		return max(time.Until(t), 0)
+	}
+	return 0
+}
+
+// transient returns true if the error might resolve itself, like upstream
+// throttling or timeouts, as opposed to a missing resource.
+func transient(err error) bool {
+	var serr statusErr
+	if errors.As(err, &serr) {
+		return serr.Status() == http.StatusTooManyRequests || serr.Status() >= 500
+	}
+	return !errors.Is(err, context.Canceled)
+}
@@ -1,9 +1,12 @@
 package internal
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"net/http"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -17,3 +20,23 @@ func TestError(t *testing.T) {
 
 	assert.ErrorContains(t, err, "invalid request")
 }
+
+func TestRetryAfter(t *testing.T) {
+	assert.Equal(t, 120*time.Second, parseRetryAfter("120"))
+	assert.Equal(t, time.Duration(0), parseRetryAfter(""))
+	assert.Equal(t, time.Duration(0), parseRetryAfter("soon"))
+	assert.Equal(t, time.Duration(0), parseRetryAfter(time.Now().Add(-time.Hour).UTC().Format(http.TimeFormat)))
+	assert.InDelta(t, time.Hour, parseRetryAfter(time.Now().Add(time.Hour).UTC().Format(http.TimeFormat)), float64(2*time.Second))
+
+	err := errors.Join(statusErr(http.StatusTooManyRequests), retryAfterErr(time.Minute))
+	assert.Equal(t, time.Minute, retryAfter(err))
+	assert.Equal(t, time.Duration(0), retryAfter(errNotFound))
+}
+
+func TestTransient(t *testing.T) {
+	assert.True(t, transient(statusErr(http.StatusTooManyRequests)))
+	assert.True(t, transient(errMalformed))
+	assert.True(t, transient(context.DeadlineExceeded))
+	assert.False(t, transient(context.Canceled))
+	assert.False(t, transient(errors.Join(fmt.Errorf("gone"), errNotFound)))
+}
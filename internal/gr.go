@@ -1,15 +1,17 @@
 package internal
 
 import (
+	"cmp"
 	"context"
 	"encoding/hex"
 	"encoding/json"
 	"encoding/xml"
 	"errors"
 	"fmt"
+	"io"
 	"iter"
-	"maps"
 	"net/http"
+	"regexp"
 	"slices"
 	"strings"
 	"time"
@@ -27,6 +29,8 @@ var _stripTags = bluemonday.StrictPolicy()
 
 // GRGetter fetches information from a GR upstream.
 type GRGetter struct {
+	getterOptions
+
 	cache    cache[[]byte]
 	gql      graphql.Client
 	upstream *http.Client
@@ -34,13 +38,18 @@ type GRGetter struct {
 
 var _ getter = (*GRGetter)(nil)
 
+// _sourceGR identifies resources produced by GRGetter.
+const _sourceGR = "gr"
+
 // _grkey key has been public for years.
 // https://github.com/search?q=whFzJP3Ud0gZsAdyXxSr7T&type=code
 var _grkey = "T7rSxXydAsZg0dU3PJzFhw"
 
 // NewGRGetter creates a new Getter backed by G——R——.
-func NewGRGetter(cache cache[[]byte], gql graphql.Client, upstream *http.Client) (*GRGetter, error) {
+func NewGRGetter(cache cache[[]byte], gql graphql.Client, upstream *http.Client, opts ...GetterOption) (*GRGetter, error) {
 	return &GRGetter{
+		getterOptions: newGetterOptions(opts...),
+
 		cache:    cache,
 		gql:      gql,
 		upstream: upstream,
@@ -51,7 +60,7 @@ func NewGRGetter(cache cache[[]byte], gql graphql.Client, upstream *http.Client)
 // [http.Client] must be non-nil and is used for issuing requests. If a
 // non-empty cookie is given the requests are authorized and use are allowed
 // more RPS.
-func NewGRGQL(_ context.Context, rate time.Duration, batchSize int, reg *prometheus.Registry) (graphql.Client, error) {
+func NewGRGQL(ctx context.Context, rate time.Duration, batchSize int, reg *prometheus.Registry, opts ...GQLOption) (graphql.Client, error) {
 	// These credentials are public and easily obtainable. They are obscured here only to hide them from search results.
 	defaultToken, err := hex.DecodeString("6461322d787067736479646b627265676a68707236656a7a716468757779")
 	if err != nil {
@@ -69,12 +78,19 @@ func NewGRGQL(_ context.Context, rate time.Duration, batchSize int, reg *prometh
 			RoundTripper: http.DefaultTransport,
 		},
 	}
-	return NewBatchedGraphQLClient(string(host), &http.Client{Transport: auth}, rate, batchSize, reg)
+	return NewBatchedGraphQLClient(ctx, string(host), &http.Client{Transport: auth}, rate, batchSize, reg, opts...)
+}
+
+// servesHost is true for G——R—— URLs.
+func (g *GRGetter) servesHost(host string) bool {
+	return host == "goodreads.com" || strings.HasSuffix(host, ".goodreads.com")
 }
 
 // Search hits the auto_complete API that has been used historically, so it
 // returns exactly the same results as legacy.
-func (g *GRGetter) Search(ctx context.Context, query string) ([]SearchResource, error) {
+func (g *GRGetter) Search(ctx context.Context, query string) (_ []SearchResource, err error) {
+	defer func() { g.metrics.resultInc("Search", err) }()
+
 	isAsin := _asin.Match([]byte(query))
 	isbn, _ := isbn.Parse(query)
 	if isAsin || isbn != nil {
@@ -100,6 +116,9 @@ func (g *GRGetter) Search(ctx context.Context, query string) ([]SearchResource,
 			Author: SearchResourceAuthor{
 				ID: edge.Node.Work.BestBook.PrimaryContributorEdge.Node.LegacyId,
 			},
+			title:       edge.Node.TitlePrimary,
+			ratingCount: edge.Node.Stats.RatingsCount,
+			releaseDate: releaseDate(edge.Node.Details.PublicationTime),
 		})
 	}
 	return result, nil
@@ -166,10 +185,11 @@ func (g *GRGetter) autoComplete(ctx context.Context, query string) ([]SearchReso
 
 // GetWork returns a work with all known editions. Due to the way R—— works, if
 // an edition is missing here (like a translated edition) it's not fetchable.
-func (g *GRGetter) GetWork(ctx context.Context, workID int64, saveEditions editionsCallback) (_ []byte, authorID int64, _ error) {
-	if workID == 146797269 {
-		// This work always 500s for some reason. Ignore it.
-		return nil, 0, errNotFound
+func (g *GRGetter) GetWork(ctx context.Context, workID int64, saveEditions editionsCallback) (_ []byte, authorID int64, err error) {
+	defer func() { g.metrics.resultInc("GetWork", err) }()
+
+	if err := poisoned(g.poisonWorks, workID); err != nil {
+		return nil, 0, err
 	}
 	workBytes, ttl, ok := g.cache.GetWithTTL(ctx, WorkKey(workID))
 	if ok && ttl > 0 {
@@ -208,7 +228,7 @@ func (g *GRGetter) GetWork(ctx context.Context, workID int64, saveEditions editi
 		} `xml:"best_book"`
 	}
 
-	err = xml.NewDecoder(resp.Body).Decode(&r)
+	err = g.decodeXML(ctx, resp.Body, &r, func() bool { return r.BestBook.ID != 0 })
 	if err != nil {
 		return nil, 0, fmt.Errorf("parsing response: %w", err)
 	}
@@ -218,7 +238,12 @@ func (g *GRGetter) GetWork(ctx context.Context, workID int64, saveEditions editi
 }
 
 // GetBook fetches a book (edition) from GR.
-func (g *GRGetter) GetBook(ctx context.Context, bookID int64, saveEditions editionsCallback) (_ []byte, workID, authorID int64, _ error) {
+func (g *GRGetter) GetBook(ctx context.Context, bookID int64, saveEditions editionsCallback) (_ []byte, workID, authorID int64, err error) {
+	defer func() { g.metrics.resultInc("GetBook", err) }()
+
+	if err := poisoned(g.poisonBooks, bookID); err != nil {
+		return nil, 0, 0, err
+	}
 	if workBytes, ttl, ok := g.cache.GetWithTTL(ctx, BookKey(bookID)); ok && ttl > 0 {
 		return workBytes, 0, 0, nil
 	}
@@ -229,12 +254,20 @@ func (g *GRGetter) GetBook(ctx context.Context, bookID int64, saveEditions editi
 	if err != nil {
 		return nil, 0, 0, fmt.Errorf("getting book: %w", err)
 	}
+	g.keepRaw(ctx, g.cache, BookKey(bookID), resp)
 
 	book := resp.GetBookByLegacyId.BookInfo
 	work := resp.GetBookByLegacyId.Work
 
 	workRsc := mapToWorkResource(book, work)
 
+	// Books without a primary contributor can't be attributed to anyone, so
+	// don't let them poison the work cache.
+	if len(workRsc.Authors) == 0 || workRsc.Authors[0].ForeignID == 0 {
+		Log(ctx).Warn("book is missing a primary author", "bookID", bookID)
+		return nil, 0, 0, errors.Join(errNotFound, errInvalid, fmt.Errorf("book %d has no primary author", bookID))
+	}
+
 	out, err := json.Marshal(workRsc)
 	if err != nil {
 		return nil, 0, 0, fmt.Errorf("marshaling work: %w", err)
@@ -250,34 +283,90 @@ func (g *GRGetter) GetBook(ctx context.Context, bookID int64, saveEditions editi
 	// If this is the "best" edition for the work, then also persist the other
 	// (de-duped) editions we have for it.
 	if saveEditions != nil && workRsc.BestBookID == bookID {
-		editions := map[editionDedupe]workResource{}
+		editions := map[editionDedupe]dedupedEdition{}
 		for _, e := range work.Editions.Edges {
+			if g.skipEbooks && isEbook(e.Node.Details.Format) {
+				continue
+			}
 			key := editionDedupe{
 				title:    strings.ToUpper(e.Node.Title),
-				language: iso639_3(e.Node.Details.Language.Name),
-				audio:    e.Node.Details.Format == "Audible Audio",
+				language: normalizeLanguage(e.Node.Details.Language.Name),
+				audio:    g.isAudio(e.Node.Details.Format),
+			}
+			if key.audio && g.audioNarrators {
+				key.narrator = grNarrator(e.Node.BookInfo)
+			}
+			if g.allEditions {
+				key.id = e.Node.LegacyId
 			}
 			edition := e.Node.BookInfo
-			if _, ok := editions[key]; ok {
+			candidate := dedupedEdition{id: edition.LegacyId, popularity: edition.Stats.RatingsCount}
+			if kept, ok := editions[key]; ok && !kept.replacedBy(candidate, key) {
 				continue // Already saw an edition similar to this one.
 			}
-			editions[key] = mapToWorkResource(edition, work) // Don't add any more editions like this one.
+			candidate.work = withNarrator(mapToWorkResource(edition, work), key.narrator)
+			editions[key] = candidate // Don't add any more editions like this one.
 		}
-		saveEditions(slices.Collect(maps.Values(editions))...)
+		saveEditions(dedupedWorks(editions)...)
 	}
 
 	return out, workRsc.ForeignID, workRsc.Authors[0].ForeignID, nil
 }
 
+// grNarrator returns the ID of the edition's first credited narrator, if any.
+func grNarrator(book gr.BookInfo) int64 {
+	for _, e := range book.SecondaryContributorEdges {
+		if isNarrator(e.Role) {
+			return e.Node.LegacyId
+		}
+	}
+	return 0
+}
+
+// _grParenthetical matches non-nested parentheticals.
+var _grParenthetical = regexp.MustCompile(`\(([^()]*)\)`)
+
+// _grEditionNote matches parentheticals in GR titles which describe the
+// edition rather than, say, its series.
+var _grEditionNote = regexp.MustCompile(`(?i)\b(edition|illustrated|deluxe|revised|abridged|unabridged|annotated|anniversary|expanded|collector'?s)\b`)
+
+// grEditionInformation extracts edition notes from a GR title. GR doesn't have
+// a dedicated field, but titles commonly include them in parentheses, e.g.
+// "The Hobbit (Illustrated Edition)". Series like "(Discworld, #1)" are
+// ignored.
+func grEditionInformation(title string) string {
+	notes := []string{}
+	for _, m := range _grParenthetical.FindAllStringSubmatch(title, -1) {
+		note := strings.TrimSpace(m[1])
+		if strings.Contains(note, "#") || !_grEditionNote.MatchString(note) {
+			continue
+		}
+		notes = append(notes, note)
+	}
+	return strings.Join(notes, ", ")
+}
+
+// _grImageSize matches the size and crop directives Amazon's image CDN appends
+// before a cover's extension, e.g. "._SX98_" or "._SX318_SY475_".
+var _grImageSize = regexp.MustCompile(`\._[A-Z]{2}[A-Z0-9_,]*_(\.[a-zA-Z]+)$`)
+
+// grImageURL strips size directives from a GR image URL so clients get the
+// original image instead of a thumbnail, if enabled.
+func grImageURL(url string) string {
+	url = strings.TrimSpace(url)
+	if !_fullSizeImages {
+		return url
+	}
+	return _grImageSize.ReplaceAllString(url, "$1")
+}
+
 // mapToWorkResource maps a GR book (edition) to the WorkResource model expected by R.
 func mapToWorkResource(book gr.BookInfo, work gr.GetBookGetBookByLegacyIdBookWork) workResource {
 	genres := []string{}
 	for _, g := range book.BookGenres {
 		genres = append(genres, g.Genre.Name)
 	}
-	if len(genres) == 0 {
-		genres = []string{"none"}
-	}
+	genres = withGenrePlaceholder(genres)
 
 	series := []SeriesResource{}
 	for _, s := range book.BookSeries {
@@ -298,10 +387,7 @@ func mapToWorkResource(book gr.BookInfo, work gr.GetBookGetBookByLegacyIdBookWor
 		})
 	}
 
-	bookDescription := strings.TrimSpace(book.Description)
-	if bookDescription == "" {
-		bookDescription = "N/A" // Must be set?
-	}
+	bookDescription := description(book.Description)
 
 	bookRsc := bookResource{
 		KCA:                book.Id,
@@ -312,16 +398,16 @@ func mapToWorkResource(book gr.BookInfo, work gr.GetBookGetBookByLegacyIdBookWor
 		Title:              book.TitlePrimary,
 		FullTitle:          book.Title,
 		ShortTitle:         book.TitlePrimary,
-		Language:           iso639_3(book.Details.Language.Name),
+		Language:           normalizeLanguage(book.Details.Language.Name),
 		Format:             book.Details.Format,
-		EditionInformation: "",                     // TODO: Is this used anywhere?
+		EditionInformation: withEditionInformation(grEditionInformation(book.Title)),
 		Publisher:          book.Details.Publisher, // TODO: Ignore books without publishers?
-		ImageURL:           book.ImageUrl,
-		IsEbook:            book.Details.Format == "Kindle Edition", // TODO: Flush this out.
+		ImageURL:           grImageURL(book.ImageUrl),
+		IsEbook:            isEbook(book.Details.Format),
 		NumPages:           book.Details.NumPages,
 		RatingCount:        book.Stats.RatingsCount,
 		RatingSum:          book.Stats.RatingsSum,
-		AverageRating:      book.Stats.AverageRating,
+		AverageRating:      averageRating(book.Stats.RatingsCount, book.Stats.AverageRating),
 		URL:                book.WebUrl,
 		// TODO: Omitting release date is a way to essentially force R to hide
 		// the book from the frontend while allowing the user to still add it
@@ -334,10 +420,7 @@ func mapToWorkResource(book gr.BookInfo, work gr.GetBookGetBookByLegacyIdBookWor
 	}
 
 	author := book.PrimaryContributorEdge.Node
-	authorDescription := strings.TrimSpace(author.Description)
-	if authorDescription == "" {
-		authorDescription = "N/A" // Must be set?
-	}
+	authorDescription := description(author.Description)
 
 	// Unlike bookDescription we can't request this with (stripped: true)
 	authorDescription = html.UnescapeString(_stripTags.Sanitize(authorDescription))
@@ -347,9 +430,10 @@ func mapToWorkResource(book gr.BookInfo, work gr.GetBookGetBookByLegacyIdBookWor
 		Name:        author.Name,
 		ForeignID:   author.LegacyId,
 		URL:         author.WebUrl,
-		ImageURL:    author.ProfileImageUrl,
+		ImageURL:    grImageURL(author.ProfileImageUrl),
 		Description: authorDescription,
 		Series:      series,
+		Source:      _sourceGR,
 	}
 
 	workRsc := workResource{
@@ -363,6 +447,9 @@ func mapToWorkResource(book gr.BookInfo, work gr.GetBookGetBookByLegacyIdBookWor
 		Genres:       genres,
 		RelatedWorks: []int{},
 		BestBookID:   work.BestBook.LegacyId,
+		Source:       _sourceGR,
+
+		OriginalTitle: withOriginalTitle(work.Details.OriginalTitle),
 	}
 
 	if work.Details.PublicationTime != 0 {
@@ -377,6 +464,10 @@ func mapToWorkResource(book gr.BookInfo, work gr.GetBookGetBookByLegacyIdBookWor
 		ForeignID: work.BestBook.PrimaryContributorEdge.Node.LegacyId, // This might not match the edition's author, in which case we'll discard the edition.
 		Role:      "Author",
 	}}
+	bookRsc.contributorIDs = []int64{author.LegacyId}
+	for _, e := range book.SecondaryContributorEdges {
+		bookRsc.contributorIDs = append(bookRsc.contributorIDs, e.Node.LegacyId)
+	}
 	authorRsc.Works = []workResource{workRsc}
 	workRsc.Authors = []AuthorResource{authorRsc}
 	workRsc.Books = []bookResource{bookRsc} // TODO: Add best book here as well?
@@ -389,7 +480,13 @@ func mapToWorkResource(book gr.BookInfo, work gr.GetBookGetBookByLegacyIdBookWor
 //
 // On an initial load we return only one work on the author. The controller
 // handles asynchronously fetching all additional works.
-func (g *GRGetter) GetAuthor(ctx context.Context, authorID int64) ([]byte, error) {
+func (g *GRGetter) GetAuthor(ctx context.Context, authorID int64) (_ []byte, err error) {
+	defer func() { g.metrics.resultInc("GetAuthor", err) }()
+
+	if err := poisoned(g.poisonAuthors, authorID); err != nil {
+		return nil, err
+	}
+
 	var authorKCA string
 
 	Log(ctx).Debug("getting author", "authorID", authorID)
@@ -406,27 +503,24 @@ func (g *GRGetter) GetAuthor(ctx context.Context, authorID int64) ([]byte, error
 		}
 	}
 
-	var err error
 	if authorKCA == "" {
 		Log(ctx).Debug("resolving author KCA", "authorID", authorID)
+		var err error
 		authorKCA, err = g.legacyAuthorIDtoKCA(ctx, authorID)
 		if err != nil {
+			Log(ctx).Warn("unable to resolve author KCA", "authorID", authorID, "hit", ok, "err", err)
 			return nil, fmt.Errorf("resolving author: %w", err)
 		}
 	}
 
-	if authorKCA == "" {
-		Log(ctx).Warn("unable to resolve author KCA", "hit", ok)
-		return nil, fmt.Errorf("unable to resolve author %d", authorID)
-	}
-
 	works, err := gr.GetAuthorWorks(ctx, g.gql, gr.GetWorksByContributorInput{
 		Id: authorKCA,
-	}, gr.PaginationInput{Limit: 20})
+	}, gr.PaginationInput{Limit: int64(g.authorProbe)})
 	if err != nil {
 		Log(ctx).Warn("problem getting author works", "err", err, "author", authorID, "authorKCA", authorKCA)
 		return nil, fmt.Errorf("author works: %w", err)
 	}
+	g.keepRaw(ctx, g.cache, AuthorKey(authorID), works)
 
 	if len(works.GetWorksByContributor.Edges) == 0 {
 		Log(ctx).Warn("no works found")
@@ -434,9 +528,8 @@ func (g *GRGetter) GetAuthor(ctx context.Context, authorID int64) ([]byte, error
 		// TODO: Return a 404 here instead?
 	}
 
-	// Load books until we find one with our author.
-	for _, e := range works.GetWorksByContributor.Edges {
-		id := e.Node.BestBook.LegacyId
+	// Load books, most popular first, until we find one with our author.
+	for _, id := range probeOrder(works.GetWorksByContributor.Edges) {
 		workBytes, _, _, err := g.GetBook(ctx, id, nil)
 		if err != nil {
 			Log(ctx).Warn("problem getting initial book for author", "err", err, "bookID", id, "authorID", authorID)
@@ -462,8 +555,51 @@ func (g *GRGetter) GetAuthor(ctx context.Context, authorID int64) ([]byte, error
 	return nil, errNotFound
 }
 
+// probeOrder returns the best book of each of an author's works, ordered by
+// popularity. The first of these actually by the author becomes a cold
+// author's initial work, so it should be one they're known for rather than
+// whatever upstream happened to list first.
+func probeOrder(edges []gr.GetAuthorWorksGetWorksByContributorContributorWorksConnectionEdgesContributorWorksEdge) []int64 {
+	edges = slices.Clone(edges)
+	slices.SortStableFunc(edges, func(a, b gr.GetAuthorWorksGetWorksByContributorContributorWorksConnectionEdgesContributorWorksEdge) int {
+		return cmp.Or(
+			-cmp.Compare(a.Node.Stats.RatingsCount, b.Node.Stats.RatingsCount),
+			-cmp.Compare(a.Node.Stats.AverageRating, b.Node.Stats.AverageRating),
+		)
+	})
+	ids := make([]int64, 0, len(edges))
+	for _, e := range edges {
+		ids = append(ids, e.Node.BestBook.LegacyId)
+	}
+	return ids
+}
+
+// GetAuthorStub returns a minimal author without any works. It only requires
+// resolving the author's KCA, so it's much faster than GetAuthor on a cold
+// cache.
+func (g *GRGetter) GetAuthorStub(ctx context.Context, authorID int64) (_ []byte, err error) {
+	defer func() { g.metrics.resultInc("GetAuthorStub", err) }()
+
+	if err := poisoned(g.poisonAuthors, authorID); err != nil {
+		return nil, err
+	}
+
+	author, err := g.legacyAuthor(ctx, authorID)
+	if err != nil {
+		return nil, fmt.Errorf("resolving author: %w", err)
+	}
+	author.Description = description(author.Description)
+	author.Works = []workResource{}
+	author.Series = []SeriesResource{}
+	author.Source = _sourceGR
+
+	return json.Marshal(author)
+}
+
 // GetSeries returns works belonging to the given series.
-func (g *GRGetter) GetSeries(ctx context.Context, seriesID int64) (*SeriesResource, error) {
+func (g *GRGetter) GetSeries(ctx context.Context, seriesID int64) (_ *SeriesResource, err error) {
+	defer func() { g.metrics.resultInc("GetSeries", err) }()
+
 	if seriesID == 0 {
 		// Not sure why this is happening.
 		return nil, errors.Join(errNotFound, errors.New("series ID was 0"))
@@ -507,7 +643,7 @@ func (g *GRGetter) GetSeries(ctx context.Context, seriesID int64) (*SeriesResour
 			} `xml:"series"`
 		}
 
-		err = xml.NewDecoder(resp.Body).Decode(&r)
+		err = g.decodeXML(ctx, resp.Body, &r, func() bool { return r.Series.ID != 0 })
 		if err != nil {
 			return nil, fmt.Errorf("parsing response: %w", err)
 		}
@@ -548,14 +684,20 @@ func (g *GRGetter) GetAuthorBooks(ctx context.Context, authorID int64) iter.Seq[
 	var author AuthorResource
 	_ = sonic.ConfigStd.Unmarshal(authorBytes, &author)
 
+	return g.authorBooks(ctx, authorID, author.KCA)
+}
+
+// authorBooks pages through the author's works, yielding the best book of
+// each one they authored.
+func (g *GRGetter) authorBooks(ctx context.Context, authorID int64, authorKCA string) iter.Seq[int64] {
 	return func(yield func(int64) bool) {
 		after := ""
 		for {
 			works, err := gr.GetAuthorWorks(ctx, g.gql, gr.GetWorksByContributorInput{
-				Id: author.KCA,
+				Id: authorKCA,
 			}, gr.PaginationInput{Limit: 20, After: after})
 			if err != nil {
-				Log(ctx).Warn("problem getting author works", "err", err, "author", authorID, "authorKCA", author.KCA, "after", after)
+				Log(ctx).Warn("problem getting author works", "err", err, "author", authorID, "authorKCA", authorKCA, "after", after)
 				return
 			}
 
@@ -575,17 +717,36 @@ func (g *GRGetter) GetAuthorBooks(ctx context.Context, authorID int64) iter.Seq[
 			if !works.GetWorksByContributor.PageInfo.HasNextPage {
 				return
 			}
-			after = works.GetWorksByContributor.PageInfo.NextPageToken
+			next := works.GetWorksByContributor.PageInfo.NextPageToken
+			if next == "" || next == after {
+				// Upstream sometimes claims there's another page without
+				// advancing the cursor. Bail instead of fetching the same
+				// page forever.
+				Log(ctx).Warn("stuck author works cursor", "author", authorID, "authorKCA", authorKCA, "after", after)
+				return
+			}
+			after = next
 		}
 	}
 }
 
-// Recommendations returns the trending works on the "explore" page.
-func (g *GRGetter) Recommendations(ctx context.Context, page int64) (RecommentationsResource, error) {
-	if page > 1 || page < 0 {
-		// GR is limitted to 50 Recommendations and doens't paginate. In the future this could be
+// Recommendations returns the trending works on the "explore" page, the
+// popular works tagged with a genre, or works similar to a seed.
+func (g *GRGetter) Recommendations(ctx context.Context, page int64, filter recommendationsFilter) (RecommentationsResource, error) {
+	if err := checkPage(page); err != nil {
+		return RecommentationsResource{}, err
+	}
+	if page > 1 {
+		// GR is limited to 50 recommendations and doesn't paginate, so there's
+		// only ever one page.
 		return RecommentationsResource{WorkIDs: []int64{}}, nil
 	}
+	if filter.seed != nil {
+		return g.similar(ctx, filter.seed)
+	}
+	if filter.genre != "" {
+		return g.tagged(ctx, filter.genre)
+	}
 	recommended, err := gr.GetRecommended(ctx, g.gql)
 	if err != nil {
 		return RecommentationsResource{}, fmt.Errorf("getting recommendations: %w", err)
@@ -614,27 +775,143 @@ func (g *GRGetter) Recommendations(ctx context.Context, page int64) (Recommentat
 	return result, nil
 }
 
-// legacyAuthorIDtoKCA resolves a legacy author ID to the new KCA URI. This is
-// the only place where we still use the deprecated API.
+// _grRecommendationsLimit is how many recommendations we ask GR for.
+const _grRecommendationsLimit = 50
+
+// tagged returns the works GR lists under a genre's tag.
+func (g *GRGetter) tagged(ctx context.Context, genre string) (RecommentationsResource, error) {
+	resp, err := gr.GetTaggedBooks(ctx, g.gql, genre, gr.PaginationInput{Limit: _grRecommendationsLimit})
+	if err != nil {
+		return RecommentationsResource{}, fmt.Errorf("getting tagged books: %w", err)
+	}
+
+	result := RecommentationsResource{WorkIDs: []int64{}}
+	for _, e := range resp.GetTaggedBooks.Edges {
+		if workID := e.Node.Work.LegacyId; workID != 0 {
+			result.WorkIDs = append(result.WorkIDs, workID)
+		}
+	}
+	return result, nil
+}
+
+// similar returns the works GR considers similar to the seed's best edition.
+func (g *GRGetter) similar(ctx context.Context, seed *workResource) (RecommentationsResource, error) {
+	kca := ""
+	for _, b := range seed.Books {
+		if b.ForeignID == seed.BestBookID {
+			kca = b.KCA
+			break
+		}
+	}
+	if kca == "" {
+		Log(ctx).Debug("seed has no KCA", "workID", seed.ForeignID)
+		return RecommentationsResource{WorkIDs: []int64{}}, nil
+	}
+
+	resp, err := gr.GetSimilarBooks(ctx, g.gql, kca, gr.PaginationInput{Limit: _grRecommendationsLimit})
+	if err != nil {
+		return RecommentationsResource{}, fmt.Errorf("getting similar books: %w", err)
+	}
+
+	result := RecommentationsResource{WorkIDs: []int64{}}
+	for _, e := range resp.GetSimilarBooks.Edges {
+		if workID := e.Node.Work.LegacyId; workID != 0 {
+			result.WorkIDs = append(result.WorkIDs, workID)
+		}
+	}
+	return result, nil
+}
+
+// _kcaAttempts and _kcaBackoff control how hard we try to resolve an author's
+// KCA before giving up. The backoff doubles after each attempt.
+var (
+	_kcaAttempts = 3
+	_kcaBackoff  = time.Second
+)
+
+// legacyAuthorIDtoKCA resolves a legacy author ID to the new KCA URI. Results
+// are cached separately from the author, so busting an author doesn't require
+// resolving it again.
 func (g *GRGetter) legacyAuthorIDtoKCA(ctx context.Context, authorID int64) (string, error) {
+	if g.kcaTTL > 0 {
+		if kca, ok := g.cache.Get(ctx, authorKCAKey(authorID)); ok && len(kca) > 0 {
+			return string(kca), nil
+		}
+	}
+	author, err := g.legacyAuthor(ctx, authorID)
+	if err != nil {
+		return "", err
+	}
+	if g.kcaTTL > 0 {
+		g.cache.Set(ctx, authorKCAKey(authorID), []byte(author.KCA), g.kcaTTL)
+	}
+	return author.KCA, nil
+}
+
+// legacyAuthor resolves a legacy author ID to a minimal author, including the
+// new KCA URI. This is the only place where we still use the deprecated API,
+// and it's the most fragile part of loading an author, so transient failures
+// are retried with backoff.
+//
+// A not found error is returned if the author genuinely has no KCA we can
+// find. Other errors are transient and shouldn't be cached.
+func (g *GRGetter) legacyAuthor(ctx context.Context, authorID int64) (AuthorResource, error) {
+	backoff := _kcaBackoff
+
+	var err error
+	for attempt := 1; ; attempt++ {
+		var author AuthorResource
+		author, err = g.fetchLegacyAuthor(ctx, authorID)
+		if err == nil && author.KCA == "" {
+			return AuthorResource{}, errors.Join(errNotFound, fmt.Errorf("no KCA found for author %d", authorID))
+		}
+		if err == nil {
+			return author, nil
+		}
+
+		var serr statusErr
+		if errors.As(err, &serr) && serr.Status() < 500 && serr.Status() != http.StatusTooManyRequests {
+			return AuthorResource{}, err // Not worth retrying.
+		}
+		if attempt >= _kcaAttempts {
+			break
+		}
+
+		Log(ctx).Debug("retrying author KCA", "authorID", authorID, "attempt", attempt, "err", err)
+		select {
+		case <-ctx.Done():
+			return AuthorResource{}, errors.Join(err, ctx.Err())
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+
+	return AuthorResource{}, fmt.Errorf("giving up after %d attempts: %w", _kcaAttempts, err)
+}
+
+// fetchLegacyAuthor makes a single attempt at resolving the author's KCA,
+// name and image. An empty KCA is returned if the author has none.
+func (g *GRGetter) fetchLegacyAuthor(ctx context.Context, authorID int64) (AuthorResource, error) {
 	url := fmt.Sprintf("/author/show/%d?key=%s", authorID, _grkey)
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		Log(ctx).Debug("problem creating request", "err", err)
-		return "", err
+		return AuthorResource{}, err
 	}
 
 	resp, err := g.upstream.Do(req)
 	if err != nil {
-		return "", fmt.Errorf("doing upstream: %w", err)
+		return AuthorResource{}, fmt.Errorf("doing upstream: %w", err)
 	}
 	Log(ctx).Debug("legacyAuthorIDtoKCA upstream success")
 	defer func() { _ = resp.Body.Close() }()
 
 	var r struct {
 		Author struct {
-			Name  string `xml:"name"`
-			Books []struct {
+			Name     string `xml:"name"`
+			ImageURL string `xml:"image_url"`
+			Link     string `xml:"link"`
+			Books    []struct {
 				Book struct {
 					Authors []struct {
 						Author struct {
@@ -647,9 +924,9 @@ func (g *GRGetter) legacyAuthorIDtoKCA(ctx context.Context, authorID int64) (str
 		} `xml:"author"`
 	}
 
-	err = xml.NewDecoder(resp.Body).Decode(&r)
+	err = g.decodeXML(ctx, resp.Body, &r, func() bool { return r.Author.Name != "" })
 	if err != nil {
-		return "", fmt.Errorf("parsing response: %w", err)
+		return AuthorResource{}, fmt.Errorf("parsing response: %w", err)
 	}
 
 	var kca string
@@ -670,7 +947,30 @@ func (g *GRGetter) legacyAuthorIDtoKCA(ctx context.Context, authorID int64) (str
 		"authorKCA", kca,
 	)
 
-	return kca, nil
+	return AuthorResource{
+		ForeignID: authorID,
+		KCA:       kca,
+		Name:      strings.TrimSpace(r.Author.Name),
+		ImageURL:  grImageURL(r.Author.ImageURL),
+		URL:       strings.TrimSpace(r.Author.Link),
+	}, nil
+}
+
+// decodeXML decodes an upstream XML response into v. Upstream occasionally
+// serves HTML error pages with a 200, which either fail to decode or decode
+// into an empty struct, so ok must report whether v was actually populated.
+// Either failure is returned as a transient errMalformed.
+func (g *GRGetter) decodeXML(ctx context.Context, body io.Reader, v any, ok func() bool) error {
+	err := xml.NewDecoder(body).Decode(v)
+	if err == nil && ok() {
+		return nil
+	}
+	g.metrics.malformedInc()
+	Log(ctx).Warn("malformed upstream response", "err", err)
+	if err != nil {
+		return errors.Join(errMalformed, err)
+	}
+	return errors.Join(errMalformed, errors.New("empty response"))
 }
 
 // releaseDate parses a G— float into a formatted time R— can work with.
@@ -687,6 +987,10 @@ func releaseDate(t float64) string {
 		return ""
 	}
 
+	if tooFarInFuture(ts) {
+		return ""
+	}
+
 	return ts.Format(time.DateTime)
 }
 
@@ -697,4 +1001,45 @@ type editionDedupe struct {
 	title    string
 	language string
 	audio    bool
+	narrator int64 // Only set for audiobooks when keeping one per narrator.
+	id       int64 // Only set when keeping every edition.
+}
+
+// dedupedEdition is the edition kept for an editionDedupe.
+type dedupedEdition struct {
+	id         int64
+	popularity int64
+	work       workResource
+}
+
+// replacedBy returns true if the candidate should be kept instead. Most
+// editions keep the first one seen, but an audiobook keeps the most popular
+// (then lowest ID) edition so the one which survives doesn't depend on the
+// upstream's ordering.
+func (kept dedupedEdition) replacedBy(candidate dedupedEdition, key editionDedupe) bool {
+	if !key.audio {
+		return false
+	}
+	if candidate.popularity != kept.popularity {
+		return candidate.popularity > kept.popularity
+	}
+	return candidate.id < kept.id
+}
+
+// withNarrator credits the narrator on a mapped edition, if there is one.
+func withNarrator(work workResource, narratorID int64) workResource {
+	if narratorID == 0 || len(work.Books) != 1 {
+		return work
+	}
+	work.Books[0].Contributors = append(work.Books[0].Contributors, contributorResource{ForeignID: narratorID, Role: "Narrator"})
+	return work
+}
+
+// dedupedWorks returns the editions which were kept.
+func dedupedWorks(editions map[editionDedupe]dedupedEdition) []workResource {
+	works := make([]workResource, 0, len(editions))
+	for _, e := range editions {
+		works = append(works, e.work)
+	}
+	return works
 }
@@ -7,6 +7,7 @@ import (
 	"io"
 	"net/http"
 	"os"
+	"slices"
 	"strings"
 	"sync"
 	"testing"
@@ -446,7 +447,7 @@ func TestGRGetBookDataIntegrity(t *testing.T) {
 	ctrl, err := NewController(cache, getter, nil, nil)
 	require.NoError(t, err)
 
-	go ctrl.Run(t.Context())
+	runController(t, ctrl)
 	t.Cleanup(func() { ctrl.Shutdown(t.Context()) })
 
 	t.Run("GetBook", func(t *testing.T) {
@@ -554,6 +555,16 @@ func TestReleaseDate(t *testing.T) {
 			assert.Equal(t, tt.want, got)
 		})
 	}
+
+	t.Run("max future years", func(t *testing.T) {
+		SetMaxFutureYears(5)
+		t.Cleanup(func() { SetMaxFutureYears(0) })
+
+		y2024 := float64(time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC).UnixMilli())
+		y2087 := float64(time.Date(2087, 6, 1, 0, 0, 0, 0, time.UTC).UnixMilli())
+		assert.Equal(t, "2024-06-01 00:00:00", releaseDate(y2024))
+		assert.Equal(t, "", releaseDate(y2087))
+	})
 }
 
 func TestBatchError(t *testing.T) {
@@ -612,7 +623,7 @@ func TestGRIntegration(t *testing.T) {
 	getter, err := NewGRGetter(cache, gql, upstream)
 	require.NoError(t, err)
 	ctrl, err := NewController(cache, getter, nil, nil)
-	go ctrl.Run(t.Context())
+	runController(t, ctrl)
 
 	require.NoError(t, err)
 
@@ -722,8 +733,371 @@ func TestGRIntegration(t *testing.T) {
 
 	t.Run("Recommended", func(t *testing.T) {
 		t.Parallel()
-		recommended, err := getter.Recommendations(t.Context(), 0)
+		recommended, err := getter.Recommendations(t.Context(), 1, recommendationsFilter{})
 		require.NoError(t, err)
 		assert.NotEmpty(t, recommended.WorkIDs)
 	})
 }
+
+func TestLegacyAuthorIDtoKCA(t *testing.T) {
+	backoff := _kcaBackoff
+	_kcaBackoff = time.Millisecond
+	t.Cleanup(func() { _kcaBackoff = backoff })
+
+	respond := func(body string) *http.Response {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{},
+			Body:       io.NopCloser(strings.NewReader(body)),
+		}
+	}
+	found := `<GoodreadsResponse><author><name>foo</name><books><book><authors><author>
+		<name>foo</name><uri>kca://author/amzn1.gr.author.v1.abc</uri>
+	</author></authors></book></books></author></GoodreadsResponse>`
+	missing := `<GoodreadsResponse><author><name>foo</name></author></GoodreadsResponse>`
+
+	t.Run("transient failures are retried", func(t *testing.T) {
+		upstream := hardcover.NewMocktransport(gomock.NewController(t))
+		gomock.InOrder(
+			upstream.EXPECT().RoundTrip(gomock.Any()).Return(nil, statusErr(http.StatusServiceUnavailable)),
+			upstream.EXPECT().RoundTrip(gomock.Any()).Return(respond(found), nil),
+		)
+		getter, err := NewGRGetter(newMemoryCache(), nil, &http.Client{Transport: upstream})
+		require.NoError(t, err)
+
+		kca, err := getter.legacyAuthorIDtoKCA(t.Context(), 1)
+		require.NoError(t, err)
+		assert.Equal(t, "kca://author/amzn1.gr.author.v1.abc", kca)
+	})
+
+	t.Run("persistent failures give up", func(t *testing.T) {
+		upstream := hardcover.NewMocktransport(gomock.NewController(t))
+		upstream.EXPECT().RoundTrip(gomock.Any()).Return(nil, statusErr(http.StatusBadGateway)).Times(_kcaAttempts)
+		getter, err := NewGRGetter(newMemoryCache(), nil, &http.Client{Transport: upstream})
+		require.NoError(t, err)
+
+		_, err = getter.legacyAuthorIDtoKCA(t.Context(), 1)
+		assert.Error(t, err)
+		assert.NotErrorIs(t, err, errNotFound)
+	})
+
+	t.Run("not found isn't retried", func(t *testing.T) {
+		upstream := hardcover.NewMocktransport(gomock.NewController(t))
+		upstream.EXPECT().RoundTrip(gomock.Any()).Return(nil, errNotFound)
+		getter, err := NewGRGetter(newMemoryCache(), nil, &http.Client{Transport: upstream})
+		require.NoError(t, err)
+
+		_, err = getter.legacyAuthorIDtoKCA(t.Context(), 1)
+		assert.ErrorIs(t, err, errNotFound)
+	})
+
+	t.Run("malformed responses are retried", func(t *testing.T) {
+		upstream := hardcover.NewMocktransport(gomock.NewController(t))
+		gomock.InOrder(
+			upstream.EXPECT().RoundTrip(gomock.Any()).Return(respond(`<html><body>Oops</body></html>`), nil),
+			upstream.EXPECT().RoundTrip(gomock.Any()).Return(respond(found), nil),
+		)
+		getter, err := NewGRGetter(newMemoryCache(), nil, &http.Client{Transport: upstream})
+		require.NoError(t, err)
+
+		kca, err := getter.legacyAuthorIDtoKCA(t.Context(), 1)
+		require.NoError(t, err)
+		assert.Equal(t, "kca://author/amzn1.gr.author.v1.abc", kca)
+		assert.Equal(t, int64(1), getter.metrics.malformedGet())
+	})
+
+	t.Run("no matching author is not found", func(t *testing.T) {
+		upstream := hardcover.NewMocktransport(gomock.NewController(t))
+		upstream.EXPECT().RoundTrip(gomock.Any()).Return(respond(missing), nil)
+		getter, err := NewGRGetter(newMemoryCache(), nil, &http.Client{Transport: upstream})
+		require.NoError(t, err)
+
+		_, err = getter.legacyAuthorIDtoKCA(t.Context(), 1)
+		assert.ErrorIs(t, err, errNotFound)
+	})
+
+	t.Run("resolved KCAs are cached", func(t *testing.T) {
+		upstream := hardcover.NewMocktransport(gomock.NewController(t))
+		upstream.EXPECT().RoundTrip(gomock.Any()).Return(respond(found), nil) // Only once.
+		cache := newMemoryCache()
+		getter, err := NewGRGetter(cache, nil, &http.Client{Transport: upstream})
+		require.NoError(t, err)
+
+		for range 2 {
+			kca, err := getter.legacyAuthorIDtoKCA(t.Context(), 1)
+			require.NoError(t, err)
+			assert.Equal(t, "kca://author/amzn1.gr.author.v1.abc", kca)
+		}
+
+		// Busting the author leaves the KCA alone.
+		require.NoError(t, cache.Delete(t.Context(), AuthorKey(1)))
+		kca, err := getter.legacyAuthorIDtoKCA(t.Context(), 1)
+		require.NoError(t, err)
+		assert.Equal(t, "kca://author/amzn1.gr.author.v1.abc", kca)
+	})
+
+	t.Run("caching can be disabled", func(t *testing.T) {
+		upstream := hardcover.NewMocktransport(gomock.NewController(t))
+		upstream.EXPECT().RoundTrip(gomock.Any()).DoAndReturn(func(*http.Request) (*http.Response, error) {
+			return respond(found), nil
+		}).Times(2)
+		getter, err := NewGRGetter(newMemoryCache(), nil, &http.Client{Transport: upstream}, WithAuthorKCATTL(0))
+		require.NoError(t, err)
+
+		for range 2 {
+			_, err := getter.legacyAuthorIDtoKCA(t.Context(), 1)
+			require.NoError(t, err)
+		}
+	})
+}
+
+func TestEmptyGenres(t *testing.T) {
+	work := mapToWorkResource(gr.BookInfo{}, gr.GetBookGetBookByLegacyIdBookWork{})
+	assert.Equal(t, []string{"none"}, work.Genres)
+
+	SetGenrePlaceholder("")
+	t.Cleanup(func() { SetGenrePlaceholder("none") })
+
+	work = mapToWorkResource(gr.BookInfo{}, gr.GetBookGetBookByLegacyIdBookWork{})
+	assert.Equal(t, []string{}, work.Genres)
+
+	// Empty genres serialize as an empty list, not null.
+	out, err := json.Marshal(work)
+	require.NoError(t, err)
+	assert.Contains(t, string(out), `"Genres":[]`)
+	assert.NotContains(t, string(out), `"Genres":null`)
+}
+
+func TestGRGetSeriesMalformed(t *testing.T) {
+	for _, body := range []string{"<!DOCTYPE html><html>", "<html><body>Oops</body></html>"} {
+		upstream := hardcover.NewMocktransport(gomock.NewController(t))
+		upstream.EXPECT().RoundTrip(gomock.Any()).Return(&http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{},
+			Body:       io.NopCloser(strings.NewReader(body)),
+		}, nil)
+		getter, err := NewGRGetter(newMemoryCache(), nil, &http.Client{Transport: upstream})
+		require.NoError(t, err)
+
+		_, err = getter.GetSeries(t.Context(), 1)
+		assert.ErrorIs(t, err, errMalformed, body)
+		assert.Equal(t, int64(1), getter.metrics.malformedGet())
+	}
+}
+
+func TestEmptyDescription(t *testing.T) {
+	work := mapToWorkResource(gr.BookInfo{Description: "  "}, gr.GetBookGetBookByLegacyIdBookWork{})
+	require.Len(t, work.Books, 1)
+	require.Len(t, work.Authors, 1)
+	assert.Equal(t, "N/A", work.Books[0].Description)
+	assert.Equal(t, "N/A", work.Authors[0].Description)
+
+	SetDescriptionPlaceholder("")
+	t.Cleanup(func() { SetDescriptionPlaceholder("N/A") })
+
+	work = mapToWorkResource(gr.BookInfo{}, gr.GetBookGetBookByLegacyIdBookWork{})
+	assert.Equal(t, "", work.Books[0].Description)
+	assert.Equal(t, "", work.Authors[0].Description)
+}
+
+func TestGREditionInformation(t *testing.T) {
+	tests := map[string]string{
+		"The Hobbit":                                   "",
+		"The Hobbit (Illustrated Edition)":             "Illustrated Edition",
+		"Guards! Guards! (Discworld, #8)":              "",
+		"Dune (Dune, #1) (Deluxe Edition)":             "Deluxe Edition",
+		"It (Unabridged) (25th Anniversary Edition)":   "Unabridged, 25th Anniversary Edition",
+		"Leviathan (or, The Matter of a Commonwealth)": "",
+	}
+	for title, want := range tests {
+		assert.Equal(t, want, grEditionInformation(title), title)
+	}
+
+	book := gr.BookInfo{Title: "The Hobbit (Illustrated Edition)"}
+	work := mapToWorkResource(book, gr.GetBookGetBookByLegacyIdBookWork{})
+	assert.Equal(t, "Illustrated Edition", work.Books[0].EditionInformation)
+
+	SetEditionInformation(false)
+	t.Cleanup(func() { SetEditionInformation(true) })
+
+	work = mapToWorkResource(book, gr.GetBookGetBookByLegacyIdBookWork{})
+	assert.Equal(t, "", work.Books[0].EditionInformation)
+}
+
+func TestGROriginalTitle(t *testing.T) {
+	work := gr.GetBookGetBookByLegacyIdBookWork{}
+	work.BestBook.TitlePrimary = "The Swarm"
+	work.Details.OriginalTitle = " Der Schwarm "
+
+	// Disabled by default.
+	workRsc := mapToWorkResource(gr.BookInfo{}, work)
+	assert.Equal(t, "", workRsc.OriginalTitle)
+
+	SetOriginalTitles(true)
+	t.Cleanup(func() { SetOriginalTitles(false) })
+
+	workRsc = mapToWorkResource(gr.BookInfo{}, work)
+	assert.Equal(t, "Der Schwarm", workRsc.OriginalTitle)
+}
+
+func TestGRImageURL(t *testing.T) {
+	tests := map[string]string{
+		"https://i.gr-assets.com/images/S/compressed.photo.goodreads.com/books/1347602096l/6609765._SX98_.jpg":  "https://i.gr-assets.com/images/S/compressed.photo.goodreads.com/books/1347602096l/6609765.jpg",
+		"https://images-na.ssl-images-amazon.com/images/S/compressed.photo.goodreads.com/books/1/2._UX200_.png": "https://images-na.ssl-images-amazon.com/images/S/compressed.photo.goodreads.com/books/1/2.png",
+		"https://m.media-amazon.com/images/S/compressed.photo.goodreads.com/books/1/2._SX318_SY475_.jpg":        "https://m.media-amazon.com/images/S/compressed.photo.goodreads.com/books/1/2.jpg",
+		"https://i.gr-assets.com/images/S/compressed.photo.goodreads.com/books/1/2.jpg":                         "https://i.gr-assets.com/images/S/compressed.photo.goodreads.com/books/1/2.jpg",
+		"https://s.gr-assets.com/assets/nophoto/book/111x148-bcc042a9c91a29c1d680899eff700a03.png":              "https://s.gr-assets.com/assets/nophoto/book/111x148-bcc042a9c91a29c1d680899eff700a03.png",
+		"": "",
+	}
+	for given, want := range tests {
+		assert.Equal(t, want, grImageURL(given), given)
+	}
+
+	book := gr.BookInfo{ImageUrl: "https://i.gr-assets.com/books/1/2._SY475_.jpg"}
+	book.PrimaryContributorEdge.Node.ProfileImageUrl = "https://i.gr-assets.com/authors/1/3._UX150_.jpg"
+	work := mapToWorkResource(book, gr.GetBookGetBookByLegacyIdBookWork{})
+	assert.Equal(t, "https://i.gr-assets.com/books/1/2.jpg", work.Books[0].ImageURL)
+	assert.Equal(t, "https://i.gr-assets.com/authors/1/3.jpg", work.Authors[0].ImageURL)
+
+	// Clients which want thumbnails can keep them.
+	SetFullSizeImages(false)
+	t.Cleanup(func() { SetFullSizeImages(true) })
+
+	work = mapToWorkResource(book, gr.GetBookGetBookByLegacyIdBookWork{})
+	assert.Equal(t, "https://i.gr-assets.com/books/1/2._SY475_.jpg", work.Books[0].ImageURL)
+}
+
+func TestGRPoisonIDs(t *testing.T) {
+	// Poisoned IDs shouldn't hit the upstream at all. The mocks fail on any
+	// unexpected call.
+	upstream := hardcover.NewMocktransport(gomock.NewController(t))
+	gql := hardcover.NewMockgql(gomock.NewController(t))
+	getter, err := NewGRGetter(newMemoryCache(), gql, &http.Client{Transport: upstream},
+		WithPoisonIDs([]int64{1}, []int64{2}, []int64{3}))
+	require.NoError(t, err)
+
+	_, _, err = getter.GetWork(t.Context(), 1, nil)
+	assert.ErrorIs(t, err, errNotFound)
+
+	_, _, _, err = getter.GetBook(t.Context(), 2, nil)
+	assert.ErrorIs(t, err, errNotFound)
+
+	_, err = getter.GetAuthor(t.Context(), 3)
+	assert.ErrorIs(t, err, errNotFound)
+}
+
+func TestGRGetBookMissingAuthor(t *testing.T) {
+	// A book without a primary contributor shouldn't be cached as a work, and
+	// shouldn't panic when we look for its author.
+	gql := hardcover.NewMockgql(gomock.NewController(t))
+	gql.EXPECT().MakeRequest(gomock.Any(),
+		gomock.AssignableToTypeOf(&graphql.Request{}),
+		gomock.AssignableToTypeOf(&graphql.Response{})).DoAndReturn(
+		func(ctx context.Context, req *graphql.Request, res *graphql.Response) error {
+			gbr := res.Data.(*gr.GetBookResponse)
+			gbr.GetBookByLegacyId = gr.GetBookGetBookByLegacyIdBook{
+				BookInfo: gr.BookInfo{LegacyId: 1, Title: "Orphan"},
+				Work: gr.GetBookGetBookByLegacyIdBookWork{
+					LegacyId: 2,
+					BestBook: gr.GetBookGetBookByLegacyIdBookWorkBestBook{LegacyId: 1},
+				},
+			}
+			return nil
+		})
+
+	cache := newMemoryCache()
+	getter, err := NewGRGetter(cache, gql, &http.Client{Transport: hardcover.NewMocktransport(gomock.NewController(t))})
+	require.NoError(t, err)
+
+	_, _, _, err = getter.GetBook(t.Context(), 1, nil)
+	assert.ErrorIs(t, err, errNotFound)
+
+	_, ok := cache.Get(t.Context(), WorkKey(2))
+	assert.False(t, ok)
+}
+
+func TestGRAuthorBooksStuckCursor(t *testing.T) {
+	for _, token := range []string{"", "page2"} {
+		calls := 0
+		gql := hardcover.NewMockgql(gomock.NewController(t))
+		gql.EXPECT().MakeRequest(gomock.Any(),
+			gomock.AssignableToTypeOf(&graphql.Request{}),
+			gomock.AssignableToTypeOf(&graphql.Response{})).DoAndReturn(
+			func(ctx context.Context, req *graphql.Request, res *graphql.Response) error {
+				calls++
+				if calls > 3 {
+					t.Fatal("cursor never terminated")
+				}
+				gaw := res.Data.(*gr.GetAuthorWorksResponse)
+				gaw.GetWorksByContributor.Edges = []gr.GetAuthorWorksGetWorksByContributorContributorWorksConnectionEdgesContributorWorksEdge{{
+					Node: gr.GetAuthorWorksGetWorksByContributorContributorWorksConnectionEdgesContributorWorksEdgeNodeWork{
+						BestBook: gr.GetAuthorWorksGetWorksByContributorContributorWorksConnectionEdgesContributorWorksEdgeNodeWorkBestBook{
+							LegacyId: int64(calls),
+							PrimaryContributorEdge: gr.GetAuthorWorksGetWorksByContributorContributorWorksConnectionEdgesContributorWorksEdgeNodeWorkBestBookPrimaryContributorEdgeBookContributorEdge{
+								Role: "Author",
+								Node: gr.GetAuthorWorksGetWorksByContributorContributorWorksConnectionEdgesContributorWorksEdgeNodeWorkBestBookPrimaryContributorEdgeBookContributorEdgeNodeContributor{
+									LegacyId: 1,
+								},
+							},
+						},
+					},
+				}}
+				// Always claim there's another page, but never advance past
+				// the token.
+				gaw.GetWorksByContributor.PageInfo.HasNextPage = true
+				gaw.GetWorksByContributor.PageInfo.NextPageToken = token
+				return nil
+			}).AnyTimes()
+
+		getter, err := NewGRGetter(newMemoryCache(), gql, &http.Client{Transport: hardcover.NewMocktransport(gomock.NewController(t))})
+		require.NoError(t, err)
+
+		bookIDs := slices.Collect(getter.authorBooks(t.Context(), 1, "kca://author"))
+		if token == "" {
+			assert.Equal(t, []int64{1}, bookIDs)
+		} else {
+			// The first page advances to "page2", the second doesn't.
+			assert.Equal(t, []int64{1, 2}, bookIDs)
+		}
+	}
+}
+
+func TestGRProbeOrder(t *testing.T) {
+	edge := func(bookID, ratings int64, avg float64) gr.GetAuthorWorksGetWorksByContributorContributorWorksConnectionEdgesContributorWorksEdge {
+		e := gr.GetAuthorWorksGetWorksByContributorContributorWorksConnectionEdgesContributorWorksEdge{}
+		e.Node.BestBook.LegacyId = bookID
+		e.Node.Stats.RatingsCount = ratings
+		e.Node.Stats.AverageRating = avg
+		return e
+	}
+	edges := []gr.GetAuthorWorksGetWorksByContributorContributorWorksConnectionEdgesContributorWorksEdge{
+		edge(1, 10, 4.5),
+		edge(2, 5000, 3.9),
+		edge(3, 10, 4.8),
+		edge(4, 0, 0),
+		edge(5, 5000, 3.9), // Ties keep upstream's order.
+	}
+
+	assert.Equal(t, []int64{2, 5, 3, 1, 4}, probeOrder(edges))
+	assert.Equal(t, int64(1), edges[0].Node.BestBook.LegacyId, "input shouldn't be modified")
+}
+
+func TestGRRecommendationsPaging(t *testing.T) {
+	gql := hardcover.NewMockgql(gomock.NewController(t))
+	gql.EXPECT().MakeRequest(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).Times(1)
+
+	getter, err := NewGRGetter(newMemoryCache(), gql, nil)
+	require.NoError(t, err)
+
+	_, err = getter.Recommendations(t.Context(), 0, recommendationsFilter{})
+	assert.ErrorIs(t, err, errBadRequest)
+
+	recs, err := getter.Recommendations(t.Context(), 1, recommendationsFilter{})
+	require.NoError(t, err)
+	assert.NotNil(t, recs.WorkIDs)
+
+	// There's only one page.
+	recs, err = getter.Recommendations(t.Context(), 2, recommendationsFilter{})
+	require.NoError(t, err)
+	assert.Empty(t, recs.WorkIDs)
+}
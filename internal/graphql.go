@@ -21,6 +21,8 @@ import (
 	"github.com/graphql-go/graphql/language/source"
 	"github.com/graphql-go/graphql/language/visitor"
 	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/trace"
 	"golang.org/x/exp/rand"
 )
 
@@ -31,38 +33,90 @@ type batchedgqlclient struct {
 
 	batchSize int            // batchSize is the max number of queries per batch.
 	queue     []batchedQuery // queue contains spillover in cases where we've accumulated more queries than our batch size allows.
-	every     time.Duration  // every controls how often requests are flushed.
+	ticker    *time.Ticker   // ticker controls how often requests are flushed.
 	metrics   *gqlMetrics    // metrics tracks batches and queries sent.
+	tracer    trace.Tracer   // tracer records a span for every query.
+	inflight  chan struct{}  // inflight optionally bounds how many batches can be awaiting a response.
+
+	authThreshold int           // authThreshold is how many consecutive 401/403 batches trigger a backoff. Zero disables it.
+	authBackoff   time.Duration // authBackoff is how long to stop sending batches once authThreshold is reached.
+	authFailures  int           // authFailures counts consecutive batches rejected with a 401/403.
+	backoffUntil  time.Time     // backoffUntil is when batches can be sent again.
+	backoffStatus statusErr     // backoffStatus is the status returned to queries during a backoff.
 
 	wrapped graphql.Client
 }
 
+// GQLOption configures a batched GraphQL client.
+type GQLOption func(*batchedgqlclient)
+
+// WithMaxInFlight bounds how many batches can be awaiting an upstream
+// response at once. Flushes wait for a slot to free up, so queries keep
+// accumulating instead of piling up against the upstream. Zero is unbounded.
+func WithMaxInFlight(n int) GQLOption {
+	return func(c *batchedgqlclient) {
+		if n > 0 {
+			c.inflight = make(chan struct{}, n)
+		}
+	}
+}
+
+// WithAuthBackoff stops sending batches for the given duration once threshold
+// consecutive batches are rejected with a 401 or 403. An invalid API key fails
+// every request, so there's no point hammering the upstream with more of them.
+// Queries made during the backoff fail immediately. A non-positive threshold
+// disables it.
+func WithAuthBackoff(threshold int, backoff time.Duration) GQLOption {
+	return func(c *batchedgqlclient) {
+		c.authThreshold = max(threshold, 0)
+		c.authBackoff = backoff
+	}
+}
+
 // NewBatchedGraphQLClient creates a batching GraphQL client. Queries are
-// accumulated and executed regularly accurding to the given rate.
-func NewBatchedGraphQLClient(url string, client *http.Client, every time.Duration, batchSize int, reg *prometheus.Registry) (graphql.Client, error) {
-	wrapped := graphql.NewClient(url, client)
+// accumulated and executed regularly accurding to the given rate until the
+// context is cancelled.
+func NewBatchedGraphQLClient(ctx context.Context, url string, client *http.Client, every time.Duration, batchSize int, reg *prometheus.Registry, opts ...GQLOption) (graphql.Client, error) {
+	traced := *client
+	traced.Transport = otelhttp.NewTransport(client.Transport)
+	wrapped := graphql.NewClient(url, &traced)
 
 	c := &batchedgqlclient{
 		batchSize: batchSize,
 		wrapped:   wrapped,
 		queue:     []batchedQuery{},
 		metrics:   newGQLMetrics(reg),
-		every:     every,
+		tracer:    newTracer(),
+		ticker:    time.NewTicker(every),
+	}
+	for _, opt := range opts {
+		opt(c)
 	}
 
 	go func() {
-		ctx := context.WithValue(context.Background(), middleware.RequestIDKey, fmt.Sprintf("batch-flush-%d", time.Now().Unix()))
+		ctx := context.WithValue(ctx, middleware.RequestIDKey, fmt.Sprintf("batch-flush-%d", time.Now().Unix()))
+		defer c.ticker.Stop()
 		for {
-			time.Sleep(c.every)
+			select {
+			case <-ctx.Done():
+				return
+			case <-c.ticker.C:
+			}
 			c.flush(ctx)
 		}
 	}()
 
 	// Log gql stats every minute.
 	go func() {
-		ctx := context.Background()
+		ctx := context.WithValue(ctx, middleware.RequestIDKey, "gql-stats")
+		ticker := time.NewTicker(1 * time.Minute)
+		defer ticker.Stop()
 		for {
-			time.Sleep(1 * time.Minute)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
 			batchesWaiting := c.metrics.batchesWaitingGet()
 			batchesSent := c.metrics.batchesSentGet()
 			queriesSent := c.metrics.queriesSentGet()
@@ -84,15 +138,34 @@ func NewBatchedGraphQLClient(url string, client *http.Client, every time.Duratio
 // can fail without the entire batch failing. The whole batch can still fail in
 // other cases, e.g. 4XX response codes.
 func (c *batchedgqlclient) flush(ctx context.Context) {
+	// Wait for a free slot before taking the lock, so queries can still be
+	// enqueued while we're blocked.
+	c.acquire()
+
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
 	c.metrics.batchesWaitingSet(len(c.queue))
 
 	if len(c.queue) == 0 {
+		c.release()
 		return // Nothing to do yet.
 	}
 
+	// Upstream is rejecting our credentials, so don't bother asking.
+	if wait := time.Until(c.backoffUntil); wait > 0 {
+		err := errors.Join(c.backoffStatus, retryAfterErr(wait))
+		for _, batch := range c.queue {
+			c.metrics.queriesRejectedAdd(int64(len(batch.subscribers)))
+			for _, sub := range batch.subscribers {
+				sub.respC <- err
+			}
+		}
+		c.queue = []batchedQuery{}
+		c.release()
+		return
+	}
+
 	// Take our oldest batch off the queue.
 	batch := c.queue[0]
 	c.queue = c.queue[1:]
@@ -102,6 +175,7 @@ func (c *batchedgqlclient) flush(ctx context.Context) {
 
 	query, vars, err := batch.qb.build()
 	if err != nil {
+		c.release()
 		Log(ctx).Error("unable to build query", "err", err)
 		return
 	}
@@ -119,10 +193,13 @@ func (c *batchedgqlclient) flush(ctx context.Context) {
 	// Issue the request in a separate goroutine so we can continue to
 	// accumulate queries without needing to wait for the network call.
 	go func(batch batchedQuery) {
+		defer c.release()
+
 		ctx, cancel := context.WithTimeout(ctx, 60*time.Second)
 		defer cancel()
 
 		err := c.wrapped.MakeRequest(ctx, req, resp)
+		c.checkAuth(ctx, err)
 
 		// Extract any field-level errors, and return them to their
 		// subscribers. We can ignore the top-level err in this case, because
@@ -161,13 +238,92 @@ func (c *batchedgqlclient) flush(ctx context.Context) {
 	}(batch)
 }
 
+// checkAuth tracks consecutive batches rejected with a 401 or 403 and starts
+// a backoff once there are too many of them. Any other outcome resets the
+// count.
+func (c *batchedgqlclient) checkAuth(ctx context.Context, err error) {
+	if c.authThreshold == 0 {
+		return
+	}
+
+	serr := gqlStatus(err)
+	rejected := serr == http.StatusUnauthorized || serr == http.StatusForbidden
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !rejected {
+		c.authFailures = 0
+		return
+	}
+	c.authFailures++
+	if c.authFailures < c.authThreshold {
+		return
+	}
+
+	c.authFailures = 0
+	c.backoffUntil = time.Now().Add(c.authBackoff)
+	c.backoffStatus = serr
+	c.metrics.authBackoffsInc()
+	Log(ctx).Error("upstream is rejecting our credentials, is the API key valid? backing off",
+		"status", serr.Status(), "failures", c.authThreshold, "backoff", c.authBackoff)
+}
+
+// gqlStatus returns the HTTP status of a failed request, or zero if there
+// isn't one. Upstream errors are normally proxied as a statusErr, but the
+// client reports its own HTTPError otherwise.
+func gqlStatus(err error) statusErr {
+	if err == nil {
+		return 0
+	}
+	var serr statusErr
+	if errors.As(gqlStatusErr(err), &serr) {
+		return serr
+	}
+	var httpErr *graphql.HTTPError
+	if errors.As(err, &httpErr) {
+		return statusErr(httpErr.StatusCode)
+	}
+	return 0
+}
+
+// acquire blocks until another batch is allowed to be in flight.
+func (c *batchedgqlclient) acquire() {
+	if c.inflight != nil {
+		c.inflight <- struct{}{}
+	}
+}
+
+// reconfigure changes how often batches are flushed and how many queries
+// they hold. Non-positive values are ignored.
+func (c *batchedgqlclient) reconfigure(every time.Duration, batchSize int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if batchSize > 0 {
+		c.batchSize = batchSize
+	}
+	if every > 0 {
+		c.ticker.Reset(every)
+	}
+}
+
+// release frees up an in-flight slot.
+func (c *batchedgqlclient) release() {
+	if c.inflight != nil {
+		<-c.inflight
+	}
+}
+
 // MakeRequest implements graphql.Client.
 func (c *batchedgqlclient) MakeRequest(
 	ctx context.Context,
 	req *graphql.Request,
 	resp *graphql.Response,
 ) error {
+	ctx, span := startSpan(ctx, c.tracer, "gql."+req.OpName)
 	err := <-c.enqueue(ctx, req, resp).respC
+	endSpan(span, err)
 	return err
 }
 
@@ -42,11 +42,14 @@ func TestQueryBuilderMultipleQueries(t *testing.T) {
 		query, vars, err := qb.build()
 		require.NoError(t, err)
 
-		expected := fmt.Sprintf(`query GetWork($%s_bookID: Int!, $%s_id: Int!, $%s_limit: Int!, $%s_offset: Int!) {
+		expected := fmt.Sprintf(`query GetWork($%s_bookID: Int!, $%s_narrators: Boolean!, $%s_id: Int!, $%s_limit: Int!, $%s_offset: Int!) {
   %s: books_by_pk(id: $%s_bookID) {
     ...WorkInfo
     editions(order_by: {score: desc_nulls_last}) {
       ...EditionInfo
+      contributions @include(if: $%s_narrators) {
+        ...Contributions
+      }
     }
   }
   %s: authors_by_pk(id: $%s_id) {
@@ -82,30 +85,59 @@ fragment DefaultEditions on books {
   }
   default_audio_edition {
     id
+    pages
+    audio_seconds
+    cached_image(path: "url")
+    language {
+      code3
+    }
     contributions {
       ...Contributions
     }
   }
   default_physical_edition {
     id
+    pages
+    audio_seconds
+    cached_image(path: "url")
+    language {
+      code3
+    }
     contributions {
       ...Contributions
     }
   }
   default_cover_edition {
     id
+    pages
+    audio_seconds
+    cached_image(path: "url")
+    language {
+      code3
+    }
     contributions {
       ...Contributions
     }
   }
   default_ebook_edition {
     id
+    pages
+    audio_seconds
+    cached_image(path: "url")
+    language {
+      code3
+    }
     contributions {
       ...Contributions
     }
   }
   fallback: editions(order_by: {id: desc}, limit: 1) {
     id
+    pages
+    audio_seconds
+    contributions {
+      ...Contributions
+    }
   }
 }
 fragment EditionInfo on editions {
@@ -154,12 +186,12 @@ fragment WorkInfo on books {
   rating
   ratings_count
   ...DefaultEditions
-}`, id1, id2, id2, id2, id1, id1, id2, id2, id2, id2)
+}`, id1, id1, id2, id2, id2, id1, id1, id1, id2, id2, id2, id2)
 
 		assert.Equal(t, expected, query, query)
 
-		assert.Len(t, vars, 4)
-		assert.Contains(t, vars, id1+"_bookID", id2+"_id", id2+"_limit", id2+"_offset")
+		assert.Len(t, vars, 5)
+		assert.Contains(t, vars, id1+"_bookID", id1+"_narrators", id2+"_id", id2+"_limit", id2+"_offset")
 	})
 
 	t.Run("gr", func(t *testing.T) {
@@ -192,6 +224,7 @@ fragment WorkInfo on books {
       details {
         webUrl
         publicationTime
+        originalTitle
       }
       bestBook {
         legacyId
@@ -217,6 +250,10 @@ fragment WorkInfo on books {
     edges {
       node {
         id
+        stats {
+          averageRating
+          ratingsCount
+        }
         bestBook {
           legacyId
           primaryContributorEdge {
@@ -277,6 +314,12 @@ fragment BookInfo on Book {
       description
     }
   }
+  secondaryContributorEdges {
+    role
+    node {
+      legacyId
+    }
+  }
   stats {
     averageRating
     ratingsCount
@@ -310,35 +353,35 @@ func TestBatching(t *testing.T) {
 
 	url := "https://api.hardcover.app/v1/graphql"
 
-	gql, err := NewBatchedGraphQLClient(url, client, time.Second, 6, nil)
+	gql, err := NewBatchedGraphQLClient(t.Context(), url, client, time.Second, 6, nil)
 	require.NoError(t, err)
 
 	start := time.Now()
 
 	wg := sync.WaitGroup{}
 	wg.Go(func() {
-		_, err := hardcover.GetWork(context.Background(), gql, 156028352)
+		_, err := hardcover.GetWork(context.Background(), gql, 156028352, false)
 		if err != nil {
 			panic(err)
 		}
 	})
 
 	wg.Go(func() {
-		_, err := hardcover.GetWork(context.Background(), gql, 164005178)
+		_, err := hardcover.GetWork(context.Background(), gql, 164005178, false)
 		if err != nil {
 			panic(err)
 		}
 	})
 
 	wg.Go(func() {
-		_, err := hardcover.GetWork(context.Background(), gql, 340640138)
+		_, err := hardcover.GetWork(context.Background(), gql, 340640138, false)
 		if err != nil {
 			panic(err)
 		}
 	})
 
 	wg.Go(func() {
-		_, err := hardcover.GetWork(context.Background(), gql, -1) // Missing.
+		_, err := hardcover.GetWork(context.Background(), gql, -1, false) // Missing.
 		if err != nil {
 			panic(err)
 		}
@@ -363,7 +406,7 @@ func TestBatchingOverflow(t *testing.T) {
 		}),
 	}
 
-	gql, err := NewBatchedGraphQLClient("https://foo.com", client, 50*time.Millisecond, 1, nil)
+	gql, err := NewBatchedGraphQLClient(t.Context(), "https://foo.com", client, 50*time.Millisecond, 1, nil)
 	require.NoError(t, err)
 
 	wg := sync.WaitGroup{}
@@ -390,6 +433,126 @@ func TestBatchingOverflow(t *testing.T) {
 	assert.Equal(t, int32(2), calls.Load())
 }
 
+func TestBatchingReconfigure(t *testing.T) {
+	// A new flush interval takes effect without waiting out the old one.
+	client := &http.Client{
+		Transport: roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+			return &http.Response{
+				StatusCode: 200,
+				Body:       io.NopCloser(strings.NewReader(`{"data": {}, "errors": []}`)),
+			}, nil
+		}),
+	}
+
+	gql, err := NewBatchedGraphQLClient(t.Context(), "https://foo.com", client, time.Hour, 1, nil)
+	require.NoError(t, err)
+	gql.(*batchedgqlclient).reconfigure(10*time.Millisecond, 0)
+
+	ctx, cancel := context.WithTimeout(t.Context(), 5*time.Second)
+	defer cancel()
+	_, err = gr.GetBook(ctx, gql, 1)
+	assert.NoError(t, err)
+}
+
+func TestBatchingMaxInFlight(t *testing.T) {
+	// Batches wait for a free slot instead of piling up against the upstream.
+	inflight, peak := atomic.Int32{}, atomic.Int32{}
+	started, release := make(chan struct{}), make(chan struct{})
+
+	client := &http.Client{
+		Transport: roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+			n := inflight.Add(1)
+			defer inflight.Add(-1)
+			for {
+				p := peak.Load()
+				if n <= p || peak.CompareAndSwap(p, n) {
+					break
+				}
+			}
+			started <- struct{}{}
+			<-release
+			return &http.Response{
+				StatusCode: 200,
+				Body:       io.NopCloser(strings.NewReader(`{"data": {}, "errors": []}`)),
+			}, nil
+		}),
+	}
+
+	gql, err := NewBatchedGraphQLClient(t.Context(), "https://foo.com", client, time.Millisecond, 1, nil, WithMaxInFlight(2))
+	require.NoError(t, err)
+
+	wg := sync.WaitGroup{}
+	for i := range 10 {
+		wg.Go(func() {
+			_, err := gr.GetBook(t.Context(), gql, int64(i))
+			assert.NoError(t, err)
+		})
+	}
+
+	// Two batches fill every slot. After that another batch only starts once
+	// one of them finishes.
+	<-started
+	<-started
+	for range 8 {
+		release <- struct{}{}
+		<-started
+	}
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(2), peak.Load())
+}
+
+func TestBatchingAuthBackoff(t *testing.T) {
+	// A run of 403s, e.g. from an invalid API key, stops us from sending more
+	// batches for a while. Queries fail fast in the meantime.
+	requests := atomic.Int32{}
+	status := atomic.Int32{}
+	status.Store(http.StatusForbidden)
+
+	client := &http.Client{
+		Transport: errorProxyTransport{roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+			requests.Add(1)
+			return &http.Response{
+				StatusCode: int(status.Load()),
+				Body:       io.NopCloser(strings.NewReader(`{"data": {}, "errors": []}`)),
+			}, nil
+		})},
+	}
+
+	c, err := NewBatchedGraphQLClient(t.Context(), "https://foo.com", client, time.Millisecond, 1, nil, WithAuthBackoff(3, time.Hour))
+	require.NoError(t, err)
+	gql := c.(*batchedgqlclient)
+
+	// A success in between resets the count.
+	for _, code := range []int{http.StatusForbidden, http.StatusForbidden, http.StatusOK, http.StatusUnauthorized, http.StatusForbidden} {
+		status.Store(int32(code))
+		_, _ = gr.GetBook(t.Context(), gql, 1)
+	}
+	assert.Equal(t, int64(0), gql.metrics.authBackoffsGet())
+
+	_, err = gr.GetBook(t.Context(), gql, 1)
+	assert.ErrorIs(t, err, statusErr(http.StatusForbidden))
+	assert.Equal(t, int32(6), requests.Load())
+	assert.Equal(t, int64(1), gql.metrics.authBackoffsGet())
+
+	// Upstream isn't asked again until the backoff expires.
+	for range 3 {
+		_, err = gr.GetBook(t.Context(), gql, 1)
+		assert.ErrorIs(t, err, statusErr(http.StatusForbidden))
+		assert.Greater(t, retryAfter(err), 59*time.Minute)
+	}
+	assert.Equal(t, int32(6), requests.Load())
+
+	gql.mu.Lock()
+	gql.backoffUntil = time.Now()
+	gql.mu.Unlock()
+	status.Store(http.StatusOK)
+	_, err = gr.GetBook(t.Context(), gql, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(7), requests.Load())
+}
+
 type roundTripperFunc func(*http.Request) (*http.Response, error)
 
 func (fn roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) {
@@ -7,7 +7,8 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"net"
+	"io"
+	"maps"
 	"net/http"
 	"net/http/pprof"
 	"net/netip"
@@ -20,13 +21,13 @@ import (
 	"sync"
 	"time"
 
-	"github.com/blampe/isbn"
 	"github.com/bytedance/sonic"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/swaggest/swgui"
 	swagger "github.com/swaggest/swgui/v3cdn"
+	"golang.org/x/sync/errgroup"
 )
 
 // Handler is our HTTP Handler. It handles muxing, response headers, etc. and
@@ -34,6 +35,29 @@ import (
 type Handler struct {
 	ctrl *Controller
 	http *http.Client
+
+	// reload re-applies configuration on POST /admin/reload, if set.
+	reload func(context.Context) error
+
+	// basePath is an optional prefix all routes are served under.
+	basePath string
+
+	// cachePolicies override Cache-Control for individual endpoints.
+	cachePolicies map[string]CachePolicy
+
+	// gzip compresses cached resources for clients which accept it.
+	gzip bool
+
+	// bulkTimeout bounds how long a bulk request waits for its books. Zero
+	// waits indefinitely.
+	bulkTimeout time.Duration
+
+	// adminNetworks are trusted to call admin endpoints, in addition to
+	// loopback.
+	adminNetworks []netip.Prefix
+
+	// debugHeaders honors ?debug=1 on resources.
+	debugHeaders bool
 }
 
 var _asin = regexp.MustCompile(`^B[A-Z0-9]{9}$`)
@@ -43,6 +67,16 @@ var (
 	_recommendedTTL = 24 * time.Hour
 )
 
+var (
+	// _maxRequestBytes limits request bodies. Only bulk requests have
+	// meaningful ones.
+	_maxRequestBytes int64 = 1024
+
+	// _maxBulkRequestBytes limits POST /book/bulk bodies, which can list a
+	// whole library's worth of ISBNs.
+	_maxBulkRequestBytes int64 = 256 << 10
+)
+
 //go:embed swagger.json
 var _spec embed.FS
 
@@ -55,12 +89,106 @@ func NewHandler(ctrl *Controller) *Handler {
 	return h
 }
 
+// SetReloader enables POST /admin/reload, which calls fn to re-apply
+// configuration without restarting.
+func (h *Handler) SetReloader(fn func(context.Context) error) {
+	h.reload = fn
+}
+
+// SetBasePath serves all routes under the given prefix (e.g. "/metadata"),
+// for deployments behind a reverse proxy mounted at a subpath.
+func (h *Handler) SetBasePath(p string) {
+	p = strings.TrimRight(p, "/")
+	if p != "" && !strings.HasPrefix(p, "/") {
+		p = "/" + p
+	}
+	h.basePath = p
+}
+
+// link returns the path to one of our routes, under the base path if there is
+// one. Redirects and self-links must use it, since routes only ever see paths
+// with the base path stripped.
+func (h *Handler) link(route string) string {
+	return path.Join("/", h.basePath, route)
+}
+
+// CachePolicy overrides an endpoint's Cache-Control max-ages. Zero values
+// keep the defaults.
+type CachePolicy struct {
+	// MaxAge is how long clients may cache responses. It's unset by default,
+	// leaving it up to the client.
+	MaxAge time.Duration
+	// SMaxAge is how long CDNs may cache responses. It defaults to the
+	// resource's TTL.
+	SMaxAge time.Duration
+}
+
+// CacheEndpoints are the endpoints whose Cache-Control can be overridden.
+var CacheEndpoints = []string{"search", "bulk", "work", "book", "author", "series", "changed", "recommended"}
+
+// SetCachePolicy overrides Cache-Control for one of CacheEndpoints, e.g. to
+// let clients cache series longer than authors.
+func (h *Handler) SetCachePolicy(endpoint string, p CachePolicy) error {
+	if !slices.Contains(CacheEndpoints, endpoint) {
+		return fmt.Errorf("unknown endpoint %q: expected one of %s", endpoint, strings.Join(CacheEndpoints, ", "))
+	}
+	if h.cachePolicies == nil {
+		h.cachePolicies = map[string]CachePolicy{}
+	}
+	h.cachePolicies[endpoint] = p
+	return nil
+}
+
+// SetCompression gzips cached resources for clients which accept it.
+func (h *Handler) SetCompression(enabled bool) {
+	h.gzip = enabled
+}
+
+// SetBulkTimeout bounds how long a bulk request waits for its books. Books
+// which aren't loaded in time are left out of the response. Zero waits
+// indefinitely.
+func (h *Handler) SetBulkTimeout(d time.Duration) {
+	h.bulkTimeout = max(d, 0)
+}
+
+// SetDebugHeaders lets clients ask for debugging information, like an
+// X-Cache-TTL header and where each resource came from, with ?debug=1. It's
+// off by default since it exposes internals to anyone.
+func (h *Handler) SetDebugHeaders(enabled bool) {
+	h.debugHeaders = enabled
+}
+
+// SetAdminNetworks trusts the given CIDRs (e.g. "10.0.0.0/8") to call admin
+// endpoints like /admin/reload. Only loopback is trusted by default.
+func (h *Handler) SetAdminNetworks(cidrs []string) error {
+	nets := make([]netip.Prefix, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		p, err := netip.ParsePrefix(cidr)
+		if err != nil {
+			return fmt.Errorf("invalid admin network: %w", err)
+		}
+		nets = append(nets, p.Masked())
+	}
+	h.adminNetworks = nets
+	return nil
+}
+
 // NewMux registers a handler's routes on a new mux.
 func NewMux(h *Handler, reg *prometheus.Registry) http.Handler {
+	if h.basePath != "" {
+		// Routes and path parsing are all root-relative, so strip the prefix
+		// before they see anything. Requests outside of it are a 404.
+		return http.StripPrefix(h.basePath, newMux(h, reg))
+	}
+	return newMux(h, reg)
+}
+
+func newMux(h *Handler, reg *prometheus.Registry) http.Handler {
 	mux := http.NewServeMux()
 
 	mux.HandleFunc("/search", h.search)
 	mux.HandleFunc("/recommended", h.recommended)
+	mux.HandleFunc("/resolve", h.resolve)
 
 	mux.HandleFunc("/work/{foreignID}", h.getWorkID)
 	mux.HandleFunc("/book/{foreignEditionID}", h.getBookID)
@@ -70,6 +198,7 @@ func NewMux(h *Handler, reg *prometheus.Registry) http.Handler {
 	mux.HandleFunc("/book/bulk", h.bulkBook)
 	mux.HandleFunc("/author/{foreignAuthorID}", h.getAuthorID)
 	mux.HandleFunc("/author/changed", h.getAuthorChanged)
+	mux.HandleFunc("/author/{foreignAuthorID}/works", h.getAuthorWorks)
 	mux.HandleFunc("/series/{seriesID}", h.getSeriesID)
 
 	mux.HandleFunc("/debug/pprof/", pprof.Index)
@@ -77,16 +206,12 @@ func NewMux(h *Handler, reg *prometheus.Registry) http.Handler {
 	mux.HandleFunc("/debug/pprof/symbol/", pprof.Symbol)
 	mux.HandleFunc("/debug/pprof/trace/", pprof.Trace)
 	mux.Handle("/debug/metrics", promhttp.HandlerFor(reg, promhttp.HandlerOpts{}))
+	mux.HandleFunc("/debug/raw/{key}", h.getRaw)
 
 	mux.HandleFunc("/reconfigure", h.reconfigure)
+	mux.HandleFunc("/admin/reload", h.adminReload)
 
-	mux.Handle("/swagger.json", http.FileServerFS(_spec))
-	mux.Handle("/", swagger.NewHandlerWithConfig(swgui.Config{
-		Title:       "BookInfo Metadata API",
-		SwaggerJSON: "/swagger.json",
-		BasePath:    "/docs/",
-		JsonEditor:  true,
-	}))
+	mux.Handle("/", swaggerUI(h.link("/")))
 
 	throttled := middleware.ThrottleWithOpts(middleware.ThrottleOpts{
 		Limit:          2,
@@ -101,6 +226,12 @@ func NewMux(h *Handler, reg *prometheus.Registry) http.Handler {
 	// Clients retry really aggressively and create thundering herds. Tell them
 	// to back off if we're overloaded.
 	server := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		limit := _maxRequestBytes
+		if r.URL.Path == "/book/bulk" {
+			limit = _maxBulkRequestBytes
+		}
+		r.Body = http.MaxBytesReader(w, r.Body, limit)
+
 		// Only throttle GET /author.
 		if r.Method == "GET" && strings.HasPrefix(r.URL.Path, "/author/") {
 			throttled.ServeHTTP(w, r)
@@ -110,7 +241,57 @@ func NewMux(h *Handler, reg *prometheus.Registry) http.Handler {
 		mux.ServeHTTP(w, r)
 	})
 
-	return instrument(reg, server)
+	instrumented := instrument(reg, server)
+	docs := docsMux(h.link("/"))
+
+	// Docs are served outside of our instrumentation so they don't show up in
+	// metrics.
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if isDocsPath(r.URL.Path) {
+			docs.ServeHTTP(w, r)
+			return
+		}
+		instrumented.ServeHTTP(w, r)
+	})
+}
+
+// Middleware wraps a mux with everything we serve it behind: request logging,
+// request IDs and panic recovery.
+func Middleware(mux http.Handler) http.Handler {
+	mux = Requestlogger{}.Wrap(mux) // Log requests.
+	mux = middleware.RequestID(mux) // Include a request ID header.
+	mux = middleware.Recoverer(mux) // Recover from panics.
+	return mux
+}
+
+// docsMux serves our embedded OpenAPI spec and a Swagger UI for browsing it.
+// base is the external path our routes are served under.
+func docsMux(base string) http.Handler {
+	mux := http.NewServeMux()
+
+	spec := func(w http.ResponseWriter, r *http.Request) {
+		http.ServeFileFS(w, r, _spec, "swagger.json")
+	}
+	mux.HandleFunc("/openapi.json", spec)
+	mux.HandleFunc("/swagger.json", spec)
+	mux.Handle("/docs/", swaggerUI(base))
+
+	return mux
+}
+
+func swaggerUI(base string) http.Handler {
+	return swagger.NewHandlerWithConfig(swgui.Config{
+		Title:            "BookInfo Metadata API",
+		SwaggerJSON:      path.Join(base, "/openapi.json"),
+		BasePath:         path.Join(base, "/docs") + "/",
+		InternalBasePath: "/docs/",
+		JsonEditor:       true,
+	})
+}
+
+// isDocsPath returns true for requests handled by docsMux.
+func isDocsPath(path string) bool {
+	return path == "/openapi.json" || path == "/swagger.json" || strings.HasPrefix(path, "/docs/")
 }
 
 // search performs a query against the metadata server.
@@ -142,8 +323,8 @@ func (h *Handler) search(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	h.cacheFor(w, "search", _searchTTL, true)
 	w.WriteHeader(http.StatusOK)
-	cacheFor(w, _searchTTL, true)
 	_ = json.NewEncoder(w).Encode(result)
 }
 
@@ -169,23 +350,32 @@ func (h *Handler) bulkBook(w http.ResponseWriter, r *http.Request) {
 	// If this is a POST, redirect to a GET with query params so the result can
 	// be cached.
 	if r.Method == http.MethodPost {
-		err := json.NewDecoder(r.Body).Decode(&ids)
+		body, err := io.ReadAll(r.Body)
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			h.error(w, errors.Join(err, statusErr(http.StatusRequestEntityTooLarge)))
+			return
+		}
 		if err != nil {
 			h.error(w, errors.Join(err, errBadRequest))
 			return
 		}
+		// Cataloging tools can send ISBNs and ASINs instead of IDs.
+		var identifiers []string
+		if json.Unmarshal(body, &identifiers) == nil {
+			h.bulkIdentifiers(w, r, identifiers)
+			return
+		}
+		if err := json.Unmarshal(body, &ids); err != nil {
+			h.error(w, errors.Join(err, errBadRequest))
+			return
+		}
 		if len(ids) == 0 {
 			h.error(w, errMissingIDs)
 			return
 		}
 
-		query := url.Values{}
-		url := url.URL{Path: r.URL.Path}
-		for _, id := range ids {
-			query.Add("id", fmt.Sprint(id))
-		}
-
-		url.RawQuery = query.Encode()
+		url := url.URL{Path: h.link(r.URL.Path), RawQuery: bulkQuery(url.Values{}, ids)}
 
 		Log(ctx).Debug("redirecting", "url", url.String())
 		http.Redirect(w, r, url.String(), http.StatusSeeOther)
@@ -210,20 +400,52 @@ func (h *Handler) bulkBook(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	result := bulkBookResource{
-		Works:   []workResource{},
-		Series:  []SeriesResource{},
-		Authors: []AuthorResource{},
+	// Clients list IDs in whatever order they like. Redirect equivalent
+	// requests to one canonical URL so they share a cache entry.
+	if canonical := bulkQuery(r.URL.Query(), ids); canonical != r.URL.RawQuery {
+		url := url.URL{Path: h.link(r.URL.Path), RawQuery: canonical}
+		http.Redirect(w, r, url.String(), http.StatusSeeOther)
+		return
+	}
+
+	result, _, timedOut := h.loadBulk(ctx, ids)
+
+	h.cacheFor(w, "bulk", _searchTTL, true)
+	if len(timedOut) > 0 {
+		// Don't let partial results stick around.
+		w.Header().Set("Cache-Control", "no-store")
+	}
+	_ = json.NewEncoder(w).Encode(result)
+}
+
+// loadBulk loads the given books (editions) in parallel for a bulk response,
+// waiting at most the bulk timeout. Books which can't be loaded are left out.
+// The IDs which loaded, and those still loading when we stopped waiting, are
+// also returned.
+func (h *Handler) loadBulk(ctx context.Context, ids []int64) (result bulkBookResource, loaded set[int64], timedOut []int64) {
+	// One slow book shouldn't hold up the rest of them.
+	if h.bulkTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, h.bulkTimeout)
+		defer cancel()
 	}
 
 	mu := sync.Mutex{}
 	wg := sync.WaitGroup{}
+	pending := newSet(ids...)
+	got := newSet[int64]()
+	acc := bulkBookResource{}
 
 	for _, id := range ids {
 		wg.Add(1)
 
 		go func(foreignBookID int64) {
 			defer wg.Done()
+			defer func() {
+				mu.Lock()
+				defer mu.Unlock()
+				delete(pending, foreignBookID)
+			}()
 
 			b, _, err := h.ctrl.GetBook(ctx, foreignBookID)
 			if err != nil {
@@ -249,21 +471,44 @@ func (h *Handler) bulkBook(w http.ResponseWriter, r *http.Request) {
 			mu.Lock()
 			defer mu.Unlock()
 
-			result.Works = append(result.Works, workRsc)
-			result.Series = []SeriesResource{}
+			got[foreignBookID] = struct{}{}
+			acc.Works = append(acc.Works, workRsc)
 
 			// Check if our result already includes this author.
-			for _, a := range result.Authors {
+			for _, a := range acc.Authors {
 				if a.ForeignID == workRsc.Authors[0].ForeignID {
 					return // Nothing more to do.
 				}
 			}
 
-			result.Authors = append(result.Authors, workRsc.Authors...)
+			acc.Authors = append(acc.Authors, workRsc.Authors...)
 		}(id)
 	}
 
-	wg.Wait()
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+	}
+
+	// Stragglers can still finish after we stop waiting, so respond with a
+	// copy of whatever completed in time.
+	mu.Lock()
+	timedOut = slices.Sorted(maps.Keys(pending))
+	loaded = maps.Clone(got)
+	result = bulkBookResource{
+		Works:   append([]workResource{}, acc.Works...),
+		Series:  []SeriesResource{},
+		Authors: append([]AuthorResource{}, acc.Authors...),
+	}
+	mu.Unlock()
+	if len(timedOut) > 0 {
+		Log(ctx).Warn("bulk request timed out", "bookIDs", timedOut, "timeout", h.bulkTimeout)
+	}
 
 	// Collect and de-dupe series -- is this even needed?
 	seenSeries := map[int64]bool{}
@@ -282,10 +527,120 @@ func (h *Handler) bulkBook(w http.ResponseWriter, r *http.Request) {
 		return -cmp.Compare(left.Books[0].RatingCount, right.Books[0].RatingCount)
 	})
 
-	cacheFor(w, _searchTTL, true)
+	return result, loaded, timedOut
+}
+
+// _bulkResolvers bounds how many identifiers in a bulk request are resolved
+// at once.
+var _bulkResolvers = 10
+
+// bulkIdentifiers hydrates editions by ISBN or ASIN instead of ID. Each
+// identifier is resolved like a search for it, and the response reports what
+// happened to every identifier. It isn't cached.
+
+// @summary Hydrate editions by ISBN or ASIN in bulk
+// @description Resolve several ISBNs or ASINs and fetch their editions. Identifiers which don't resolve are left out, and Identifiers reports each one's status: 200 if its edition is included, 400 if it isn't an ISBN or ASIN, 404 if it's unknown or its edition couldn't be loaded, and 504 if it timed out.
+// @success 200 {object} bulkBookResource
+// @router /book/bulk [post]
+// @param identifiers body []string true "ISBNs or ASINs to hydrate."
+func (h *Handler) bulkIdentifiers(w http.ResponseWriter, r *http.Request, identifiers []string) {
+	ctx := r.Context()
+
+	statuses := []bulkIdentifierResource{}
+	seen := newSet[string]()
+	for _, identifier := range identifiers {
+		identifier = strings.TrimSpace(identifier)
+		if _, ok := seen[identifier]; ok {
+			continue
+		}
+		seen[identifier] = struct{}{}
+		statuses = append(statuses, bulkIdentifierResource{Identifier: identifier})
+	}
+	if len(statuses) == 0 {
+		h.error(w, errMissingIDs)
+		return
+	}
+
+	// Resolving and loading share the bulk timeout.
+	if h.bulkTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, h.bulkTimeout)
+		defer cancel()
+	}
+
+	g := errgroup.Group{}
+	g.SetLimit(_bulkResolvers)
+	for i := range statuses {
+		g.Go(func() error {
+			statuses[i].ForeignEditionID, statuses[i].Status = h.resolveIdentifier(ctx, statuses[i].Identifier)
+			return nil
+		})
+	}
+	_ = g.Wait()
+
+	ids := []int64{}
+	for _, s := range statuses {
+		if s.Status == http.StatusOK {
+			ids = append(ids, s.ForeignEditionID)
+		}
+	}
+	slices.Sort(ids)
+	ids = slices.Compact(ids)
+
+	result, loaded, timedOut := h.loadBulk(ctx, ids)
+	for i, s := range statuses {
+		if s.Status != http.StatusOK {
+			continue
+		}
+		if _, ok := loaded[s.ForeignEditionID]; ok {
+			continue
+		}
+		statuses[i].Status = http.StatusNotFound
+		if slices.Contains(timedOut, s.ForeignEditionID) {
+			statuses[i].Status = http.StatusGatewayTimeout
+		}
+	}
+	result.Identifiers = statuses
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
 	_ = json.NewEncoder(w).Encode(result)
 }
 
+// resolveIdentifier returns the edition ID for an ISBN or ASIN, along with
+// the HTTP status describing the outcome.
+func (h *Handler) resolveIdentifier(ctx context.Context, identifier string) (int64, int) {
+	isbn, _ := parseISBN(identifier)
+	if isbn == nil && !_asin.MatchString(normalizeASIN(identifier)) {
+		return 0, http.StatusBadRequest
+	}
+	results, err := h.ctrl.Search(ctx, identifier)
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return 0, http.StatusGatewayTimeout
+	case err != nil:
+		Log(ctx).Warn("resolving identifier", "err", err, "identifier", identifier)
+		return 0, errStatus(err)
+	case len(results) == 0 || results[0].BookID == 0:
+		return 0, http.StatusNotFound
+	}
+	return results[0].BookID, http.StatusOK
+}
+
+// bulkQuery returns the canonical query for a bulk request, with IDs sorted
+// and de-duplicated. Other params are preserved.
+func bulkQuery(query url.Values, ids []int64) string {
+	ids = slices.Clone(ids)
+	slices.Sort(ids)
+
+	query = maps.Clone(query)
+	query.Del("id")
+	for _, id := range slices.Compact(ids) {
+		query.Add("id", strconv.FormatInt(id, 10))
+	}
+	return query.Encode()
+}
+
 // getWorkID handles /work/{id}
 //
 // Upstream is /work/{workID} which redirects to /book/show/{bestBookID}.
@@ -295,10 +650,13 @@ func (h *Handler) bulkBook(w http.ResponseWriter, r *http.Request) {
 // @success 200 {object} workResource
 // @router /work/{workId} [get]
 // @param workId path int true "Work ID"
+// @param lang query string false "Preferred edition language (ISO 639-1 or 639-3); requests without it are redirected according to Accept-Language"
+// @param format query string false "Set to msgpack for MessagePack; requests without it which accept application/msgpack are redirected here"
+// @param debug query string false "Set to 1 to include which source produced each work and author, and an X-Cache-TTL header, if the server enables debug headers"
 func (h *Handler) getWorkID(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
-	workID, err := pathToID(r.URL.Path)
+	workID, err := pathID(r, "foreignID")
 	if err != nil {
 		h.error(w, err)
 		return
@@ -310,28 +668,79 @@ func (h *Handler) getWorkID(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Honor Accept-Language by redirecting to the equivalent ?lang= URL,
+	// which CDNs can cache. The redirect itself depends on the header so it
+	// isn't shared.
+	if !r.URL.Query().Has("lang") {
+		if lang := acceptLanguage(r); lang != "" && lang != _primaryLanguage {
+			query := r.URL.Query()
+			query.Set("lang", lang)
+			target := url.URL{Path: h.link(r.URL.Path), RawQuery: query.Encode()}
+			w.Header().Set("Cache-Control", "private")
+			vary(w, "Accept-Language")
+			http.Redirect(w, r, target.String(), http.StatusSeeOther)
+			return
+		}
+	}
+	if h.redirectMsgpack(w, r) {
+		return
+	}
+
 	out, ttl, err := h.ctrl.GetWork(ctx, workID)
 	if err != nil {
-		h.error(w, err)
-		return
+		if out, err = h.stale(w, r, WorkKey(workID), err); err != nil {
+			h.error(w, err)
+			return
+		}
+	}
+
+	canonicalLocation(w, h.link("/work"), workID, servedID(out))
+	out = h.withoutSource(r, out)
+
+	// Editions are already ordered for the primary language when the work
+	// is saved, so we only need to reorder them for a different one.
+	if lang := preferredLanguage(r); lang != "" && lang != _primaryLanguage {
+		var work workResource
+		if err := json.Unmarshal(out, &work); err != nil {
+			h.error(w, err)
+			return
+		}
+		preferLanguage(work.Books, lang)
+		if out, err = json.Marshal(work); err != nil {
+			h.error(w, err)
+			return
+		}
 	}
 
+	h.debugTTL(w, r, ttl)
 	if ttl > 0 {
-		cacheFor(w, ttl, false)
+		h.cacheFor(w, "work", ttl, false)
+		// The response depends on the caller's language preference.
+		w.Header().Set("No-Vary-Search", `params, except=("lang" "format" "debug")`)
 	}
+	out = h.encode(w, r, negotiate[workResource](w, r, out))
 	w.WriteHeader(http.StatusOK)
 	_, _ = w.Write(out)
 }
 
-// cacheFor sets cache response headers. s-maxage controls CDN cache time; we
-// default to an hour expiry for clients.
+// cacheFor sets cache response headers. s-maxage controls CDN cache time and
+// defaults to d. Clients pick their own expiry unless the endpoint's policy
+// sets a max-age.
 //
-// Set varyParams to true if the cache key should include query params.
-func cacheFor(w http.ResponseWriter, d time.Duration, varyParams bool) {
-	w.Header().Add("Cache-Control", fmt.Sprintf("public, s-maxage=%d", int(d.Seconds())))
-	w.Header().Add("Vary", "Content-Type,Accept-Encoding") // Ignore headers like User-Agent, etc.
+// Set varyParams to true if the cache key should include query params. Vary
+// always includes Accept-Encoding because encode may compress the body.
+func (h *Handler) cacheFor(w http.ResponseWriter, endpoint string, d time.Duration, varyParams bool) {
+	policy := h.cachePolicies[endpoint]
+	if policy.SMaxAge > 0 {
+		d = policy.SMaxAge
+	}
+	if policy.MaxAge > 0 {
+		w.Header().Add("Cache-Control", fmt.Sprintf("public, max-age=%d, s-maxage=%d", int(policy.MaxAge.Seconds()), int(d.Seconds())))
+	} else {
+		w.Header().Add("Cache-Control", fmt.Sprintf("public, s-maxage=%d", int(d.Seconds())))
+	}
+	vary(w, "Content-Type", "Accept-Encoding") // Ignore headers like User-Agent, etc.
 	w.Header().Add("Content-Type", "application/json")
-	// w.Header().Add("Content-Encoding", "gzip") // TODO: Negotiate this with the client.
 
 	if !varyParams {
 		// In most cases we ignore query params when serving cached responses,
@@ -341,6 +750,37 @@ func cacheFor(w http.ResponseWriter, d time.Duration, varyParams bool) {
 	}
 }
 
+// stale falls back to expired data for key when upstream is failing, marking
+// the response as stale. The original error is returned if there's nothing we
+// can serve.
+func (h *Handler) stale(w http.ResponseWriter, r *http.Request, key string, err error) ([]byte, error) {
+	out, ok := h.ctrl.getStale(r.Context(), key, err)
+	if !ok {
+		return nil, err
+	}
+	w.Header().Set("Warning", `110 - "Response is Stale"`)
+	return out, nil
+}
+
+// canonicalLocation sets a Content-Location header when we served a resource
+// with a different ID than the one requested, e.g. because upstream merged it
+// into another. This lets clients update their references.
+func canonicalLocation(w http.ResponseWriter, prefix string, requested, served int64) {
+	if served == 0 || served == requested {
+		return
+	}
+	w.Header().Set("Content-Location", fmt.Sprintf("%s/%d", prefix, served))
+}
+
+// servedID returns the ForeignId of a serialized work or author.
+func servedID(out []byte) int64 {
+	var rsc struct {
+		ForeignID int64 `json:"ForeignId"`
+	}
+	_ = json.Unmarshal(out, &rsc)
+	return rsc.ForeignID
+}
+
 // getBookID handles /book/{id}.
 //
 // Importantly, the client expects this to always return a redirect -- either
@@ -360,7 +800,7 @@ func cacheFor(w http.ResponseWriter, d time.Duration, varyParams bool) {
 func (h *Handler) getBookID(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
-	bookID, err := pathToID(r.URL.Path)
+	bookID, err := pathID(r, "foreignEditionID")
 	if err != nil {
 		h.error(w, err)
 		return
@@ -374,8 +814,10 @@ func (h *Handler) getBookID(w http.ResponseWriter, r *http.Request) {
 
 	b, ttl, err := h.ctrl.GetBook(ctx, bookID)
 	if err != nil {
-		h.error(w, err)
-		return
+		if b, err = h.stale(w, r, BookKey(bookID), err); err != nil {
+			h.error(w, err)
+			return
+		}
 	}
 
 	var workRsc workResource
@@ -386,18 +828,25 @@ func (h *Handler) getBookID(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if ttl > 0 {
-		cacheFor(w, ttl, false)
+		h.cacheFor(w, "book", ttl, false)
+	}
+	if len(workRsc.Books) > 0 {
+		canonicalLocation(w, h.link("/book"), bookID, workRsc.Books[0].ForeignID)
 	}
 
 	if len(workRsc.Authors) > 0 {
-		http.Redirect(w, r, fmt.Sprintf("/author/%d?edition=%d", workRsc.Authors[0].ForeignID, bookID), http.StatusSeeOther)
+		target := fmt.Sprintf("%s?edition=%d", h.link(fmt.Sprintf("/author/%d", workRsc.Authors[0].ForeignID)), bookID)
+		if h.debugging(r) {
+			target += "&debug=1"
+		}
+		http.Redirect(w, r, target, http.StatusSeeOther)
 		return
 	}
 
 	// This doesn't actually work -- the client gets a
 	// System.NullReferenceException. But we should always have an author, so
 	// we should never hit this.
-	http.Redirect(w, r, fmt.Sprintf("/work/%d", workRsc.ForeignID), http.StatusSeeOther)
+	http.Redirect(w, r, h.link(fmt.Sprintf("/work/%d", workRsc.ForeignID)), http.StatusSeeOther)
 }
 
 // @summary Look up a foreign edition ID by ASIN
@@ -409,8 +858,8 @@ func (h *Handler) getBookID(w http.ResponseWriter, r *http.Request) {
 func (h *Handler) getASIN(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
-	asin := strings.TrimSpace(r.PathValue("asin"))
-	if !_asin.Match([]byte(asin)) {
+	asin := normalizeASIN(r.PathValue("asin"))
+	if !_asin.MatchString(asin) {
 		h.error(w, errBadRequest)
 		return
 	}
@@ -421,7 +870,7 @@ func (h *Handler) getASIN(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	http.Redirect(w, r, fmt.Sprintf("/book/%d", editionID), http.StatusSeeOther)
+	http.Redirect(w, r, h.link(fmt.Sprintf("/book/%d", editionID)), http.StatusSeeOther)
 }
 
 // @summary Look up a foreign edition ID by ISBN10 or ISBN13
@@ -433,8 +882,7 @@ func (h *Handler) getASIN(w http.ResponseWriter, r *http.Request) {
 func (h *Handler) getISBN(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
-	s := strings.TrimSpace(r.PathValue("isbn"))
-	isbn, err := isbn.Parse(s)
+	isbn, err := parseISBN(r.PathValue("isbn"))
 	if err != nil || isbn == nil {
 		h.error(w, errors.Join(errBadRequest, err))
 		return
@@ -446,7 +894,65 @@ func (h *Handler) getISBN(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	http.Redirect(w, r, fmt.Sprintf("/book/%d", editionID), http.StatusSeeOther)
+	http.Redirect(w, r, h.link(fmt.Sprintf("/book/%d", editionID)), http.StatusSeeOther)
+}
+
+// Paths of the public pages we know how to resolve. Slugs and titles after
+// the ID are ignored by pathToID.
+var (
+	_grBookURL     = regexp.MustCompile(`^/book/show/([^/]+)/?$`)
+	_grWorkURL     = regexp.MustCompile(`^/work/(?:editions/)?([^/]+)/?$`)
+	_hcEditionURL  = regexp.MustCompile(`^/books/[^/]+/editions/([^/]+)/?$`)
+	_hcWorkSlugURL = regexp.MustCompile(`^/books/([^/]+)/?$`)
+)
+
+// @summary Resolve a book's public URL
+// @description Redirects a G——R—— /book/show/{id} or /work/editions/{id} URL, or a Hardcover /books/{slug} or /books/{slug}/editions/{id} URL, to the corresponding /work/ or /book/ endpoint. URLs from a different upstream than the one we serve are rejected.
+// @success 303
+// @failure 400 int int
+// @failure 404 int int
+// @param url query string true "The URL to resolve"
+// @router /resolve [get]
+func (h *Handler) resolve(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	raw := strings.TrimSpace(r.URL.Query().Get("url"))
+	if raw != "" && !strings.Contains(raw, "://") {
+		raw = "https://" + raw
+	}
+	u, err := url.Parse(raw)
+	if err != nil || raw == "" {
+		h.error(w, errors.Join(errBadRequest, err))
+		return
+	}
+	if !h.ctrl.servesHost(u.Hostname()) {
+		h.error(w, errors.Join(errBadRequest, fmt.Errorf("%q isn't served by this upstream", u.Hostname())))
+		return
+	}
+
+	var target string
+	switch p := u.Path; {
+	case _grBookURL.MatchString(p), _hcEditionURL.MatchString(p):
+		var bookID int64
+		bookID, err = pathToID(p)
+		target = fmt.Sprintf("/book/%d", bookID)
+	case _grWorkURL.MatchString(p):
+		var workID int64
+		workID, err = pathToID(p)
+		target = fmt.Sprintf("/work/%d", workID)
+	case _hcWorkSlugURL.MatchString(p):
+		var workID int64
+		workID, err = h.ctrl.GetWorkIDBySlug(ctx, _hcWorkSlugURL.FindStringSubmatch(p)[1])
+		target = fmt.Sprintf("/work/%d", workID)
+	default:
+		err = errors.Join(errBadRequest, fmt.Errorf("unrecognized URL %q", raw))
+	}
+	if err != nil {
+		h.error(w, err)
+		return
+	}
+
+	http.Redirect(w, r, h.link(target), http.StatusSeeOther)
 }
 
 // getAuthorID handles /author/{id}.
@@ -464,11 +970,13 @@ func (h *Handler) getISBN(w http.ResponseWriter, r *http.Request) {
 // @success 200 {object} AuthorResource
 // @param authorId path int true "Author ID"
 // @param editionId path int false "Return the author with only this edition loaded; more performant"
+// @param format query string false "Set to msgpack for MessagePack; requests without it which accept application/msgpack are redirected here"
+// @param debug query string false "Set to 1 to include which source produced each work and author, and an X-Cache-TTL header, if the server enables debug headers"
 // @router /author/{authorId} [get]
 func (h *Handler) getAuthorID(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
-	authorID, err := pathToID(r.URL.Path)
+	authorID, err := pathID(r, "foreignAuthorID")
 	if err != nil {
 		h.error(w, err)
 		return
@@ -477,12 +985,15 @@ func (h *Handler) getAuthorID(w http.ResponseWriter, r *http.Request) {
 	if r.Method == "DELETE" {
 		ctx := context.WithValue(context.Background(), middleware.RequestIDKey, fmt.Sprintf("author-bust-%d", authorID))
 
-		bytes, _ := h.ctrl.cache.Get(r.Context(), AuthorKey(authorID))
-		_ = h.ctrl.cache.Expire(r.Context(), AuthorKey(authorID))
-		_ = h.ctrl.cache.Expire(r.Context(), refreshAuthorKey(authorID))
+		// Bust and refresh together in the background, so nothing is busted
+		// if there's no room to refresh.
+		full := r.URL.Query().Get("full") != ""
+		busted := h.ctrl.spawn(ctx, "author-bust", func() {
+			bytes, _ := h.ctrl.cache.Get(ctx, AuthorKey(authorID))
+			_ = h.ctrl.cache.Expire(ctx, AuthorKey(authorID))
+			_ = h.ctrl.cache.Expire(ctx, refreshAuthorKey(authorID))
 
-		go func() {
-			if r.URL.Query().Get("full") != "" {
+			if full {
 				// Expire all works/editions.
 				var author AuthorResource
 				_ = json.Unmarshal(bytes, &author)
@@ -501,21 +1012,30 @@ func (h *Handler) getAuthorID(w http.ResponseWriter, r *http.Request) {
 			if errors.Is(err, errNotFound) {
 				// This author has been deleted, remove the entry.
 				_ = h.ctrl.cache.Delete(ctx, AuthorKey(authorID))
-				w.WriteHeader(http.StatusNotFound)
 				return
 			}
 			if err != nil {
 				Log(ctx).Warn("problem refreshing", "err", err)
 			}
-		}()
+		})
+		if !busted {
+			h.error(w, statusErr(http.StatusServiceUnavailable))
+			return
+		}
 		w.WriteHeader(http.StatusOK)
 		return
 	}
 
+	if h.redirectMsgpack(w, r) {
+		return
+	}
+
 	out, ttl, err := h.ctrl.GetAuthor(r.Context(), authorID)
 	if err != nil {
-		h.error(w, err)
-		return
+		if out, err = h.stale(w, r, AuthorKey(authorID), err); err != nil {
+			h.error(w, err)
+			return
+		}
 	}
 
 	// If a specific edition was requested, mutate the returned author to
@@ -548,21 +1068,197 @@ func (h *Handler) getAuthorID(w http.ResponseWriter, r *http.Request) {
 
 		author.Works = []workResource{work}
 
+		out, err = json.Marshal(author)
+		if err != nil {
+			h.error(w, err)
+			return
+		}
+
+		h.debugTTL(w, r, ttl)
 		if ttl > 0 {
-			cacheFor(w, ttl, true)
+			h.cacheFor(w, "author", ttl, true)
 		}
-		_ = json.NewEncoder(w).Encode(author)
+		canonicalLocation(w, h.link("/author"), authorID, author.ForeignID)
+		out = h.encode(w, r, negotiate[AuthorResource](w, r, append(h.withoutSource(r, out), '\n')))
+		_, _ = w.Write(out)
 		return
 
 	}
 
+	h.debugTTL(w, r, ttl)
 	if ttl > 0 {
-		cacheFor(w, ttl, true)
+		h.cacheFor(w, "author", ttl, true)
 	}
+	canonicalLocation(w, h.link("/author"), authorID, servedID(out))
+	out = h.encode(w, r, negotiate[AuthorResource](w, r, h.withoutSource(r, out)))
 	w.WriteHeader(http.StatusOK)
 	_, _ = w.Write(out)
 }
 
+// _authorWorksPageSize and _authorWorksMaxPageSize are the default and
+// largest number of works returned per page by /author/{id}/works.
+const (
+	_authorWorksPageSize    = 20
+	_authorWorksMaxPageSize = 100
+)
+
+// @summary Fetch a page of an author's works
+// @description A lighter alternative to the full author for browsing. Works only include their best edition.
+// @success 200 {object} AuthorWorksResource
+// @param authorId path int true "Author ID"
+// @param page query int false "The page of results, starting at 1"
+// @param size query int false "How many works to return per page, at most 100"
+// @param sort query string false "Sort by rating, popular (rating count), date (newest first) or title. Defaults to the author's order"
+// @router /author/{authorId}/works [get]
+func (h *Handler) getAuthorWorks(w http.ResponseWriter, r *http.Request) {
+	authorID, err := pathID(r, "foreignAuthorID")
+	if err != nil {
+		h.error(w, err)
+		return
+	}
+
+	query := r.URL.Query()
+	page, err := positiveParam(query, "page", 1)
+	if err != nil {
+		h.error(w, err)
+		return
+	}
+	size, err := positiveParam(query, "size", _authorWorksPageSize)
+	if err != nil {
+		h.error(w, err)
+		return
+	}
+	size = min(size, _authorWorksMaxPageSize)
+
+	out, ttl, err := h.ctrl.GetAuthor(r.Context(), authorID)
+	if err != nil {
+		if out, err = h.stale(w, r, AuthorKey(authorID), err); err != nil {
+			h.error(w, err)
+			return
+		}
+	}
+	var author AuthorResource
+	if err := json.Unmarshal(out, &author); err != nil {
+		h.error(w, err)
+		return
+	}
+
+	result, err := pageAuthorWorks(author, query.Get("sort"), page, size)
+	if err != nil {
+		h.error(w, err)
+		return
+	}
+
+	h.debugTTL(w, r, ttl)
+	if ttl > 0 {
+		h.cacheFor(w, "author", ttl, true)
+	}
+	canonicalLocation(w, h.link("/author"), authorID, author.ForeignID)
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(result)
+}
+
+// positiveParam parses an optional positive integer query param.
+func positiveParam(query url.Values, name string, def int) (int, error) {
+	raw := query.Get(name)
+	if raw == "" {
+		return def, nil
+	}
+	n, err := strconv.Atoi(raw)
+	if err != nil || n < 1 {
+		return 0, errors.Join(fmt.Errorf("invalid %s %q", name, raw), errBadRequest)
+	}
+	return n, nil
+}
+
+// pageAuthorWorks sorts an author's works and returns the requested page,
+// trimming each work to its best edition.
+func pageAuthorWorks(author AuthorResource, sort string, page, size int) (AuthorWorksResource, error) {
+	works := slices.Clone(author.Works)
+	switch sort {
+	case "":
+	case "rating":
+		slices.SortStableFunc(works, func(a, b workResource) int {
+			return cmp.Or(cmp.Compare(b.AverageRating, a.AverageRating), cmp.Compare(b.RatingCount, a.RatingCount))
+		})
+	case "popular":
+		slices.SortStableFunc(works, func(a, b workResource) int {
+			return cmp.Compare(b.RatingCount, a.RatingCount)
+		})
+	case "date":
+		// Release dates start with a zero-padded date, so they sort lexically.
+		slices.SortStableFunc(works, func(a, b workResource) int {
+			return cmp.Compare(b.ReleaseDate, a.ReleaseDate)
+		})
+	case "title":
+		slices.SortStableFunc(works, func(a, b workResource) int {
+			return cmp.Compare(strings.ToLower(a.Title), strings.ToLower(b.Title))
+		})
+	default:
+		return AuthorWorksResource{}, errors.Join(fmt.Errorf("invalid sort %q", sort), errBadRequest)
+	}
+
+	start := len(works)
+	if page-1 < (len(works)+size-1)/size {
+		start = (page - 1) * size
+	}
+	works = works[start:min(start+size, len(works))]
+
+	result := AuthorWorksResource{
+		ForeignID: author.ForeignID,
+		Page:      page,
+		PageSize:  size,
+		Total:     len(author.Works),
+		Works:     make([]workResource, 0, len(works)),
+	}
+	for _, work := range works {
+		books := []bookResource{}
+		for _, b := range work.Books {
+			if b.ForeignID == work.BestBookID {
+				books = append(books, b)
+				break
+			}
+		}
+		if len(books) == 0 && len(work.Books) > 0 {
+			books = append(books, work.Books[0])
+		}
+		work.Books = books
+		work.Authors = []AuthorResource{}
+		work.Source = ""
+		result.Works = append(result.Works, work)
+	}
+	return result, nil
+}
+
+// _sourceField matches the Source field of serialized works and authors. A
+// raw quote can't appear inside an encoded JSON string, so this only ever
+// matches the field itself.
+var _sourceField = regexp.MustCompile(`,"Source":"[^"\\]*"`)
+
+// debugging returns true if the request asked for debugging information with
+// ?debug=1 and debug headers are enabled.
+func (h *Handler) debugging(r *http.Request) bool {
+	return h.debugHeaders && r.URL.Query().Get("debug") == "1"
+}
+
+// debugTTL sets an X-Cache-TTL header with the seconds remaining before the
+// cached resource expires, but only if the request is debugging.
+func (h *Handler) debugTTL(w http.ResponseWriter, r *http.Request, ttl time.Duration) {
+	if !h.debugging(r) {
+		return
+	}
+	w.Header().Set("X-Cache-TTL", fmt.Sprint(int64(ttl.Seconds())))
+}
+
+// withoutSource removes provenance from a serialized work or author unless
+// the request is debugging. This keeps our default response shape unchanged.
+func (h *Handler) withoutSource(r *http.Request, out []byte) []byte {
+	if h.debugging(r) {
+		return out
+	}
+	return _sourceField.ReplaceAll(out, nil)
+}
+
 // @summary Refresh an author
 // @decription Causes an author to be enqueued for refresh; doesn't necessarily refresh immediately
 // @success 200
@@ -581,7 +1277,7 @@ func deleteAuthorID() {} //nolint:unused // swag docs
 func (h *Handler) getSeriesID(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
-	seriesID, err := pathToID(r.URL.Path)
+	seriesID, err := pathID(r, "seriesID")
 	if err != nil {
 		h.error(w, err)
 		return
@@ -599,7 +1295,8 @@ func (h *Handler) getSeriesID(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	cacheFor(w, _seriesTTL, false)
+	h.cacheFor(w, "series", _seriesTTL, false)
+	out = h.encode(w, r, out)
 	w.WriteHeader(http.StatusOK)
 	_, _ = w.Write(out)
 }
@@ -623,7 +1320,7 @@ func (h *Handler) getSeriesID(w http.ResponseWriter, r *http.Request) {
 // These will hit cached entries, and the client will pick up newer data
 // gradually as entries become invalidated.
 func (h *Handler) getAuthorChanged(w http.ResponseWriter, _ *http.Request) {
-	cacheFor(w, _searchTTL, false)
+	h.cacheFor(w, "changed", _searchTTL, false)
 	w.WriteHeader(http.StatusOK)
 	_, _ = w.Write([]byte(`{"Limited": true, "Ids": []}`))
 }
@@ -631,12 +1328,26 @@ func (h *Handler) getAuthorChanged(w http.ResponseWriter, _ *http.Request) {
 // error writes an error message. The status code defaults to 500 unless the
 // error wraps a statusErr.
 func (*Handler) error(w http.ResponseWriter, err error) {
-	status := http.StatusInternalServerError
-	var s statusErr
-	if errors.As(err, &s) {
-		status = s.Status()
+	http.Error(w, err.Error(), errStatus(err))
+}
+
+// getRaw returns the raw upstream response behind a cache key, e.g. w123 for
+// work 123, if raw responses are being kept.
+func (h *Handler) getRaw(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	out, ok := h.ctrl.cache.Get(r.Context(), RawKey(r.PathValue("key")))
+	if !ok {
+		http.NotFound(w, r)
+		return
 	}
-	http.Error(w, err.Error(), status)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	_, _ = w.Write(out)
 }
 
 // reconfigure is only available to host-local clients and allows tweaking the
@@ -649,20 +1360,12 @@ func (h *Handler) reconfigure(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	ap, err := netip.ParseAddrPort(r.RemoteAddr)
-	if err != nil {
+	Log(ctx).Warn("reconfigure request", "addr", r.RemoteAddr)
+
+	if ok, err := h.trusted(r); err != nil {
 		h.error(w, err)
 		return
-	}
-
-	Log(ctx).Warn("reconfigure request", "addr", r.RemoteAddr, "ip", ap.Addr().String())
-
-	network := net.IPNet{
-		IP:   net.ParseIP("10.0.0.0"),
-		Mask: net.CIDRMask(8, 32),
-	}
-
-	if !network.Contains(net.IP(ap.Addr().AsSlice())) {
+	} else if !ok {
 		w.WriteHeader(http.StatusUnauthorized)
 		return
 	}
@@ -678,12 +1381,11 @@ func (h *Handler) reconfigure(w http.ResponseWriter, r *http.Request) {
 
 	if gr, ok := h.ctrl.getter.(*GRGetter); ok {
 		gql := gr.gql.(*batchedgqlclient)
+		gql.reconfigure(every, body.BatchSize)
 		if body.BatchSize > 0 {
-			gql.batchSize = body.BatchSize
 			Log(ctx).Warn("set batch size", "size", body.BatchSize)
 		}
 		if every > 0 {
-			gql.every = every
 			Log(ctx).Warn("set gql sleep", "every", every)
 		}
 	}
@@ -691,11 +1393,68 @@ func (h *Handler) reconfigure(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
 }
 
+// adminReload is only available to host-local clients and re-applies
+// configuration without dropping any caches.
+func (h *Handler) adminReload(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	if r.Method != "POST" {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	Log(ctx).Warn("reload request", "addr", r.RemoteAddr)
+
+	if ok, err := h.trusted(r); err != nil {
+		h.error(w, err)
+		return
+	} else if !ok {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	if h.reload == nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	if err := h.reload(ctx); err != nil {
+		Log(ctx).Error("reloading config", "err", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// trusted returns true if the request originated from loopback or one of our
+// admin networks. Private ranges aren't trusted implicitly, since behind a
+// reverse proxy or on a Docker bridge every request appears to come from one.
+func (h *Handler) trusted(r *http.Request) (bool, error) {
+	ap, err := netip.ParseAddrPort(r.RemoteAddr)
+	if err != nil {
+		return false, err
+	}
+
+	ip := ap.Addr().Unmap()
+	if ip.IsLoopback() {
+		return true, nil
+	}
+	for _, p := range h.adminNetworks {
+		if p.Contains(ip) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
 // @summary Fetch rending work IDs
 // @description This matches what you see on the upstream website's "trending" page.
 // @success 200 {object} RecommentationsResource
 // @router /recommended [get]
 // @param page query string true "The page of results; not supported by G—R—"
+// @param genre query string false "Only return popular works in this genre"
+// @param seed query int false "Only return works similar to this work ID"
 func (h *Handler) recommended(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
@@ -719,18 +1478,47 @@ func (h *Handler) recommended(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	result, err := h.ctrl.Recommendations(ctx, page)
+	genre := strings.TrimSpace(r.URL.Query().Get("genre"))
+	var seed int64
+	if seedParam := r.URL.Query().Get("seed"); seedParam != "" {
+		var err error
+		seed, err = pathToID(seedParam)
+		if err != nil {
+			h.error(w, err)
+			return
+		}
+	}
+	if genre != "" && seed != 0 {
+		h.error(w, errors.Join(fmt.Errorf("genre and seed can't be combined"), errBadRequest))
+		return
+	}
+
+	result, err := h.ctrl.Recommendations(ctx, page, genre, seed)
 	if err != nil {
 		h.error(w, err)
 		return
 	}
 
+	h.cacheFor(w, "recommended", _recommendedTTL, true)
 	w.WriteHeader(http.StatusOK)
-	cacheFor(w, _recommendedTTL, true)
 	_ = json.NewEncoder(w).Encode(result)
 }
 
-var _number = regexp.MustCompile("-?[0-9]+")
+var (
+	_number = regexp.MustCompile("-?[0-9]+")
+	_digits = regexp.MustCompile("^[0-9]+$")
+)
+
+// pathID parses the named ID path param. Unlike pathToID, which digs IDs out
+// of upstream URLs, it's strict: anything other than a positive integer is a
+// bad request.
+func pathID(r *http.Request, name string) (int64, error) {
+	s := r.PathValue(name)
+	if !_digits.MatchString(s) {
+		return 0, errors.Join(fmt.Errorf("invalid ID %q", s), errBadRequest)
+	}
+	return pathToID(s)
+}
 
 func pathToID(p string) (int64, error) {
 	p = path.Base(p)
@@ -1,9 +1,25 @@
 package internal
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/Khan/genqlient/graphql"
+	"github.com/blampe/rreading-glasses/hardcover"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
 )
 
 func TestPathToID(t *testing.T) {
@@ -54,3 +70,839 @@ func TestPathToID(t *testing.T) {
 		assert.Equal(t, tt.want, actual)
 	}
 }
+
+func TestMalformedIDs(t *testing.T) {
+	// Malformed IDs are rejected before reaching the controller. The mock
+	// getter fails on any unexpected call.
+	ctrl, err := NewController(newMemoryCache(), NewMockgetter(gomock.NewController(t)), nil, nil)
+	require.NoError(t, err)
+	mux := NewMux(NewHandler(ctrl), prometheus.NewRegistry())
+
+	for _, resource := range []string{"work", "book", "author", "series"} {
+		for _, id := range []string{"123abc", "-5", "0", "1.5", "10000000000", "99999999999999999999"} {
+			path := "/" + resource + "/" + id
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+			assert.Equal(t, http.StatusBadRequest, w.Code, path)
+		}
+	}
+}
+
+func TestDocs(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	mux := NewMux(NewHandler(nil), reg)
+
+	for _, path := range []string{"/openapi.json", "/swagger.json", "/docs/"} {
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+		assert.Equal(t, http.StatusOK, w.Code, path)
+	}
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest("GET", "/openapi.json", nil))
+	assert.Contains(t, w.Header().Get("Content-Type"), "application/json")
+	assert.True(t, json.Valid(w.Body.Bytes()))
+
+	// Docs shouldn't be instrumented.
+	families, err := reg.Gather()
+	require.NoError(t, err)
+	for _, f := range families {
+		assert.NotEqual(t, "rg_http_requests", f.GetName())
+	}
+}
+
+func TestPreferredLanguage(t *testing.T) {
+	tests := []struct {
+		name   string
+		query  string
+		header string
+		want   string
+	}{
+		{name: "none"},
+		{name: "param", query: "?lang=fr", want: "fra"},
+		{name: "param 639-3", query: "?lang=deu", want: "deu"},
+		{name: "header ignored", header: "fr", want: ""},
+		{name: "unknown", query: "?lang=xx-YY", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/work/1"+tt.query, nil)
+			if tt.header != "" {
+				r.Header.Set("Accept-Language", tt.header)
+			}
+			assert.Equal(t, tt.want, preferredLanguage(r))
+		})
+	}
+
+	headers := []struct {
+		header string
+		want   string
+	}{
+		{header: ""},
+		{header: "pt-BR", want: "por"},
+		{header: "en;q=0.5, ja;q=0.9, *;q=0.1", want: "jpn"},
+		{header: "nl, en", want: "nld"},
+		{header: "fr;q=0, de", want: "deu"},
+		{header: "xx-YY, *", want: ""},
+	}
+	for _, tt := range headers {
+		r := httptest.NewRequest("GET", "/work/1", nil)
+		r.Header.Set("Accept-Language", tt.header)
+		assert.Equal(t, tt.want, acceptLanguage(r), tt.header)
+	}
+
+	books := []bookResource{
+		{ForeignID: 1, Language: "eng"},
+		{ForeignID: 2, Language: "fra"},
+		{ForeignID: 3, Language: "eng"},
+		{ForeignID: 4, Language: "fra"},
+	}
+	preferLanguage(books, "fra")
+	ids := []int64{}
+	for _, b := range books {
+		ids = append(ids, b.ForeignID)
+	}
+	assert.Equal(t, []int64{2, 4, 1, 3}, ids)
+}
+
+func TestNormalizeLanguage(t *testing.T) {
+	tests := []struct {
+		given string
+		want  string
+	}{
+		{given: "", want: ""},
+		{given: "English", want: "eng"},
+		{given: "eng", want: "eng"},
+		{given: "ENG", want: "eng"},
+		{given: "Brazilian Portuguese", want: "por"},
+		{given: "Simplified Chinese", want: "zho"},
+		{given: "Traditional Chinese", want: "zho"},
+		{given: "Spanish; Castilian", want: "spa"},
+		{given: "English (US)", want: "eng"},
+		{given: "pt-BR", want: "por"},
+		{given: "Norwegian Bokmal", want: "nob"},
+		{given: "Klingon", want: "Klingon"}, // Unmapped.
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, normalizeLanguage(tt.given), tt.given)
+	}
+
+	t.Cleanup(func() { SetLanguageOverrides(nil) })
+	SetLanguageOverrides(map[string]string{"Klingon": "tlh", "NOB": "nor"})
+
+	assert.Equal(t, "tlh", normalizeLanguage("Klingon"))
+	assert.Equal(t, "nor", normalizeLanguage("Norwegian Bokmal"))
+	assert.Equal(t, "nor", normalizeLanguage("nob"))
+	assert.Equal(t, "nor", languageCode("nb"))
+	assert.Equal(t, "eng", normalizeLanguage("English"))
+}
+
+func TestCanonicalLocation(t *testing.T) {
+	// Merged-away IDs should point clients at the resource actually served.
+
+	ctx := t.Context()
+	cache := newMemoryCache()
+	authorBytes, err := json.Marshal(AuthorResource{ForeignID: 2})
+	require.NoError(t, err)
+	cache.Set(ctx, AuthorKey(2), authorBytes, time.Hour)
+
+	ctrl, err := NewController(cache, NewMockgetter(gomock.NewController(t)), nil, nil, WithAuthorAliases(map[int64]int64{1: 2}))
+	require.NoError(t, err)
+	mux := NewMux(NewHandler(ctrl), prometheus.NewRegistry())
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest("GET", "/author/1", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "/author/2", w.Header().Get("Content-Location"))
+
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest("GET", "/author/2", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Header().Get("Content-Location"))
+}
+
+func TestEditionOrder(t *testing.T) {
+	// Editions are stored by ID but the best one should be listed first, and
+	// editions in the primary language before that.
+
+	books := []bookResource{
+		{ForeignID: 10, RatingCount: 5, ReleaseDate: "2001-01-01"},
+		{ForeignID: 20, RatingCount: 50, ReleaseDate: "1999-01-01", Language: "fra"},
+		{ForeignID: 30, RatingCount: 1, ReleaseDate: "1990-01-01"},
+		{ForeignID: 40, RatingCount: 50, ReleaseDate: "2020-01-01"},
+	}
+
+	t.Cleanup(func() {
+		_ = SetEditionOrder(EditionsByID)
+		_ = SetPrimaryLanguage("")
+	})
+	assert.Error(t, SetEditionOrder("popularity"))
+
+	tests := []struct {
+		order   EditionOrder
+		primary string
+		want    []int64
+	}{
+		{order: EditionsByID, want: []int64{10, 20, 30, 40}},
+		{order: EditionsByID, primary: "fr", want: []int64{20, 10, 30, 40}},
+		{order: EditionsByRatings, want: []int64{30, 20, 40, 10}},
+		{order: EditionsByRecency, want: []int64{30, 40, 10, 20}},
+		{order: EditionsByRecency, primary: "fr", want: []int64{20, 30, 40, 10}},
+	}
+	for _, tt := range tests {
+		require.NoError(t, SetEditionOrder(tt.order))
+		require.NoError(t, SetPrimaryLanguage(tt.primary))
+
+		work := workResource{ForeignID: 1, BestBookID: 30, Books: slices.Clone(books)}
+		orderEditions(&work)
+
+		ids := []int64{}
+		for _, b := range work.Books {
+			ids = append(ids, b.ForeignID)
+		}
+		assert.Equal(t, tt.want, ids, tt.order)
+	}
+}
+
+func TestWorkLanguage(t *testing.T) {
+	// Editions are saved in presentation order, and only reordered when the
+	// caller asks for a different language.
+
+	ctx := t.Context()
+	cache := newMemoryCache()
+	workBytes, err := json.Marshal(workResource{
+		ForeignID:  1,
+		BestBookID: 30,
+		Books: []bookResource{
+			{ForeignID: 30, Language: "eng"},
+			{ForeignID: 10, Language: "eng"},
+			{ForeignID: 20, Language: "fra"},
+		},
+	})
+	require.NoError(t, err)
+	cache.Set(ctx, WorkKey(1), workBytes, time.Hour)
+
+	ctrl, err := NewController(cache, NewMockgetter(gomock.NewController(t)), nil, nil)
+	require.NoError(t, err)
+	mux := NewMux(NewHandler(ctrl), prometheus.NewRegistry())
+
+	for query, want := range map[string][]int64{
+		"":         {30, 10, 20},
+		"?lang=fr": {20, 30, 10},
+	} {
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest("GET", "/work/1"+query, nil))
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.NotContains(t, w.Header().Values("Vary"), "Accept-Language")
+
+		var work workResource
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &work))
+		ids := []int64{}
+		for _, b := range work.Books {
+			ids = append(ids, b.ForeignID)
+		}
+		assert.Equal(t, want, ids, query)
+	}
+
+	// Accept-Language redirects to the equivalent ?lang= URL, without
+	// letting the redirect be shared.
+	r := httptest.NewRequest("GET", "/work/1?debug=1", nil)
+	r.Header.Set("Accept-Language", "fr-CH, en;q=0.5")
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, r)
+	assert.Equal(t, http.StatusSeeOther, w.Code)
+	assert.Equal(t, "/work/1?debug=1&lang=fra", w.Header().Get("Location"))
+	assert.Equal(t, "private", w.Header().Get("Cache-Control"))
+	assert.Contains(t, w.Header().Values("Vary"), "Accept-Language")
+
+	// An explicit ?lang= wins.
+	r = httptest.NewRequest("GET", "/work/1?lang=en", nil)
+	r.Header.Set("Accept-Language", "fr")
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, r)
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestBasePath(t *testing.T) {
+	// Routes are served under the base path, and only there.
+
+	ctx := t.Context()
+	cache := newMemoryCache()
+	authorBytes, err := json.Marshal(AuthorResource{ForeignID: 2})
+	require.NoError(t, err)
+	cache.Set(ctx, AuthorKey(2), authorBytes, time.Hour)
+
+	ctrl, err := NewController(cache, NewMockgetter(gomock.NewController(t)), nil, nil, WithAuthorAliases(map[int64]int64{1: 2}))
+	require.NoError(t, err)
+	h := NewHandler(ctrl)
+	h.SetBasePath("metadata/")
+	mux := NewMux(h, prometheus.NewRegistry())
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest("GET", "/metadata/author/2", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest("GET", "/metadata/author/1", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "/metadata/author/2", w.Header().Get("Content-Location"))
+
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest("POST", "/metadata/book/bulk", strings.NewReader("[1]")))
+	assert.Equal(t, http.StatusSeeOther, w.Code)
+	assert.Equal(t, "/metadata/book/bulk?id=1", w.Header().Get("Location"))
+
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest("GET", "/author/2", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	// Redirects and self-links stay under the base path.
+	bookBytes, err := json.Marshal(workResource{ForeignID: 3, Books: []bookResource{{ForeignID: 10}}, Authors: []AuthorResource{{ForeignID: 2}}})
+	require.NoError(t, err)
+	cache.Set(ctx, BookKey(10), bookBytes, time.Hour)
+	require.NoError(t, ctrl.setASIN(ctx, "B00ABC1234", 10))
+	isbn, err := parseISBN("9780306406157")
+	require.NoError(t, err)
+	require.NoError(t, ctrl.setISBN(ctx, *isbn, 10))
+
+	redirects := map[string]string{
+		"/metadata/book/10":                                            "/metadata/author/2?edition=10",
+		"/metadata/book/asin/B00ABC1234":                               "/metadata/book/10",
+		"/metadata/book/isbn/9780306406157":                            "/metadata/book/10",
+		"/metadata/resolve?url=https://www.goodreads.com/book/show/10": "/metadata/book/10",
+	}
+	for from, to := range redirects {
+		w = httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest("GET", from, nil))
+		assert.Equal(t, http.StatusSeeOther, w.Code, from)
+		assert.Equal(t, to, w.Header().Get("Location"), from)
+	}
+
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest("GET", "/metadata/docs/", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "/metadata/openapi.json")
+}
+
+func TestAuthorWorks(t *testing.T) {
+	ctx := t.Context()
+	cache := newMemoryCache()
+	work := func(id int64, title string, rating float64, count int64, date string) workResource {
+		return workResource{
+			ForeignID: id, Title: title, AverageRating: rating, RatingCount: count, ReleaseDate: date,
+			BestBookID: id * 10,
+			Books:      []bookResource{{ForeignID: id*10 + 1}, {ForeignID: id * 10}},
+			Authors:    []AuthorResource{{ForeignID: 2}},
+		}
+	}
+	authorBytes, err := json.Marshal(AuthorResource{ForeignID: 2, Works: []workResource{
+		work(1, "beta", 4.5, 10, "2001-01-01"),
+		work(2, "Alpha", 3.9, 500, "2010-01-01"),
+		work(3, "gamma", 4.5, 20, "1999-01-01"),
+	}})
+	require.NoError(t, err)
+	cache.Set(ctx, AuthorKey(2), authorBytes, time.Hour)
+
+	ctrl, err := NewController(cache, NewMockgetter(gomock.NewController(t)), nil, nil)
+	require.NoError(t, err)
+	mux := NewMux(NewHandler(ctrl), prometheus.NewRegistry())
+
+	get := func(query string) (int, AuthorWorksResource) {
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest("GET", "/author/2/works"+query, nil))
+		var result AuthorWorksResource
+		if w.Code == http.StatusOK {
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+		}
+		return w.Code, result
+	}
+	ids := func(result AuthorWorksResource) []int64 {
+		out := []int64{}
+		for _, w := range result.Works {
+			out = append(out, w.ForeignID)
+		}
+		return out
+	}
+
+	tests := map[string][]int64{
+		"":                          {1, 2, 3},
+		"?sort=rating":              {3, 1, 2},
+		"?sort=popular":             {2, 3, 1},
+		"?sort=date":                {2, 1, 3},
+		"?sort=title":               {2, 1, 3},
+		"?sort=title&size=2":        {2, 1},
+		"?sort=title&size=2&page=2": {3},
+		"?page=3&size=2":            {},
+		"?page=9223372036854775807": {},
+	}
+	for query, want := range tests {
+		code, result := get(query)
+		require.Equal(t, http.StatusOK, code, query)
+		assert.Equal(t, want, ids(result), query)
+		assert.Equal(t, 3, result.Total, query)
+	}
+
+	// Only the best edition is included, and no authors.
+	_, result := get("?size=1")
+	require.Len(t, result.Works, 1)
+	require.Len(t, result.Works[0].Books, 1)
+	assert.Equal(t, int64(10), result.Works[0].Books[0].ForeignID)
+	assert.Empty(t, result.Works[0].Authors)
+
+	// Page sizes are bounded.
+	_, result = get("?size=1000")
+	assert.Equal(t, _authorWorksMaxPageSize, result.PageSize)
+
+	for _, query := range []string{"?sort=nope", "?page=0", "?size=-1", "?page=abc"} {
+		code, _ := get(query)
+		assert.Equal(t, http.StatusBadRequest, code, query)
+	}
+}
+
+func TestCachePolicy(t *testing.T) {
+	ctx := t.Context()
+	cache := newMemoryCache()
+	authorBytes, err := json.Marshal(AuthorResource{ForeignID: 2})
+	require.NoError(t, err)
+	cache.Set(ctx, AuthorKey(2), authorBytes, time.Hour)
+
+	ctrl, err := NewController(cache, NewMockgetter(gomock.NewController(t)), nil, nil)
+	require.NoError(t, err)
+	h := NewHandler(ctrl)
+	mux := NewMux(h, prometheus.NewRegistry())
+
+	// Defaults leave max-age up to the client.
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest("GET", "/author/changed", nil))
+	assert.Equal(t, "public, s-maxage=86400", w.Header().Get("Cache-Control"))
+
+	require.NoError(t, h.SetCachePolicy("changed", CachePolicy{SMaxAge: time.Hour}))
+	require.NoError(t, h.SetCachePolicy("author", CachePolicy{MaxAge: 10 * time.Minute}))
+	assert.Error(t, h.SetCachePolicy("authors", CachePolicy{}))
+
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest("GET", "/author/changed", nil))
+	assert.Equal(t, "public, s-maxage=3600", w.Header().Get("Cache-Control"))
+
+	// The CDN still uses the author's TTL.
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest("GET", "/author/2", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Regexp(t, `^public, max-age=600, s-maxage=3[56]\d\d$`, w.Header().Get("Cache-Control"))
+}
+
+func TestDeleteAuthorBudget(t *testing.T) {
+	// Authors aren't busted unless there's room to refresh them.
+
+	ctx := t.Context()
+	cache := newMemoryCache()
+	authorBytes, err := json.Marshal(AuthorResource{ForeignID: 1, Works: []workResource{{ForeignID: 2}}})
+	require.NoError(t, err)
+	cache.Set(ctx, AuthorKey(1), authorBytes, time.Hour)
+	cache.Set(ctx, WorkKey(2), []byte(`{}`), time.Hour)
+
+	getter := NewMockgetter(gomock.NewController(t))
+	getter.EXPECT().GetAuthor(gomock.Any(), int64(1)).Return(nil, errNotFound)
+	ctrl, err := NewController(cache, getter, nil, nil, WithBackgroundWorkers(1))
+	require.NoError(t, err)
+	mux := NewMux(NewHandler(ctrl), prometheus.NewRegistry())
+
+	block := make(chan struct{})
+	require.True(t, ctrl.spawn(ctx, "test", func() { <-block }))
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest("DELETE", "/author/1?full=1", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	_, ttl, ok := cache.GetWithTTL(ctx, AuthorKey(1))
+	assert.True(t, ok)
+	assert.Positive(t, ttl)
+	_, ttl, _ = cache.GetWithTTL(ctx, WorkKey(2))
+	assert.Positive(t, ttl)
+
+	close(block)
+	assert.Eventually(t, func() bool { return ctrl.metrics.backgroundGet() == 0 }, time.Second, time.Millisecond)
+
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest("DELETE", "/author/1?full=1", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Eventually(t, func() bool { return ctrl.metrics.backgroundGet() == 0 }, time.Second, time.Millisecond)
+	_, ok = cache.Get(ctx, AuthorKey(1))
+	assert.False(t, ok)
+	_, ttl, _ = cache.GetWithTTL(ctx, WorkKey(2))
+	assert.LessOrEqual(t, ttl, time.Duration(0))
+}
+
+func TestSourceDebug(t *testing.T) {
+	// Provenance is only served when debugging, and only if the server
+	// enables debug headers.
+
+	ctx := t.Context()
+	cache := newMemoryCache()
+	authorBytes, err := json.Marshal(AuthorResource{
+		ForeignID:   1,
+		Description: `Says "Source":"hi", quoted`,
+		Works:       []workResource{{ForeignID: 2, Source: _sourceHardcover}},
+		Source:      _sourceGR,
+	})
+	require.NoError(t, err)
+	cache.Set(ctx, AuthorKey(1), authorBytes, time.Hour)
+
+	ctrl, err := NewController(cache, NewMockgetter(gomock.NewController(t)), nil, nil)
+	require.NoError(t, err)
+	h := NewHandler(ctrl)
+	mux := NewMux(h, prometheus.NewRegistry())
+
+	// Clients can't ask for debugging unless the server allows it.
+	for _, path := range []string{"/author/1", "/author/1?debug=1"} {
+		var author AuthorResource
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.NotContains(t, w.Body.String(), `"Source":"gr"`, path)
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &author))
+		assert.Empty(t, author.Source, path)
+		assert.Empty(t, author.Works[0].Source, path)
+		assert.Equal(t, `Says "Source":"hi", quoted`, author.Description)
+		assert.Empty(t, w.Header().Get("X-Cache-TTL"), path)
+	}
+
+	h.SetDebugHeaders(true)
+	var author AuthorResource
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest("GET", "/author/1?debug=1", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	ttl, err := strconv.Atoi(w.Header().Get("X-Cache-TTL"))
+	require.NoError(t, err)
+	assert.InDelta(t, time.Hour.Seconds(), ttl, 5)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &author))
+	assert.Equal(t, _sourceGR, author.Source)
+	assert.Equal(t, _sourceHardcover, author.Works[0].Source)
+}
+
+func TestRawResponses(t *testing.T) {
+	// Raw upstream responses are kept alongside mapped resources when
+	// enabled.
+
+	cache := newMemoryCache()
+
+	gql := hardcover.NewMockgql(gomock.NewController(t))
+	gql.EXPECT().MakeRequest(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, req *graphql.Request, res *graphql.Response) error {
+			gaw := res.Data.(*hardcover.GetAuthorEditionsResponse)
+			gaw.Authors_by_pk.AuthorInfo = hardcover.AuthorInfo{Id: 1, Name: "Upstream Name"}
+			return nil
+		}).Times(1)
+
+	getter, err := NewHardcoverGetter(cache, gql, WithEmptyAuthors(true), WithRawResponses(time.Hour))
+	require.NoError(t, err)
+	_, err = getter.GetAuthor(t.Context(), 1)
+	require.NoError(t, err)
+
+	ctrl, err := NewController(cache, getter, nil, nil)
+	require.NoError(t, err)
+	mux := NewMux(NewHandler(ctrl), prometheus.NewRegistry())
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest("GET", "/debug/raw/a1", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"name":"Upstream Name"`)
+	assert.Equal(t, "no-store", w.Header().Get("Cache-Control"))
+
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest("GET", "/debug/raw/a2", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestResolve(t *testing.T) {
+	gql := hardcover.NewMockgql(gomock.NewController(t))
+	gql.EXPECT().MakeRequest(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, req *graphql.Request, res *graphql.Response) error {
+			vars := req.Variables.(interface{ GetSlug() string })
+			resp := res.Data.(*hardcover.GetWorkBySlugResponse)
+			if vars.GetSlug() == "the-hobbit" {
+				resp.Books = []hardcover.GetWorkBySlugBooks{{Id: 379631}}
+			}
+			return nil
+		}).AnyTimes()
+	hc, err := NewHardcoverGetter(newMemoryCache(), gql)
+	require.NoError(t, err)
+
+	hcCtrl, err := NewController(newMemoryCache(), hc, nil, nil)
+	require.NoError(t, err)
+	gr, err := NewGRGetter(newMemoryCache(), nil, nil)
+	require.NoError(t, err)
+	grCtrl, err := NewController(newMemoryCache(), gr, nil, nil)
+	require.NoError(t, err)
+
+	tests := []struct {
+		ctrl     *Controller
+		given    string
+		wantCode int
+		wantLoc  string
+	}{
+		{grCtrl, "https://www.goodreads.com/book/show/27362503-it-ends-with-us", http.StatusSeeOther, "/book/27362503"},
+		{grCtrl, "www.goodreads.com/book/show/27362503.It_Ends_with_Us?from_search=true", http.StatusSeeOther, "/book/27362503"},
+		{grCtrl, "https://goodreads.com/work/editions/1883478-it-ends-with-us", http.StatusSeeOther, "/work/1883478"},
+		{hcCtrl, "https://hardcover.app/books/the-hobbit", http.StatusSeeOther, "/work/379631"},
+		{hcCtrl, "https://hardcover.app/books/the-hobbit/editions/30405274", http.StatusSeeOther, "/book/30405274"},
+		{hcCtrl, "https://hardcover.app/books/missing", http.StatusNotFound, ""},
+		{grCtrl, "https://www.goodreads.com/author/show/1234.Someone", http.StatusBadRequest, ""},
+		{grCtrl, "https://www.goodreads.com/book/show/not-a-number", http.StatusBadRequest, ""},
+		{grCtrl, "", http.StatusBadRequest, ""},
+
+		// IDs aren't shared between upstreams, so URLs from the other one
+		// are rejected instead of resolving to an unrelated book.
+		{grCtrl, "https://hardcover.app/books/the-hobbit", http.StatusBadRequest, ""},
+		{grCtrl, "https://hardcover.app/books/the-hobbit/editions/30405274", http.StatusBadRequest, ""},
+		{hcCtrl, "https://www.goodreads.com/book/show/27362503-it-ends-with-us", http.StatusBadRequest, ""},
+		{hcCtrl, "https://www.goodreads.com/work/editions/1883478-it-ends-with-us", http.StatusBadRequest, ""},
+		{grCtrl, "https://notgoodreads.com/book/show/27362503", http.StatusBadRequest, ""},
+	}
+	for _, tt := range tests {
+		mux := NewMux(NewHandler(tt.ctrl), prometheus.NewRegistry())
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest("GET", "/resolve?url="+url.QueryEscape(tt.given), nil))
+		assert.Equal(t, tt.wantCode, w.Code, tt.given)
+		if tt.wantLoc != "" {
+			assert.Equal(t, tt.wantLoc, w.Header().Get("Location"), tt.given)
+		}
+	}
+}
+
+func TestBulkCanonicalOrder(t *testing.T) {
+	getter := NewMockgetter(gomock.NewController(t))
+	getter.EXPECT().GetBook(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, 0, 0, errNotFound).AnyTimes()
+	ctrl, err := NewController(newMemoryCache(), getter, nil, nil)
+	require.NoError(t, err)
+	mux := NewMux(NewHandler(ctrl), prometheus.NewRegistry())
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest("POST", "/book/bulk", strings.NewReader("[3,1,3,2]")))
+	assert.Equal(t, http.StatusSeeOther, w.Code)
+	assert.Equal(t, "/book/bulk?id=1&id=2&id=3", w.Header().Get("Location"))
+
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest("GET", "/book/bulk?id=2&id=1&id=2", nil))
+	assert.Equal(t, http.StatusSeeOther, w.Code)
+	assert.Equal(t, "/book/bulk?id=1&id=2", w.Header().Get("Location"))
+
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest("GET", "/book/bulk?id=1&id=2", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestBulkTimeout(t *testing.T) {
+	// A hung book doesn't hold up the rest of a bulk request.
+	ctx := t.Context()
+
+	cache := newMemoryCache()
+	workBytes, err := json.Marshal(workResource{
+		ForeignID: 10,
+		Books:     []bookResource{{ForeignID: 1}},
+		Authors:   []AuthorResource{{ForeignID: 100}},
+	})
+	require.NoError(t, err)
+	cache.Set(ctx, BookKey(1), workBytes, time.Hour)
+
+	release := make(chan struct{})
+	t.Cleanup(func() { close(release) })
+	getter := NewMockgetter(gomock.NewController(t))
+	getter.EXPECT().GetBook(gomock.Any(), int64(2), gomock.Any()).DoAndReturn(
+		func(context.Context, int64, editionsCallback) ([]byte, int64, int64, error) {
+			<-release
+			return nil, 0, 0, errNotFound
+		}).AnyTimes()
+
+	ctrl, err := NewController(cache, getter, nil, nil)
+	require.NoError(t, err)
+	h := NewHandler(ctrl)
+	h.SetBulkTimeout(50 * time.Millisecond)
+
+	w := httptest.NewRecorder()
+	NewMux(h, prometheus.NewRegistry()).ServeHTTP(w, httptest.NewRequest("GET", "/book/bulk?id=1&id=2", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "no-store", w.Header().Get("Cache-Control"))
+
+	var result bulkBookResource
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+	require.Len(t, result.Works, 1)
+	assert.Equal(t, int64(10), result.Works[0].ForeignID)
+	require.Len(t, result.Authors, 1)
+	assert.Equal(t, int64(100), result.Authors[0].ForeignID)
+}
+
+func TestBulkIdentifiers(t *testing.T) {
+	// ISBNs and ASINs resolve to editions, with a status for each.
+	ctx := t.Context()
+
+	cache := newMemoryCache()
+	workBytes, err := json.Marshal(workResource{
+		ForeignID: 10,
+		Books:     []bookResource{{ForeignID: 1}},
+		Authors:   []AuthorResource{{ForeignID: 100}},
+	})
+	require.NoError(t, err)
+	cache.Set(ctx, BookKey(1), workBytes, time.Hour)
+
+	getter := NewMockgetter(gomock.NewController(t))
+	getter.EXPECT().Search(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, query string) ([]SearchResource, error) {
+			switch query {
+			case "9780441172719":
+				return []SearchResource{{BookID: 1, WorkID: 10}}, nil
+			case "9780316769488":
+				return []SearchResource{{BookID: 2, WorkID: 20}}, nil
+			}
+			return nil, nil
+		}).AnyTimes()
+	getter.EXPECT().GetBook(gomock.Any(), int64(2), gomock.Any()).Return(nil, 0, 0, errNotFound).AnyTimes()
+
+	ctrl, err := NewController(cache, getter, nil, nil)
+	require.NoError(t, err)
+	mux := NewMux(NewHandler(ctrl), prometheus.NewRegistry())
+
+	w := httptest.NewRecorder()
+	body := `["9780441172719", "9780441172719", "9780316769488", "B000000000", "nope"]`
+	mux.ServeHTTP(w, httptest.NewRequest("POST", "/book/bulk", strings.NewReader(body)))
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "no-store", w.Header().Get("Cache-Control"))
+
+	var result bulkBookResource
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+	require.Len(t, result.Works, 1)
+	assert.Equal(t, int64(10), result.Works[0].ForeignID)
+	assert.Equal(t, []bulkIdentifierResource{
+		{Identifier: "9780441172719", Status: http.StatusOK, ForeignEditionID: 1},
+		{Identifier: "9780316769488", Status: http.StatusNotFound, ForeignEditionID: 2}, // Resolved but not loaded.
+		{Identifier: "B000000000", Status: http.StatusNotFound},
+		{Identifier: "nope", Status: http.StatusBadRequest},
+	}, result.Identifiers)
+
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest("POST", "/book/bulk", strings.NewReader(`[]`)))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestBulkIdentifiersSize(t *testing.T) {
+	// A large library's worth of ISBNs makes it through all of our
+	// middleware, while oversized bodies are rejected.
+	getter := NewMockgetter(gomock.NewController(t))
+	getter.EXPECT().Search(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+
+	ctrl, err := NewController(newMemoryCache(), getter, nil, nil)
+	require.NoError(t, err)
+	mux := Middleware(NewMux(NewHandler(ctrl), prometheus.NewRegistry()))
+
+	isbns := make([]string, 0, 3000)
+	for i := range cap(isbns) {
+		digits := fmt.Sprintf("978%09d", i)
+		sum := 0
+		for j, d := range digits {
+			sum += int(d-'0') * (1 + 2*(j%2))
+		}
+		isbns = append(isbns, fmt.Sprintf("%s%d", digits, (10-sum%10)%10))
+	}
+	body, err := json.Marshal(isbns)
+	require.NoError(t, err)
+	require.Greater(t, len(body), 32<<10)
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest("POST", "/book/bulk", bytes.NewReader(body)))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var result bulkBookResource
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+	require.Len(t, result.Identifiers, len(isbns))
+	for _, s := range result.Identifiers {
+		assert.Equal(t, http.StatusNotFound, s.Status, s.Identifier)
+	}
+
+	w = httptest.NewRecorder()
+	huge := `["` + strings.Repeat("9780441172719", int(_maxBulkRequestBytes)) + `"]`
+	mux.ServeHTTP(w, httptest.NewRequest("POST", "/book/bulk", strings.NewReader(huge)))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+}
+
+// staleCache pretends its stale values expired an hour ago.
+type staleCache struct {
+	cache[[]byte]
+	stale map[string][]byte
+}
+
+func (c staleCache) GetStale(_ context.Context, key string) ([]byte, time.Duration, bool) {
+	val, ok := c.stale[key]
+	return val, time.Hour, ok
+}
+
+func TestServeStale(t *testing.T) {
+	workBytes, err := json.Marshal(workResource{ForeignID: 1, Title: "stale"})
+	require.NoError(t, err)
+	cache := staleCache{cache: newMemoryCache(), stale: map[string][]byte{WorkKey(1): workBytes}}
+
+	getter := NewMockgetter(gomock.NewController(t))
+	getter.EXPECT().GetWork(gomock.Any(), int64(1), gomock.Any()).Return(nil, 0, statusErr(http.StatusServiceUnavailable)).AnyTimes()
+	getter.EXPECT().GetWork(gomock.Any(), int64(2), gomock.Any()).Return(nil, 0, errNotFound).AnyTimes()
+
+	get := func(maxStale time.Duration, path string) *httptest.ResponseRecorder {
+		ctrl, err := NewController(cache, getter, nil, nil, WithServeStale(maxStale))
+		require.NoError(t, err)
+		w := httptest.NewRecorder()
+		NewMux(NewHandler(ctrl), prometheus.NewRegistry()).ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+		return w
+	}
+
+	// Disabled by default.
+	w := get(0, "/work/1")
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+
+	w = get(2*time.Hour, "/work/1")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"stale"`)
+	assert.NotEmpty(t, w.Header().Get("Warning"))
+	assert.Empty(t, w.Header().Get("Cache-Control"))
+
+	// Too stale.
+	w = get(30*time.Minute, "/work/1")
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+
+	// Missing resources aren't an outage.
+	cache.stale[WorkKey(2)] = workBytes
+	w = get(2*time.Hour, "/work/2")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestTrusted(t *testing.T) {
+	h := NewHandler(nil)
+	require.NoError(t, h.SetAdminNetworks([]string{"10.0.0.0/8", "fd00::/8"}))
+	assert.Error(t, h.SetAdminNetworks([]string{"10.0.0.0"}))
+
+	tests := map[string]bool{
+		"127.0.0.1:1234":       true,
+		"[::1]:1234":           true,
+		"10.1.2.3:1234":        true,
+		"[::ffff:10.0.0.1]:80": true,
+		"[fd00::1]:1234":       true,
+		"172.16.0.1:1234":      false,
+		"192.168.1.1:1234":     false,
+		"203.0.113.1:1234":     false,
+		"[2001:db8::1]:1234":   false,
+	}
+	for addr, want := range tests {
+		t.Run(addr, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/", nil)
+			r.RemoteAddr = addr
+			ok, err := h.trusted(r)
+			require.NoError(t, err)
+			assert.Equal(t, want, ok)
+		})
+	}
+
+	t.Run("default", func(t *testing.T) {
+		r := httptest.NewRequest("GET", "/", nil)
+		r.RemoteAddr = "10.1.2.3:1234"
+		ok, err := NewHandler(nil).trusted(r)
+		require.NoError(t, err)
+		assert.False(t, ok)
+	})
+}
@@ -6,7 +6,6 @@ import (
 	"errors"
 	"fmt"
 	"iter"
-	"maps"
 	"slices"
 	"strings"
 	"sync"
@@ -21,20 +20,27 @@ import (
 // attempts to minimize upstream HEAD requests (to resolve book/work IDs) by
 // relying on HC's raw external data.
 type HCGetter struct {
+	getterOptions
+
 	cache cache[[]byte]
 	gql   graphql.Client
 }
 
 var _ getter = (*HCGetter)(nil)
 
+// _sourceHardcover identifies resources produced by HCGetter.
+const _sourceHardcover = "hardcover"
+
 // NewHardcoverGetter returns a new Getter backed by Hardcover.
-func NewHardcoverGetter(cache cache[[]byte], gql graphql.Client) (*HCGetter, error) {
-	return &HCGetter{cache: cache, gql: gql}, nil
+func NewHardcoverGetter(cache cache[[]byte], gql graphql.Client, opts ...GetterOption) (*HCGetter, error) {
+	return &HCGetter{getterOptions: newGetterOptions(opts...), cache: cache, gql: gql}, nil
 }
 
 // Search hits the GraphQL endpoint to fetch relevant work IDs and then fetches
 // those in order to return the necessary edition and author IDs to the client.
-func (g *HCGetter) Search(ctx context.Context, query string) ([]SearchResource, error) {
+func (g *HCGetter) Search(ctx context.Context, query string) (_ []SearchResource, err error) {
+	defer func() { g.metrics.resultInc("Search", err) }()
+
 	workIDs := []int64{}
 
 	// Try a lookup by ASIN/ISBN if the query looks like one
@@ -89,6 +95,9 @@ func (g *HCGetter) Search(ctx context.Context, query string) ([]SearchResource,
 				Author: SearchResourceAuthor{
 					ID: workRsc.Authors[0].ForeignID,
 				},
+				title:       workRsc.ShortTitle,
+				ratingCount: workRsc.RatingCount,
+				releaseDate: workRsc.ReleaseDate,
 			})
 		})
 	}
@@ -99,10 +108,15 @@ func (g *HCGetter) Search(ctx context.Context, query string) ([]SearchResource,
 }
 
 // GetWork returns the canonical edition for a work.
-func (g *HCGetter) GetWork(ctx context.Context, workID int64, saveEditions editionsCallback) ([]byte, int64, error) {
+func (g *HCGetter) GetWork(ctx context.Context, workID int64, saveEditions editionsCallback) (_ []byte, _ int64, err error) {
+	defer func() { g.metrics.resultInc("GetWork", err) }()
+
 	if workID == 0 {
 		return nil, 0, errors.Join(errBadRequest, errors.New("work ID missing"))
 	}
+	if err := poisoned(g.poisonWorks, workID); err != nil {
+		return nil, 0, err
+	}
 
 	workBytes, ttl, ok := g.cache.GetWithTTL(ctx, WorkKey(workID))
 	if ok && ttl > 0 {
@@ -111,10 +125,11 @@ func (g *HCGetter) GetWork(ctx context.Context, workID int64, saveEditions editi
 
 	Log(ctx).Debug("getting work", "workID", workID)
 
-	resp, err := hardcover.GetWork(ctx, g.gql, workID)
+	resp, err := hardcover.GetWork(ctx, g.gql, workID, g.audioNarrators)
 	if err != nil {
 		return nil, 0, fmt.Errorf("getting work: %w", err)
 	}
+	g.keepRaw(ctx, g.cache, WorkKey(workID), resp)
 
 	if resp.Books_by_pk.Id == 0 {
 		return nil, 0, errors.Join(errNotFound, fmt.Errorf("invalid work info"))
@@ -125,14 +140,25 @@ func (g *HCGetter) GetWork(ctx context.Context, workID int64, saveEditions editi
 	}
 
 	if saveEditions != nil {
-		editions := map[editionDedupe]workResource{}
+		editions := map[editionDedupe]dedupedEdition{}
 		for _, e := range resp.Books_by_pk.Editions {
+			if g.skipEbooks && isEbook(e.Edition_format) {
+				continue
+			}
 			key := editionDedupe{
 				title:    strings.ToUpper(e.Title),
-				language: e.Language.Code3,
-				audio:    e.Audio_seconds != 0,
+				language: normalizeLanguage(e.Language.Code3),
+				audio:    e.Audio_seconds != 0 || g.isAudio(e.Edition_format),
+			}
+			if key.audio && g.audioNarrators {
+				key.narrator = hcNarrator(hardcover.AsContributions(e.Contributions))
+			}
+			if g.allEditions {
+				key.id = e.Id
 			}
-			if _, ok := editions[key]; ok {
+			// Ratings are per-work, so readers are the best signal we have.
+			candidate := dedupedEdition{id: e.Id, popularity: e.Users_read_count}
+			if kept, ok := editions[key]; ok && !kept.replacedBy(candidate, key) {
 				continue // Already saw an edition similar to this one.
 			}
 
@@ -140,9 +166,10 @@ func (g *HCGetter) GetWork(ctx context.Context, workID int64, saveEditions editi
 			if err != nil {
 				continue
 			}
-			editions[key] = work
+			candidate.work = withNarrator(work, key.narrator)
+			editions[key] = candidate
 		}
-		saveEditions(slices.Collect(maps.Values(editions))...)
+		saveEditions(dedupedWorks(editions)...)
 	}
 
 	author, err := bestAuthor(hardcover.AsContributions(resp.Books_by_pk.Contributions))
@@ -157,10 +184,15 @@ func (g *HCGetter) GetWork(ctx context.Context, workID int64, saveEditions editi
 }
 
 // GetBook looks up a GR book (edition) in Hardcover's mappings.
-func (g *HCGetter) GetBook(ctx context.Context, editionID int64, _ editionsCallback) ([]byte, int64, int64, error) {
+func (g *HCGetter) GetBook(ctx context.Context, editionID int64, _ editionsCallback) (_ []byte, _ int64, _ int64, err error) {
+	defer func() { g.metrics.resultInc("GetBook", err) }()
+
 	if editionID == 0 {
 		return nil, 0, 0, errors.Join(errBadRequest, errors.New("edition missing ID"))
 	}
+	if err := poisoned(g.poisonBooks, editionID); err != nil {
+		return nil, 0, 0, err
+	}
 
 	workBytes, ttl, ok := g.cache.GetWithTTL(ctx, BookKey(editionID))
 	if ok && ttl > 0 {
@@ -173,6 +205,7 @@ func (g *HCGetter) GetBook(ctx context.Context, editionID int64, _ editionsCallb
 	if err != nil {
 		return nil, 0, 0, fmt.Errorf("getting book: %w", err)
 	}
+	g.keepRaw(ctx, g.cache, BookKey(editionID), resp)
 	work := resp.Editions_by_pk.Book.WorkInfo
 
 	if work.Id == 0 {
@@ -181,7 +214,7 @@ func (g *HCGetter) GetBook(ctx context.Context, editionID int64, _ editionsCallb
 
 	workRsc, err := mapHardcoverToWorkResource(ctx, resp.Editions_by_pk.EditionInfo, work)
 	if err != nil {
-		return nil, 0, 0, fmt.Errorf("mapping for book: %w", err)
+		return nil, 0, 0, errors.Join(errInvalid, fmt.Errorf("mapping for book: %w", err))
 	}
 	out, err := json.Marshal(workRsc)
 	if err != nil {
@@ -190,7 +223,7 @@ func (g *HCGetter) GetBook(ctx context.Context, editionID int64, _ editionsCallb
 
 	if len(workRsc.Authors) == 0 {
 		Log(ctx).Warn("missing author", "editionID", editionID)
-		return nil, 0, 0, errors.Join(errNotFound, errors.New("missing author"))
+		return nil, 0, 0, errors.Join(errNotFound, errInvalid, errors.New("missing author"))
 	}
 
 	return out, workRsc.ForeignID, workRsc.Authors[0].ForeignID, nil
@@ -210,9 +243,7 @@ func mapHardcoverToWorkResource(ctx context.Context, edition hardcover.EditionIn
 	for _, t := range tags {
 		genres = append(genres, t.Tag)
 	}
-	if len(genres) == 0 {
-		genres = []string{"none"}
-	}
+	genres = withGenrePlaceholder(genres)
 
 	series := []SeriesResource{}
 	for _, s := range work.Book_series {
@@ -230,10 +261,7 @@ func mapHardcoverToWorkResource(ctx context.Context, edition hardcover.EditionIn
 		})
 	}
 
-	editionDescription := work.Description // edition.Description is no longer populated.
-	if editionDescription == "" {
-		editionDescription = "N/A" // Must be set?
-	}
+	editionDescription := description(work.Description) // edition.Description is no longer populated.
 
 	editionTitle := edition.Title
 	editionFullTitle := editionTitle
@@ -253,20 +281,23 @@ func mapHardcoverToWorkResource(ctx context.Context, edition hardcover.EditionIn
 
 		FullTitle:          editionFullTitle,
 		ShortTitle:         editionTitle,
-		Language:           edition.Language.Code3,
+		Language:           normalizeLanguage(edition.Language.Code3),
 		Format:             edition.Edition_format,
-		EditionInformation: edition.Edition_information, // TODO: Is this used anywhere?
-		Publisher:          edition.Publisher.Name,      // TODO: Ignore books without publishers?
+		EditionInformation: withEditionInformation(edition.Edition_information),
+		Publisher:          edition.Publisher.Name, // TODO: Ignore books without publishers?
 		ImageURL:           strings.ReplaceAll(string(work.Cached_image), `"`, ``),
-		IsEbook:            edition.Edition_format == "ebook" || edition.Edition_format == "Kindle Edition",
+		IsEbook:            isEbook(edition.Edition_format),
 		NumPages:           edition.Pages,
 		RatingCount:        work.Ratings_count,
 		RatingSum:          int64(float64(work.Ratings_count) * work.Rating),
-		AverageRating:      work.Rating,
+		AverageRating:      averageRating(work.Ratings_count, work.Rating),
 		URL:                "https://hardcover.app/books/" + work.Slug,
 		ReleaseDate:        hcReleaseDate(edition.Release_date),
 		ReleaseDateRaw:     edition.Release_date,
 
+		PhysicalFormat:      edition.Physical_format,
+		PhysicalInformation: edition.Physical_information,
+
 		// TODO: Grab release date from book if absent
 
 		// TODO: Omitting release date is a way to essentially force R to hide
@@ -279,10 +310,7 @@ func mapHardcoverToWorkResource(ctx context.Context, edition hardcover.EditionIn
 		return workResource{}, err
 	}
 
-	authorDescription := "N/A" // Must be set?
-	if author.Bio != "" {
-		authorDescription = author.Bio
-	}
+	authorDescription := description(author.Bio)
 
 	authorRsc := AuthorResource{
 		Name:        author.Name,
@@ -291,6 +319,8 @@ func mapHardcoverToWorkResource(ctx context.Context, edition hardcover.EditionIn
 		ImageURL:    strings.ReplaceAll(string(author.Cached_image), `"`, ``),
 		Description: authorDescription,
 		Series:      series, // TODO:: Doesn't fully work yet #17.
+		Aliases:     pseudonyms(hardcover.AsContributions(work.Contributions), author.Id),
+		Source:      _sourceHardcover,
 	}
 
 	workTitle := work.Title
@@ -317,10 +347,15 @@ func mapHardcoverToWorkResource(ctx context.Context, edition hardcover.EditionIn
 
 		RatingCount:   work.Ratings_count,
 		RatingSum:     int64(float64(work.Ratings_count) * work.Rating),
-		AverageRating: work.Rating,
+		AverageRating: averageRating(work.Ratings_count, work.Rating),
+
+		Source: _sourceHardcover,
 	}
 
 	bookRsc.Contributors = []contributorResource{{ForeignID: author.Id, Role: "Author"}}
+	for _, c := range hardcover.AsContributions(work.Contributions) {
+		bookRsc.contributorIDs = append(bookRsc.contributorIDs, c.Author.Id)
+	}
 	authorRsc.Works = []workResource{workRsc}
 	workRsc.Authors = []AuthorResource{authorRsc}
 	workRsc.Books = []bookResource{bookRsc} // TODO: Add best book here as well?
@@ -328,7 +363,10 @@ func mapHardcoverToWorkResource(ctx context.Context, edition hardcover.EditionIn
 	return workRsc, nil
 }
 
-// GetAuthorBooks returns all GR book (edition) IDs.
+// GetAuthorBooks returns an author's best edition IDs, paging through their
+// works from most to least popular. The controller stops iterating once it
+// hits --max-author-works, at which point no further pages are requested, so
+// large authors keep their most popular works.
 func (g *HCGetter) GetAuthorBooks(ctx context.Context, authorID int64) iter.Seq[int64] {
 	return func(yield func(int64) bool) {
 		limit, offset := int64(100), int64(0)
@@ -361,17 +399,49 @@ func (g *HCGetter) GetAuthorBooks(ctx context.Context, authorID int64) iter.Seq[
 				}
 			}
 
+			if int64(len(editions.Authors_by_pk.Contributions)) < limit {
+				break // Last page, no need to ask for another.
+			}
+
 			offset += limit
 		}
 	}
 }
 
-// Recommendations returns trending work IDs from the past week.
-func (g *HCGetter) Recommendations(ctx context.Context, page int64) (RecommentationsResource, error) {
+// Recommendations returns trending work IDs from the past week, or the most
+// popular works in a genre.
+func (g *HCGetter) Recommendations(ctx context.Context, page int64, filter recommendationsFilter) (RecommentationsResource, error) {
+	if err := checkPage(page); err != nil {
+		return RecommentationsResource{}, err
+	}
 	now := time.Now()
 	lastWeek := now.Add(-7 * 24 * time.Hour)
-	if page < 1 {
-		return RecommentationsResource{}, fmt.Errorf("page must be gte 1")
+
+	genre := filter.genre
+	if filter.seed != nil {
+		// Hardcover doesn't expose similar works, so approximate them with
+		// popular works from the seed's first genre.
+		genre = ""
+		for _, name := range filter.seed.Genres {
+			if name != _genrePlaceholder {
+				genre = name
+				break
+			}
+		}
+		if genre == "" {
+			return RecommentationsResource{WorkIDs: []int64{}}, nil
+		}
+	}
+	if genre != "" {
+		resp, err := hardcover.GetGenreRecommended(ctx, g.gql, genre, 100, 100*(page-1))
+		if err != nil {
+			return RecommentationsResource{}, fmt.Errorf("getting genre recommendations: %w", err)
+		}
+		result := RecommentationsResource{WorkIDs: []int64{}}
+		for _, b := range resp.Books {
+			result.WorkIDs = append(result.WorkIDs, b.Id)
+		}
+		return result, nil
 	}
 
 	recommended, err := hardcover.GetRecommended(ctx, g.gql, lastWeek.String(), now.String(), 100, 100*(page-1))
@@ -388,44 +458,101 @@ func bestHardcoverEdition(defaults hardcover.DefaultEditions, expectedAuthorID i
 		Log(context.TODO()).Warn("no author", "workID", defaults.Id)
 		return 0
 	}
-	if expectedAuthorID != 0 && expectedAuthorID != author.Id {
-		Log(context.TODO()).Warn("author mismatch", "expected", expectedAuthorID, "got", author.Id, "workID", defaults.Id)
-		return 0
+	// If the work is credited to someone else, e.g. a co-author, we can
+	// still use an edition credited to the author we expect.
+	authorID := author.Id
+	mismatched := expectedAuthorID != 0 && expectedAuthorID != author.Id
+	if mismatched {
+		authorID = expectedAuthorID
 	}
 
-	cover := defaults.Default_cover_edition
-	if cover.Id != 0 {
-		coverAuthor, _ := bestAuthor(hardcover.AsContributions(cover.Contributions))
-		if coverAuthor.Id == author.Id {
-			return cover.Id
+	type candidate struct {
+		id           int64
+		pages        int64
+		audioSeconds int64
+		language     string
+		image        json.RawMessage
+	}
+
+	// Default editions in order of preference, limited to those by the
+	// work's author.
+	candidates := []candidate{}
+	consider := func(c candidate, contributions any) {
+		if c.id == 0 {
+			return
+		}
+		if a, _ := bestAuthor(hardcover.AsContributions(contributions)); a.Id != authorID {
+			return
 		}
+		candidates = append(candidates, c)
 	}
 
+	cover := defaults.Default_cover_edition
+	consider(candidate{cover.Id, cover.Pages, cover.Audio_seconds, normalizeLanguage(cover.Language.Code3), cover.Cached_image}, cover.Contributions)
+
 	ebook := defaults.Default_ebook_edition
-	if ebook.Id != 0 {
-		ebookAuthor, _ := bestAuthor(hardcover.AsContributions(ebook.Contributions))
-		if ebookAuthor.Id == author.Id {
-			return ebook.Id
+	consider(candidate{ebook.Id, ebook.Pages, ebook.Audio_seconds, normalizeLanguage(ebook.Language.Code3), ebook.Cached_image}, ebook.Contributions)
+
+	audio := defaults.Default_cover_edition
+	consider(candidate{audio.Id, audio.Pages, audio.Audio_seconds, normalizeLanguage(audio.Language.Code3), audio.Cached_image}, audio.Contributions)
+
+	physical := defaults.Default_physical_edition
+	consider(candidate{physical.Id, physical.Pages, physical.Audio_seconds, normalizeLanguage(physical.Language.Code3), physical.Cached_image}, physical.Contributions)
+
+	// All else equal, an edition with a cover beats one without.
+	slices.SortStableFunc(candidates, func(a, b candidate) int {
+		switch {
+		case hasImage(a.image) == hasImage(b.image):
+			return 0
+		case hasImage(a.image):
+			return -1
+		default:
+			return 1
+		}
+	})
+
+	first := func(ok func(candidate) bool) int64 {
+		for _, c := range candidates {
+			if ok(c) {
+				return c.id
+			}
 		}
+		return 0
 	}
 
-	audio := defaults.Default_cover_edition
-	if audio.Id != 0 {
-		audioAuthor, _ := bestAuthor(hardcover.AsContributions(audio.Contributions))
-		if audioAuthor.Id == author.Id {
-			return audio.Id
+	// Prefer a real edition in the primary language, then any real edition.
+	// Stubs are only used if nothing better turns up.
+	if _primaryLanguage != "" {
+		if id := first(func(c candidate) bool {
+			return c.language == _primaryLanguage && !stubEdition(c.pages, c.audioSeconds)
+		}); id != 0 {
+			return id
 		}
 	}
+	if id := first(func(c candidate) bool { return !stubEdition(c.pages, c.audioSeconds) }); id != 0 {
+		return id
+	}
+	stub := first(func(candidate) bool { return true })
 
-	physical := defaults.Default_physical_edition
-	if physical.Id != 0 {
-		physicalAuthor, _ := bestAuthor(hardcover.AsContributions(physical.Contributions))
-		if physicalAuthor.Id == author.Id {
-			return physical.Id
+	if mismatched {
+		for _, fallback := range defaults.Fallback {
+			if a, _ := bestAuthor(hardcover.AsContributions(fallback.Contributions)); a.Id != authorID {
+				continue
+			}
+			if stub == 0 || !stubEdition(fallback.Pages, fallback.Audio_seconds) {
+				return fallback.Id
+			}
+		}
+		if stub == 0 {
+			Log(context.TODO()).Warn("author mismatch", "expected", expectedAuthorID, "got", author.Id, "workID", defaults.Id)
 		}
+		return stub
 	}
 
 	if len(defaults.Fallback) == 0 {
+		if stub != 0 {
+			return stub
+		}
 		Log(context.TODO()).Warn("no editions", "workID", defaults.Id)
 		return 0
 	}
@@ -435,12 +562,22 @@ func bestHardcoverEdition(defaults hardcover.DefaultEditions, expectedAuthorID i
 		return 0
 	}
 
-	return defaults.Fallback[0].Id
+	fallback := defaults.Fallback[0]
+	if stub != 0 && stubEdition(fallback.Pages, fallback.Audio_seconds) {
+		return stub
+	}
+	return fallback.Id
+}
+
+// hasImage returns true if a cached_image is set to a non-empty URL.
+func hasImage(image json.RawMessage) bool {
+	url := strings.Trim(strings.TrimSpace(string(image)), `"`)
+	return url != "" && url != "null"
 }
 
 func bestAuthor(contributions []hardcover.Contributions) (hardcover.ContributionsAuthorAuthors, error) {
 	if len(contributions) == 0 {
-		return hardcover.ContributionsAuthorAuthors{}, errors.Join(errNotFound, fmt.Errorf("no contributions"))
+		return hardcover.ContributionsAuthorAuthors{}, errors.Join(errNotFound, errInvalid, fmt.Errorf("no contributions"))
 	}
 	for _, c := range contributions {
 		switch strings.ToLower(c.Contribution) {
@@ -471,33 +608,71 @@ func bestAuthor(contributions []hardcover.Contributions) (hardcover.Contribution
 			continue
 		}
 	}
-	return hardcover.ContributionsAuthorAuthors{}, errors.Join(errNotFound, fmt.Errorf("no valid contribution"))
+	return hardcover.ContributionsAuthorAuthors{}, errors.Join(errNotFound, errInvalid, fmt.Errorf("no valid contribution"))
+}
+
+// hcNarrator returns the ID of the first contributor credited as a narrator,
+// if any.
+func hcNarrator(contributions []hardcover.Contributions) int64 {
+	for _, c := range contributions {
+		if isNarrator(c.Contribution) {
+			return c.Author.Id
+		}
+	}
+	return 0
+}
+
+// pseudonyms returns anyone credited with a "pseudonym" contribution other
+// than the author. They're the same person writing under another name, which
+// bestAuthor otherwise skips.
+func pseudonyms(contributions []hardcover.Contributions, authorID int64) []AuthorAlias {
+	var aliases []AuthorAlias
+	for _, c := range contributions {
+		if !strings.EqualFold(strings.TrimSpace(c.Contribution), "pseudonym") {
+			continue
+		}
+		if c.Author.Id == 0 || c.Author.Id == authorID {
+			continue
+		}
+		aliases = mergeAliases(aliases, AuthorAlias{ForeignID: c.Author.Id, Name: c.Author.Name})
+	}
+	return aliases
 }
 
 // GetAuthor looks up an author on Hardcover.
-func (g *HCGetter) GetAuthor(ctx context.Context, authorID int64) ([]byte, error) {
+func (g *HCGetter) GetAuthor(ctx context.Context, authorID int64) (_ []byte, err error) {
+	defer func() { g.metrics.resultInc("GetAuthor", err) }()
+
 	Log(ctx).Debug("getting author", "authorID", authorID)
 
 	if authorID == 0 {
 		return nil, errors.Join(errBadRequest, errors.New("author ID missing"))
 	}
+	if err := poisoned(g.poisonAuthors, authorID); err != nil {
+		return nil, err
+	}
 
 	resp, err := hardcover.GetAuthorEditions(ctx, g.gql, authorID, 20, 0)
 	if err != nil {
 		return nil, fmt.Errorf("getting author editions: %w", err)
 	}
+	g.keepRaw(ctx, g.cache, AuthorKey(authorID), resp)
 
 	if resp.Authors_by_pk.Id == 0 {
 		return nil, errors.Join(errNotFound, fmt.Errorf("invalid author editions"))
 	}
 
 	author, err := bestAuthor(hardcover.AsContributions(resp.Authors_by_pk.Contributions))
+	if errors.Is(err, errNotFound) && g.emptyAuthors {
+		Log(ctx).Debug("no valid contributions, returning empty author", "authorID", authorID)
+		return json.Marshal(emptyHardcoverAuthor(resp.Authors_by_pk.AuthorInfo))
+	}
 	if err != nil {
 		return nil, err
 	}
 	if author.Id != authorID {
 		Log(ctx).Warn("author mismatch, possibly merged?", "expected", authorID, "got", author.Id)
-		return nil, errors.Join(errNotFound, fmt.Errorf("author mismatch"))
+		return nil, errors.Join(errNotFound, errInvalid, fmt.Errorf("author mismatch"))
 	}
 
 	for _, cc := range resp.Authors_by_pk.Contributions {
@@ -525,19 +700,61 @@ func (g *HCGetter) GetAuthor(ctx context.Context, authorID int64) ([]byte, error
 		return json.Marshal(author)
 	}
 
+	if g.emptyAuthors {
+		Log(ctx).Debug("no valid works found, returning empty author", "authorID", authorID)
+		return json.Marshal(emptyHardcoverAuthor(resp.Authors_by_pk.AuthorInfo))
+	}
+
 	Log(ctx).Warn("no valid works found", "authorID", authorID)
-	return nil, errors.Join(errNotFound, fmt.Errorf("no valid works found"))
+	return nil, errors.Join(errNotFound, errInvalid, fmt.Errorf("no valid works found"))
+}
+
+// emptyHardcoverAuthor returns an author without any works, for authors who
+// exist but only have works we filter out (e.g. editor credits).
+func emptyHardcoverAuthor(author hardcover.AuthorInfo) AuthorResource {
+	return AuthorResource{
+		Name:        author.Name,
+		ForeignID:   author.Id,
+		URL:         "https://hardcover.app/authors/" + author.Slug,
+		ImageURL:    strings.ReplaceAll(string(author.Cached_image), `"`, ``),
+		Description: description(author.Bio),
+		Works:       []workResource{},
+		Series:      []SeriesResource{},
+		Source:      _sourceHardcover,
+	}
+}
+
+// GetWorkIDBySlug looks up a work by the slug in its hardcover.app URL.
+func (g *HCGetter) GetWorkIDBySlug(ctx context.Context, slug string) (int64, error) {
+	resp, err := hardcover.GetWorkBySlug(ctx, g.gql, slug)
+	if err != nil {
+		return 0, fmt.Errorf("getting work by slug: %w", err)
+	}
+	if len(resp.Books) == 0 {
+		return 0, errNotFound
+	}
+	return resp.Books[0].Id, nil
+}
+
+// servesHost is true for hardcover.app URLs.
+func (g *HCGetter) servesHost(host string) bool {
+	return host == "hardcover.app" || strings.HasSuffix(host, ".hardcover.app")
 }
 
 // GetSeries isn't implemented yet.
-func (g *HCGetter) GetSeries(ctx context.Context, seriesID int64) (*SeriesResource, error) {
+func (g *HCGetter) GetSeries(ctx context.Context, seriesID int64) (_ *SeriesResource, err error) {
+	defer func() { g.metrics.resultInc("GetSeries", err) }()
+
 	seriesRsc := &SeriesResource{
 		LinkItems: []seriesWorkLinkResource{},
 	}
 
 	limit, offset := int64(1000), int64(0)
 
-	var lastPosition float32
+	// Only one book is kept per position. picked tracks the book behind each
+	// link item and positions maps a position to its link item.
+	picked := []hardcover.GetSeriesSeries_by_pkSeriesBook_series{}
+	positions := map[float32]int{}
 
 	// Max out at 3k for the series.
 	for offset < 3*limit {
@@ -555,17 +772,25 @@ func (g *HCGetter) GetSeries(ctx context.Context, seriesID int64) (*SeriesResour
 		}
 
 		for _, bs := range series.Series_by_pk.Book_series {
-			if lastPosition > 0 && bs.Position == lastPosition {
-				// Skip less popular duplicates.
-				continue
-			}
-			seriesRsc.LinkItems = append(seriesRsc.LinkItems, seriesWorkLinkResource{
+			link := seriesWorkLinkResource{
 				ForeignWorkID:    bs.Book_id,
 				PositionInSeries: bs.Details,
 				SeriesPosition:   int(bs.Position),
 				Primary:          bs.Featured,
-			})
-			lastPosition = bs.Position
+			}
+			if bs.Position > 0 {
+				if idx, ok := positions[bs.Position]; ok {
+					// Replace less popular duplicates.
+					if seriesTieBreak(bs, picked[idx]) {
+						picked[idx] = bs
+						seriesRsc.LinkItems[idx] = link
+					}
+					continue
+				}
+				positions[bs.Position] = len(seriesRsc.LinkItems)
+			}
+			picked = append(picked, bs)
+			seriesRsc.LinkItems = append(seriesRsc.LinkItems, link)
 		}
 
 		if len(seriesRsc.LinkItems) >= int(series.Series_by_pk.Books_count) {
@@ -578,6 +803,16 @@ func (g *HCGetter) GetSeries(ctx context.Context, seriesID int64) (*SeriesResour
 	return seriesRsc, nil
 }
 
+// seriesTieBreak returns true if a should be preferred over b when both share
+// the same series position. The book with the most ratings wins, falling back
+// to the lowest ID, so the choice doesn't depend on upstream ordering.
+func seriesTieBreak(a, b hardcover.GetSeriesSeries_by_pkSeriesBook_series) bool {
+	if a.Book.Ratings_count != b.Book.Ratings_count {
+		return a.Book.Ratings_count > b.Book.Ratings_count
+	}
+	return a.Book_id < b.Book_id
+}
+
 func hcReleaseDate(d string) string {
 	if strings.HasSuffix(d, "BC") {
 		return "0001-01-01"
@@ -586,7 +821,7 @@ func hcReleaseDate(d string) string {
 	if err != nil {
 		return ""
 	}
-	if t.Year() > 9999 {
+	if t.Year() > 9999 || tooFarInFuture(t) {
 		return ""
 	}
 	return d
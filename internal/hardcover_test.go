@@ -4,9 +4,11 @@ package internal
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"os"
+	"slices"
 	"testing"
 	"time"
 
@@ -275,7 +277,7 @@ func TestGetBookDataIntegrity(t *testing.T) {
 	ctrl, err := NewController(cache, getter, nil, nil)
 	require.NoError(t, err)
 
-	go ctrl.Run(t.Context()) // Denormalize data in the background.
+	runController(t, ctrl) // Denormalize data in the background.
 	t.Cleanup(func() { ctrl.Shutdown(t.Context()) })
 
 	t.Run("GetBook", func(t *testing.T) {
@@ -352,7 +354,7 @@ func TestHardcoverIntegration(t *testing.T) {
 
 	hcClient := &http.Client{Transport: hcTransport}
 
-	gql, err := NewBatchedGraphQLClient("https://api.hardcover.app/v1/graphql", hcClient, time.Second, 25, nil)
+	gql, err := NewBatchedGraphQLClient(t.Context(), "https://api.hardcover.app/v1/graphql", hcClient, time.Second, 25, nil)
 	require.NoError(t, err)
 
 	getter, err := NewHardcoverGetter(cache, gql)
@@ -360,7 +362,7 @@ func TestHardcoverIntegration(t *testing.T) {
 
 	ctrl, err := NewController(cache, getter, nil, nil)
 	require.NoError(t, err)
-	go ctrl.Run(t.Context())
+	runController(t, ctrl)
 
 	t.Run("GetAuthor", func(t *testing.T) {
 		t.Parallel()
@@ -514,12 +516,440 @@ func TestHardcoverIntegration(t *testing.T) {
 
 	t.Run("Recommended", func(t *testing.T) {
 		t.Parallel()
-		recommended, err := getter.Recommendations(t.Context(), 1)
+		recommended, err := getter.Recommendations(t.Context(), 1, recommendationsFilter{})
 		require.NoError(t, err)
 		assert.NotEmpty(t, recommended.WorkIDs)
 	})
 }
 
+func TestHCGetAuthorBooksLastPage(t *testing.T) {
+	// We shouldn't request another page if the current one wasn't full.
+
+	t.Parallel()
+
+	c := gomock.NewController(t)
+
+	author := hardcover.Contributions{
+		Author: hardcover.ContributionsAuthorAuthors{AuthorInfo: hardcover.AuthorInfo{Id: 1}},
+	}
+
+	gql := hardcover.NewMockgql(c)
+	gql.EXPECT().MakeRequest(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, req *graphql.Request, res *graphql.Response) error {
+			gaw := res.Data.(*hardcover.GetAuthorEditionsResponse)
+			for _, editionID := range []int64{100, 200} {
+				gaw.Authors_by_pk.Contributions = append(gaw.Authors_by_pk.Contributions, hardcover.GetAuthorEditionsAuthors_by_pkAuthorsContributions{
+					Contributions: author,
+					Book: hardcover.GetAuthorEditionsAuthors_by_pkAuthorsContributionsBookBooks{
+						DefaultEditions: hardcover.DefaultEditions{
+							Contributions: []hardcover.DefaultEditionsContributions{{Contributions: author}},
+							Fallback:      []hardcover.DefaultEditionsFallbackEditions{{Id: editionID}},
+						},
+					},
+				})
+			}
+			return nil
+		}).Times(1)
+
+	getter, err := NewHardcoverGetter(newMemoryCache(), gql)
+	require.NoError(t, err)
+
+	assert.Equal(t, []int64{100, 200}, slices.Collect(getter.GetAuthorBooks(t.Context(), 1)))
+}
+
+func TestHCEmptyAuthors(t *testing.T) {
+	// Authors who are only credited as editors have no valid works, but they
+	// can optionally still be returned.
+
+	t.Parallel()
+
+	c := gomock.NewController(t)
+
+	gql := hardcover.NewMockgql(c)
+	gql.EXPECT().MakeRequest(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, req *graphql.Request, res *graphql.Response) error {
+			gaw := res.Data.(*hardcover.GetAuthorEditionsResponse)
+			gaw.Authors_by_pk.AuthorInfo = hardcover.AuthorInfo{Id: 1, Name: "Editor", Slug: "editor"}
+			gaw.Authors_by_pk.Contributions = []hardcover.GetAuthorEditionsAuthors_by_pkAuthorsContributions{{
+				Contributions: hardcover.Contributions{
+					Author:       hardcover.ContributionsAuthorAuthors{AuthorInfo: hardcover.AuthorInfo{Id: 1}},
+					Contribution: "Editor",
+				},
+			}}
+			return nil
+		}).Times(2)
+
+	getter, err := NewHardcoverGetter(newMemoryCache(), gql)
+	require.NoError(t, err)
+
+	_, err = getter.GetAuthor(t.Context(), 1)
+	assert.ErrorIs(t, err, errNotFound)
+
+	getter, err = NewHardcoverGetter(newMemoryCache(), gql, WithEmptyAuthors(true))
+	require.NoError(t, err)
+
+	out, err := getter.GetAuthor(t.Context(), 1)
+	require.NoError(t, err)
+
+	var author AuthorResource
+	require.NoError(t, json.Unmarshal(out, &author))
+	assert.Equal(t, int64(1), author.ForeignID)
+	assert.Equal(t, "Editor", author.Name)
+	assert.Equal(t, "https://hardcover.app/authors/editor", author.URL)
+	assert.NotNil(t, author.Works)
+	assert.Empty(t, author.Works)
+}
+
+func TestHCGetterResults(t *testing.T) {
+	// Upstream 404s, data we reject and other failures are counted apart.
+
+	t.Parallel()
+
+	gql := hardcover.NewMockgql(gomock.NewController(t))
+	gomock.InOrder(
+		gql.EXPECT().MakeRequest(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil), // Not found.
+		gql.EXPECT().MakeRequest(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, req *graphql.Request, res *graphql.Response) error {
+				gaw := res.Data.(*hardcover.GetAuthorEditionsResponse)
+				gaw.Authors_by_pk.AuthorInfo = hardcover.AuthorInfo{Id: 1, Name: "Editor"}
+				gaw.Authors_by_pk.Contributions = []hardcover.GetAuthorEditionsAuthors_by_pkAuthorsContributions{{
+					Contributions: hardcover.Contributions{
+						Author:       hardcover.ContributionsAuthorAuthors{AuthorInfo: hardcover.AuthorInfo{Id: 1}},
+						Contribution: "Editor",
+					},
+				}}
+				return nil
+			}),
+		gql.EXPECT().MakeRequest(gomock.Any(), gomock.Any(), gomock.Any()).Return(errors.New("boom")),
+	)
+
+	getter, err := NewHardcoverGetter(newMemoryCache(), gql)
+	require.NoError(t, err)
+
+	for range 3 {
+		_, err = getter.GetAuthor(t.Context(), 1)
+		assert.Error(t, err)
+	}
+
+	assert.Equal(t, 1.0, getter.metrics.resultGet("GetAuthor", "not_found"))
+	assert.Equal(t, 1.0, getter.metrics.resultGet("GetAuthor", "invalid"))
+	assert.Equal(t, 1.0, getter.metrics.resultGet("GetAuthor", "error"))
+	assert.Zero(t, getter.metrics.resultGet("GetAuthor", "ok"))
+}
+
+func TestHCUnratedWork(t *testing.T) {
+	// Unrated works and editions can report a sentinel average instead of 0,
+	// so they aren't shown as zero stars. Rated works are unaffected.
+
+	t.Cleanup(func() { SetUnratedRating(0) })
+
+	author := hardcover.Contributions{
+		Author: hardcover.ContributionsAuthorAuthors{AuthorInfo: hardcover.AuthorInfo{Id: 1}},
+	}
+	work := func(count int64, rating float64) hardcover.WorkInfo {
+		return hardcover.WorkInfo{
+			Id: 1,
+			DefaultEditions: hardcover.DefaultEditions{
+				Contributions: []hardcover.DefaultEditionsContributions{{Contributions: author}},
+			},
+			Ratings_count: count,
+			Rating:        rating,
+		}
+	}
+	serialized := func(t *testing.T, w hardcover.WorkInfo) map[string]any {
+		workRsc, err := mapHardcoverToWorkResource(t.Context(), hardcover.EditionInfo{Id: 10}, w)
+		require.NoError(t, err)
+		out, err := json.Marshal(workRsc)
+		require.NoError(t, err)
+		var m map[string]any
+		require.NoError(t, json.Unmarshal(out, &m))
+		return m
+	}
+
+	tests := []struct {
+		name     string
+		unrated  float64
+		count    int64
+		rating   float64
+		wantWork float64
+	}{
+		{name: "unrated by default", unrated: 0, count: 0, rating: 0, wantWork: 0},
+		{name: "unrated sentinel", unrated: -1, count: 0, rating: 0, wantWork: -1},
+		{name: "rated", unrated: -1, count: 3, rating: 4.5, wantWork: 4.5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetUnratedRating(tt.unrated)
+
+			m := serialized(t, work(tt.count, tt.rating))
+			assert.Equal(t, tt.wantWork, m["AverageRating"])
+			assert.Equal(t, float64(tt.count), m["RatingCount"])
+
+			books := m["Books"].([]any)
+			require.Len(t, books, 1)
+			book := books[0].(map[string]any)
+			assert.Equal(t, tt.wantWork, book["AverageRating"])
+			assert.Equal(t, float64(tt.count), book["RatingCount"])
+			assert.NotNil(t, book["RatingSum"])
+		})
+	}
+}
+
+// hcSavedEditions returns the editions saved while loading a work made up of
+// the given editions.
+func hcSavedEditions(t *testing.T, editions []hardcover.GetWorkBooks_by_pkBooksEditions, opts ...GetterOption) []workResource {
+	t.Helper()
+
+	author := hardcover.Contributions{
+		Author: hardcover.ContributionsAuthorAuthors{AuthorInfo: hardcover.AuthorInfo{Id: 1}},
+	}
+
+	gql := hardcover.NewMockgql(gomock.NewController(t))
+	gql.EXPECT().MakeRequest(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, req *graphql.Request, res *graphql.Response) error {
+			if req.OpName != "GetWork" {
+				return fmt.Errorf("unrecognized op %q", req.OpName)
+			}
+			vars, err := json.Marshal(req.Variables)
+			if err != nil {
+				return err
+			}
+			var v struct{ Narrators bool }
+			if err := json.Unmarshal(vars, &v); err != nil {
+				return err
+			}
+
+			gwr := res.Data.(*hardcover.GetWorkResponse)
+			gwr.Books_by_pk.WorkInfo = hardcover.WorkInfo{
+				Id: 1,
+				DefaultEditions: hardcover.DefaultEditions{
+					Contributions: []hardcover.DefaultEditionsContributions{{Contributions: author}},
+				},
+			}
+			for _, e := range editions {
+				if !v.Narrators {
+					e.Contributions = nil // Not requested.
+				}
+				gwr.Books_by_pk.Editions = append(gwr.Books_by_pk.Editions, e)
+			}
+			return nil
+		}).AnyTimes()
+
+	getter, err := NewHardcoverGetter(newMemoryCache(), gql, opts...)
+	require.NoError(t, err)
+
+	saved := []workResource{}
+	_, _, _ = getter.GetWork(t.Context(), 1, func(editions ...workResource) {
+		saved = append(saved, editions...)
+	})
+	return saved
+}
+
+// savedIDs returns the IDs of saved editions.
+func savedIDs(editions []workResource) []int64 {
+	ids := []int64{}
+	for _, e := range editions {
+		ids = append(ids, e.Books[0].ForeignID)
+	}
+	slices.Sort(ids)
+	return ids
+}
+
+func TestHCAllEditions(t *testing.T) {
+	// Similar editions are deduped unless every edition is requested.
+
+	t.Parallel()
+
+	editions := []hardcover.GetWorkBooks_by_pkBooksEditions{
+		{EditionInfo: hardcover.EditionInfo{Id: 10, Title: "Foo", Edition_format: "Hardcover"}},
+		{EditionInfo: hardcover.EditionInfo{Id: 20, Title: "Foo", Edition_format: "Paperback"}},
+		{EditionInfo: hardcover.EditionInfo{Id: 30, Title: "Foo", Edition_format: "Audiobook"}},
+	}
+
+	assert.Equal(t, []int64{10, 30}, savedIDs(hcSavedEditions(t, editions)))
+	assert.Equal(t, []int64{10, 20, 30}, savedIDs(hcSavedEditions(t, editions, WithAllEditions(true))))
+}
+
+func TestHCRatingsCount(t *testing.T) {
+	// Averages are weighted by ratings, not reviews, which Hardcover counts
+	// separately and which are far fewer.
+
+	for _, op := range []string{hardcover.GetWork_Operation, hardcover.GetEdition_Operation, hardcover.GetAuthorEditions_Operation} {
+		assert.Contains(t, op, "\tratings_count\n")
+		assert.NotContains(t, op, "reviews_count")
+	}
+
+	workRsc, err := mapHardcoverToWorkResource(t.Context(), hardcover.EditionInfo{Id: 10}, hardcover.WorkInfo{
+		Id: 1,
+		DefaultEditions: hardcover.DefaultEditions{
+			Contributions: []hardcover.DefaultEditionsContributions{{Contributions: hardcover.Contributions{
+				Author: hardcover.ContributionsAuthorAuthors{AuthorInfo: hardcover.AuthorInfo{Id: 1}},
+			}}},
+		},
+		Ratings_count: 4,
+		Rating:        4.5,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, int64(4), workRsc.RatingCount)
+	assert.Equal(t, int64(18), workRsc.RatingSum)
+	require.Len(t, workRsc.Books, 1)
+	assert.Equal(t, int64(4), workRsc.Books[0].RatingCount)
+	assert.Equal(t, int64(18), workRsc.Books[0].RatingSum)
+}
+
+func TestHCContributorIDs(t *testing.T) {
+	// Every credited contributor is available for relaxed author matching.
+
+	t.Parallel()
+
+	contribution := func(id int64, role string) hardcover.DefaultEditionsContributions {
+		return hardcover.DefaultEditionsContributions{Contributions: hardcover.Contributions{
+			Author:       hardcover.ContributionsAuthorAuthors{AuthorInfo: hardcover.AuthorInfo{Id: id}},
+			Contribution: role,
+		}}
+	}
+
+	workRsc, err := mapHardcoverToWorkResource(t.Context(), hardcover.EditionInfo{Id: 10}, hardcover.WorkInfo{
+		Id: 1,
+		DefaultEditions: hardcover.DefaultEditions{
+			Contributions: []hardcover.DefaultEditionsContributions{contribution(100, ""), contribution(200, "Author")},
+		},
+	})
+	require.NoError(t, err)
+
+	require.Len(t, workRsc.Books, 1)
+	assert.Equal(t, []int64{100, 200}, workRsc.Books[0].contributorIDs)
+}
+
+func TestHCSkipEbooks(t *testing.T) {
+	t.Parallel()
+
+	saved := hcSavedEditions(t, []hardcover.GetWorkBooks_by_pkBooksEditions{
+		{EditionInfo: hardcover.EditionInfo{Id: 10, Title: "Kindle", Edition_format: "Kindle Edition"}},
+		{EditionInfo: hardcover.EditionInfo{Id: 20, Title: "Ebook", Edition_format: "ebook"}},
+		{EditionInfo: hardcover.EditionInfo{Id: 30, Title: "Print", Edition_format: "Hardcover"}},
+	}, WithSkipEbooks(true))
+
+	assert.Equal(t, []int64{30}, savedIDs(saved))
+}
+
+func TestHCAudioNarrators(t *testing.T) {
+	// Audiobooks which only differ by narrator collapse to the most popular
+	// one, regardless of upstream's order, unless we keep one per narrator.
+
+	t.Parallel()
+
+	narrator := func(id int64) []hardcover.GetWorkBooks_by_pkBooksEditionsContributions {
+		return []hardcover.GetWorkBooks_by_pkBooksEditionsContributions{{Contributions: hardcover.Contributions{
+			Author:       hardcover.ContributionsAuthorAuthors{AuthorInfo: hardcover.AuthorInfo{Id: id}},
+			Contribution: "Narrator",
+		}}}
+	}
+
+	editions := []hardcover.GetWorkBooks_by_pkBooksEditions{
+		{EditionInfo: hardcover.EditionInfo{Id: 10, Title: "Foo", Edition_format: "Audiobook", Users_read_count: 5}, Contributions: narrator(100)},
+		{EditionInfo: hardcover.EditionInfo{Id: 20, Title: "Foo", Edition_format: "Audiobook", Users_read_count: 50}, Contributions: narrator(200)},
+		{EditionInfo: hardcover.EditionInfo{Id: 30, Title: "Foo", Edition_format: "Hardcover"}},
+	}
+
+	saved := func(t *testing.T, opts ...GetterOption) map[int64][]contributorResource {
+		out := map[int64][]contributorResource{}
+		for _, e := range hcSavedEditions(t, editions, opts...) {
+			out[e.Books[0].ForeignID] = e.Books[0].Contributors
+		}
+		return out
+	}
+
+	t.Run("most popular", func(t *testing.T) {
+		got := saved(t)
+		assert.Len(t, got, 2)
+		assert.Contains(t, got, int64(20))
+		assert.Contains(t, got, int64(30))
+		assert.Equal(t, []contributorResource{{ForeignID: 1, Role: "Author"}}, got[20])
+	})
+
+	t.Run("per narrator", func(t *testing.T) {
+		got := saved(t, WithAudioNarrators(true))
+		assert.Len(t, got, 3)
+		assert.Equal(t, []contributorResource{{ForeignID: 1, Role: "Author"}, {ForeignID: 100, Role: "Narrator"}}, got[10])
+		assert.Equal(t, []contributorResource{{ForeignID: 1, Role: "Author"}, {ForeignID: 200, Role: "Narrator"}}, got[20])
+		assert.Equal(t, []contributorResource{{ForeignID: 1, Role: "Author"}}, got[30])
+	})
+}
+
+func TestAudioFormats(t *testing.T) {
+	t.Parallel()
+
+	o := newGetterOptions()
+	for _, format := range []string{"Audible Audio", "Audio CD", "MP3 CD", "audiobook", " Audio Cassette "} {
+		assert.True(t, o.isAudio(format), format)
+	}
+	for _, format := range []string{"Hardcover", "Paperback", "Kindle Edition", ""} {
+		assert.False(t, o.isAudio(format), format)
+	}
+
+	o = newGetterOptions(WithAudioFormats("Playaway"))
+	assert.True(t, o.isAudio("playaway"))
+	assert.False(t, o.isAudio("Audio CD"))
+
+	// Audiobooks shouldn't be deduped with print editions of the same title.
+	for _, format := range []string{"Audio CD", "MP3 CD", "Audiobook"} {
+		t.Run(format, func(t *testing.T) {
+			saved := hcSavedEditions(t, []hardcover.GetWorkBooks_by_pkBooksEditions{
+				{EditionInfo: hardcover.EditionInfo{Id: 10, Title: "Same", Edition_format: "Hardcover"}},
+				{EditionInfo: hardcover.EditionInfo{Id: 20, Title: "Same", Edition_format: format}},
+			})
+
+			assert.Equal(t, []int64{10, 20}, savedIDs(saved))
+		})
+	}
+}
+
+func TestHCSeriesTieBreak(t *testing.T) {
+	// Books sharing a position should resolve to the same book regardless of
+	// the order they're returned in.
+
+	t.Parallel()
+
+	book := func(id int64, position float32, ratings int64) hardcover.GetSeriesSeries_by_pkSeriesBook_series {
+		return hardcover.GetSeriesSeries_by_pkSeriesBook_series{
+			Book_id:  id,
+			Position: position,
+			Book:     hardcover.GetSeriesSeries_by_pkSeriesBook_seriesBookBooks{Ratings_count: ratings},
+		}
+	}
+
+	orderings := [][]hardcover.GetSeriesSeries_by_pkSeriesBook_series{
+		{book(1, 1, 10), book(31, 3, 5), book(30, 3, 5), book(32, 3, 2)},
+		{book(1, 1, 10), book(32, 3, 2), book(30, 3, 5), book(31, 3, 5)},
+	}
+
+	for _, books := range orderings {
+		c := gomock.NewController(t)
+		gql := hardcover.NewMockgql(c)
+		gql.EXPECT().MakeRequest(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, req *graphql.Request, res *graphql.Response) error {
+				gsr := res.Data.(*hardcover.GetSeriesResponse)
+				gsr.Series_by_pk.Id = 100
+				gsr.Series_by_pk.Books_count = 2
+				gsr.Series_by_pk.Book_series = books
+				return nil
+			})
+
+		getter, err := NewHardcoverGetter(newMemoryCache(), gql)
+		require.NoError(t, err)
+
+		series, err := getter.GetSeries(t.Context(), 100)
+		require.NoError(t, err)
+
+		require.Len(t, series.LinkItems, 2)
+		assert.Equal(t, int64(1), series.LinkItems[0].ForeignWorkID)
+		assert.Equal(t, int64(30), series.LinkItems[1].ForeignWorkID)
+		assert.Equal(t, 3, series.LinkItems[1].SeriesPosition)
+	}
+}
+
 func TestBestAuthor(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -591,4 +1021,264 @@ func TestHCReleaseDate(t *testing.T) {
 			assert.Equal(t, tt.want, got)
 		})
 	}
+
+	t.Run("max future years", func(t *testing.T) {
+		SetMaxFutureYears(5)
+		t.Cleanup(func() { SetMaxFutureYears(0) })
+
+		assert.Equal(t, "2024-06-01", hcReleaseDate("2024-06-01"))
+		assert.Equal(t, "", hcReleaseDate("2087-06-01"))
+	})
+}
+
+func TestBestHardcoverEditionStubs(t *testing.T) {
+	author := hardcover.Contributions{
+		Author: hardcover.ContributionsAuthorAuthors{AuthorInfo: hardcover.AuthorInfo{Id: 1}},
+	}
+	defaults := hardcover.DefaultEditions{
+		Contributions: []hardcover.DefaultEditionsContributions{{Contributions: author}},
+		// A placeholder edition is the default cover edition.
+		Default_cover_edition: hardcover.DefaultEditionsDefault_cover_editionEditions{
+			Id:            10,
+			Pages:         0,
+			Contributions: []hardcover.DefaultEditionsDefault_cover_editionEditionsContributions{{Contributions: author}},
+		},
+		Default_physical_edition: hardcover.DefaultEditionsDefault_physical_editionEditions{
+			Id:            20,
+			Pages:         300,
+			Contributions: []hardcover.DefaultEditionsDefault_physical_editionEditionsContributions{{Contributions: author}},
+		},
+		Fallback: []hardcover.DefaultEditionsFallbackEditions{{Id: 30}},
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		assert.Equal(t, int64(10), bestHardcoverEdition(defaults, 1))
+	})
+
+	t.Run("enabled", func(t *testing.T) {
+		SetMinEditionPages(1)
+		t.Cleanup(func() { SetMinEditionPages(0) })
+
+		assert.Equal(t, int64(20), bestHardcoverEdition(defaults, 1))
+
+		// The stub is still better than another stub.
+		onlyStubs := defaults
+		onlyStubs.Default_physical_edition = hardcover.DefaultEditionsDefault_physical_editionEditions{}
+		assert.Equal(t, int64(10), bestHardcoverEdition(onlyStubs, 1))
+
+		// But not better than a real fallback.
+		onlyStubs.Fallback = []hardcover.DefaultEditionsFallbackEditions{{Id: 30, Pages: 200}}
+		assert.Equal(t, int64(30), bestHardcoverEdition(onlyStubs, 1))
+
+		// Audiobooks aren't stubs.
+		audio := defaults
+		audio.Default_cover_edition.Audio_seconds = 3600
+		assert.Equal(t, int64(10), bestHardcoverEdition(audio, 1))
+	})
+}
+
+func TestBestHardcoverEditionPrimaryLanguage(t *testing.T) {
+	author := hardcover.Contributions{
+		Author: hardcover.ContributionsAuthorAuthors{AuthorInfo: hardcover.AuthorInfo{Id: 1}},
+	}
+	defaults := hardcover.DefaultEditions{
+		Contributions: []hardcover.DefaultEditionsContributions{{Contributions: author}},
+		Default_cover_edition: hardcover.DefaultEditionsDefault_cover_editionEditions{
+			Id:            10,
+			Pages:         300,
+			Language:      hardcover.DefaultEditionsDefault_cover_editionEditionsLanguageLanguages{Code3: "eng"},
+			Contributions: []hardcover.DefaultEditionsDefault_cover_editionEditionsContributions{{Contributions: author}},
+		},
+		Default_physical_edition: hardcover.DefaultEditionsDefault_physical_editionEditions{
+			Id:            20,
+			Pages:         280,
+			Language:      hardcover.DefaultEditionsDefault_physical_editionEditionsLanguageLanguages{Code3: "fra"},
+			Contributions: []hardcover.DefaultEditionsDefault_physical_editionEditionsContributions{{Contributions: author}},
+		},
+	}
+
+	assert.Equal(t, int64(10), bestHardcoverEdition(defaults, 1))
+
+	require.NoError(t, SetPrimaryLanguage("fr"))
+	t.Cleanup(func() { _ = SetPrimaryLanguage("") })
+	assert.Equal(t, int64(20), bestHardcoverEdition(defaults, 1))
+
+	// Without an edition in the primary language we keep the usual order.
+	require.NoError(t, SetPrimaryLanguage("deu"))
+	assert.Equal(t, int64(10), bestHardcoverEdition(defaults, 1))
+
+	assert.Error(t, SetPrimaryLanguage("klingon"))
+}
+
+func TestBestHardcoverEditionPrefersCovers(t *testing.T) {
+	author := hardcover.Contributions{
+		Author: hardcover.ContributionsAuthorAuthors{AuthorInfo: hardcover.AuthorInfo{Id: 1}},
+	}
+	other := hardcover.Contributions{
+		Author: hardcover.ContributionsAuthorAuthors{AuthorInfo: hardcover.AuthorInfo{Id: 2}},
+	}
+	defaults := hardcover.DefaultEditions{
+		Contributions: []hardcover.DefaultEditionsContributions{{Contributions: author}},
+		Default_cover_edition: hardcover.DefaultEditionsDefault_cover_editionEditions{
+			Id:            10,
+			Pages:         300,
+			Cached_image:  json.RawMessage(`""`),
+			Contributions: []hardcover.DefaultEditionsDefault_cover_editionEditionsContributions{{Contributions: author}},
+		},
+		Default_ebook_edition: hardcover.DefaultEditionsDefault_ebook_editionEditions{
+			Id:            20,
+			Pages:         300,
+			Cached_image:  json.RawMessage(`"https://example.com/ebook.jpg"`),
+			Contributions: []hardcover.DefaultEditionsDefault_ebook_editionEditionsContributions{{Contributions: other}},
+		},
+		Default_physical_edition: hardcover.DefaultEditionsDefault_physical_editionEditions{
+			Id:            30,
+			Pages:         300,
+			Cached_image:  json.RawMessage(`"https://example.com/physical.jpg"`),
+			Contributions: []hardcover.DefaultEditionsDefault_physical_editionEditionsContributions{{Contributions: author}},
+		},
+	}
+
+	// The ebook has a cover but the wrong author, so the physical edition
+	// wins over the cover edition without an image.
+	assert.Equal(t, int64(30), bestHardcoverEdition(defaults, 1))
+
+	// Without any covers we keep the usual order.
+	defaults.Default_physical_edition.Cached_image = json.RawMessage(`null`)
+	assert.Equal(t, int64(10), bestHardcoverEdition(defaults, 1))
+}
+
+func TestHCGenreRecommendations(t *testing.T) {
+	// Seeds are approximated by popular works from their first real genre.
+
+	gql := hardcover.NewMockgql(gomock.NewController(t))
+	gql.EXPECT().MakeRequest(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, req *graphql.Request, res *graphql.Response) error {
+			vars := req.Variables.(interface{ GetGenre() string })
+			assert.Equal(t, "Fantasy", vars.GetGenre())
+			resp := res.Data.(*hardcover.GetGenreRecommendedResponse)
+			resp.Books = []hardcover.GetGenreRecommendedBooks{{Id: 2}, {Id: 3}}
+			return nil
+		}).Times(2)
+
+	getter, err := NewHardcoverGetter(newMemoryCache(), gql)
+	require.NoError(t, err)
+
+	recs, err := getter.Recommendations(t.Context(), 1, recommendationsFilter{genre: "Fantasy"})
+	require.NoError(t, err)
+	assert.Equal(t, []int64{2, 3}, recs.WorkIDs)
+
+	seed := &workResource{ForeignID: 1, Genres: []string{_genrePlaceholder, "Fantasy"}}
+	recs, err = getter.Recommendations(t.Context(), 1, recommendationsFilter{seed: seed})
+	require.NoError(t, err)
+	assert.Equal(t, []int64{2, 3}, recs.WorkIDs)
+
+	// Without a genre there's nothing to go on.
+	recs, err = getter.Recommendations(t.Context(), 1, recommendationsFilter{seed: &workResource{ForeignID: 1}})
+	require.NoError(t, err)
+	assert.Empty(t, recs.WorkIDs)
+}
+
+func TestHCRecommendationsPaging(t *testing.T) {
+	gql := hardcover.NewMockgql(gomock.NewController(t))
+	gql.EXPECT().MakeRequest(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, req *graphql.Request, res *graphql.Response) error {
+			offset := req.Variables.(interface{ GetOffset() int64 }).GetOffset()
+			resp := res.Data.(*hardcover.GetRecommendedResponse)
+			if offset == 0 {
+				resp.Books_trending.WorkIDs = []int64{1, 2}
+			}
+			return nil
+		}).Times(2)
+
+	getter, err := NewHardcoverGetter(newMemoryCache(), gql)
+	require.NoError(t, err)
+
+	_, err = getter.Recommendations(t.Context(), 0, recommendationsFilter{})
+	assert.ErrorIs(t, err, errBadRequest)
+
+	recs, err := getter.Recommendations(t.Context(), 1, recommendationsFilter{})
+	require.NoError(t, err)
+	assert.Equal(t, []int64{1, 2}, recs.WorkIDs)
+
+	// Past the last page.
+	recs, err = getter.Recommendations(t.Context(), 3, recommendationsFilter{})
+	require.NoError(t, err)
+	assert.Empty(t, recs.WorkIDs)
+}
+
+func TestHCPhysicalFormat(t *testing.T) {
+	work := hardcover.WorkInfo{Id: 1, Title: "Title"}
+	work.Contributions = []hardcover.DefaultEditionsContributions{{
+		Contributions: hardcover.Contributions{Author: hardcover.ContributionsAuthorAuthors{AuthorInfo: hardcover.AuthorInfo{Id: 2}}},
+	}}
+
+	edition := hardcover.EditionInfo{Id: 3, Physical_format: "Mass Market Paperback", Physical_information: "4.2 x 6.9 inches"}
+	workRsc, err := mapHardcoverToWorkResource(t.Context(), edition, work)
+	require.NoError(t, err)
+	require.Len(t, workRsc.Books, 1)
+	assert.Equal(t, "Mass Market Paperback", workRsc.Books[0].PhysicalFormat)
+	assert.Equal(t, "4.2 x 6.9 inches", workRsc.Books[0].PhysicalInformation)
+
+	// Absent details are omitted entirely.
+	workRsc, err = mapHardcoverToWorkResource(t.Context(), hardcover.EditionInfo{Id: 4}, work)
+	require.NoError(t, err)
+	out, err := json.Marshal(workRsc.Books[0])
+	require.NoError(t, err)
+	assert.NotContains(t, string(out), "Physical")
+}
+
+func TestHCPseudonyms(t *testing.T) {
+	credit := func(id int64, name, contribution string) hardcover.DefaultEditionsContributions {
+		return hardcover.DefaultEditionsContributions{Contributions: hardcover.Contributions{
+			Contribution: contribution,
+			Author:       hardcover.ContributionsAuthorAuthors{AuthorInfo: hardcover.AuthorInfo{Id: id, Name: name}},
+		}}
+	}
+	work := hardcover.WorkInfo{Id: 1, Title: "The Cuckoo's Calling"}
+	work.Contributions = []hardcover.DefaultEditionsContributions{
+		credit(2, "J.K. Rowling", ""),
+		credit(3, "Robert Galbraith", "Pseudonym"),
+		credit(3, "Robert Galbraith", "pseudonym"), // Duplicate.
+		credit(4, "Someone", "Narrator"),
+	}
+
+	workRsc, err := mapHardcoverToWorkResource(t.Context(), hardcover.EditionInfo{Id: 5}, work)
+	require.NoError(t, err)
+	require.Len(t, workRsc.Authors, 1)
+	assert.Equal(t, int64(2), workRsc.Authors[0].ForeignID)
+	assert.Equal(t, []AuthorAlias{{ForeignID: 3, Name: "Robert Galbraith"}}, workRsc.Authors[0].Aliases)
+
+	// Authors without aliases omit the field.
+	work.Contributions = work.Contributions[:1]
+	workRsc, err = mapHardcoverToWorkResource(t.Context(), hardcover.EditionInfo{Id: 5}, work)
+	require.NoError(t, err)
+	out, err := json.Marshal(workRsc.Authors[0])
+	require.NoError(t, err)
+	assert.NotContains(t, string(out), "Aliases")
+}
+
+func TestBestHardcoverEditionCoAuthor(t *testing.T) {
+	// The work is credited to author 1, but we're loading co-author 2.
+	credit := func(id int64) hardcover.Contributions {
+		return hardcover.Contributions{Author: hardcover.ContributionsAuthorAuthors{AuthorInfo: hardcover.AuthorInfo{Id: id}}}
+	}
+	defaults := hardcover.DefaultEditions{
+		Id:            1,
+		Contributions: []hardcover.DefaultEditionsContributions{{Contributions: credit(1)}},
+		Default_cover_edition: hardcover.DefaultEditionsDefault_cover_editionEditions{
+			Id:            10,
+			Pages:         300,
+			Contributions: []hardcover.DefaultEditionsDefault_cover_editionEditionsContributions{{Contributions: credit(1)}},
+		},
+		Fallback: []hardcover.DefaultEditionsFallbackEditions{{
+			Id:            30,
+			Pages:         200,
+			Contributions: []hardcover.DefaultEditionsFallbackEditionsContributions{{Contributions: credit(2)}},
+		}},
+	}
+
+	assert.Equal(t, int64(10), bestHardcoverEdition(defaults, 1))
+	assert.Equal(t, int64(30), bestHardcoverEdition(defaults, 2))
+	assert.Equal(t, int64(0), bestHardcoverEdition(defaults, 3))
 }
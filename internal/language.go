@@ -1,43 +1,262 @@
 package internal
 
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// _codes maps lower-cased language names, as G——R—— reports them, to ISO
+// 639-3 codes. Regional variants share their language's code so their
+// editions are bucketed together.
 var _codes = map[string]string{
-	"English":            "eng",
-	"French":             "fra",
-	"Spanish":            "spa",
-	"Spanish; Castilian": "spa",
-	"German":             "deu",
-	"Italian":            "ita",
-	"Danish":             "dan",
-	"Dutch":              "nld",
-	"Japanese":           "jpn",
-	"Icelandic":          "isl",
-	"Chinese":            "zho",
-	"Russian":            "rus",
-	"Polish":             "pol",
-	"Vietnamese":         "vie",
-	"Swedish":            "swe",
-	"Norwegian":          "nor",
-	"Norwegian Bokmal":   "nob",
-	"Finnish":            "fin",
-	"Turkish":            "tur",
-	"Portuguese":         "por",
-	"Greek":              "ell",
-	"Korean":             "kor",
-	"Hungarian":          "hun",
-	"Hebrew":             "heb",
-	"Czech":              "ces",
-	"Hindi":              "hin",
-	"Thai":               "tha",
-	"Bulgarian":          "bul",
-	"Romanian":           "ron",
-	"Arabic":             "ara",
-	"Ukrainian":          "ukr",
-}
-
-func iso639_3(name string) (iso string) {
-	iso, ok := _codes[name]
+	"english":              "eng",
+	"french":               "fra",
+	"spanish":              "spa",
+	"spanish; castilian":   "spa",
+	"castilian":            "spa",
+	"german":               "deu",
+	"italian":              "ita",
+	"danish":               "dan",
+	"dutch":                "nld",
+	"flemish":              "nld",
+	"japanese":             "jpn",
+	"icelandic":            "isl",
+	"chinese":              "zho",
+	"simplified chinese":   "zho",
+	"traditional chinese":  "zho",
+	"russian":              "rus",
+	"polish":               "pol",
+	"vietnamese":           "vie",
+	"swedish":              "swe",
+	"norwegian":            "nor",
+	"norwegian bokmal":     "nob",
+	"norwegian nynorsk":    "nno",
+	"finnish":              "fin",
+	"turkish":              "tur",
+	"portuguese":           "por",
+	"brazilian portuguese": "por",
+	"greek":                "ell",
+	"korean":               "kor",
+	"hungarian":            "hun",
+	"hebrew":               "heb",
+	"czech":                "ces",
+	"hindi":                "hin",
+	"thai":                 "tha",
+	"bulgarian":            "bul",
+	"romanian":             "ron",
+	"arabic":               "ara",
+	"ukrainian":            "ukr",
+	"catalan":              "cat",
+	"croatian":             "hrv",
+	"serbian":              "srp",
+	"slovak":               "slk",
+	"slovenian":            "slv",
+	"estonian":             "est",
+	"latvian":              "lav",
+	"lithuanian":           "lit",
+	"indonesian":           "ind",
+	"persian":              "fas",
+	"latin":                "lat",
+	"welsh":                "cym",
+	"irish":                "gle",
+	"afrikaans":            "afr",
+	"esperanto":            "epo",
+}
+
+// _languageOverrides replaces the normalized code for particular language
+// names or codes. Keys are lower-cased.
+var _languageOverrides = map[string]string{}
+
+// SetLanguageOverrides sets codes to use for particular language names or
+// codes, e.g. "Filipino" to "fil" or "nob" to "nor". Overrides take precedence
+// over the built-in table. It should only be called during startup.
+func SetLanguageOverrides(overrides map[string]string) {
+	_languageOverrides = map[string]string{}
+	for from, to := range overrides {
+		_languageOverrides[strings.ToLower(strings.TrimSpace(from))] = strings.ToLower(strings.TrimSpace(to))
+	}
+}
+
+// _unmappedLanguages remembers which unrecognized names were already logged.
+var _unmappedLanguages sync.Map
+
+// normalizeLanguage returns the ISO 639-3 code for a language name like
+// "French" or a code like "fra" or "fr-CH", so editions from either upstream
+// are bucketed consistently. Unrecognized names are returned unchanged and
+// logged once so operators can add an override.
+func normalizeLanguage(lang string) string {
+	lang = strings.TrimSpace(lang)
+	if lang == "" {
+		return ""
+	}
+	key := strings.ToLower(lang)
+	if code, ok := _languageOverrides[key]; ok {
+		return code
+	}
+
+	code, ok := _codes[key]
+	if !ok {
+		// Try without a parenthesized region, e.g. "English (US)".
+		if base, _, found := strings.Cut(key, " ("); found {
+			code, ok = _codes[base]
+		}
+	}
 	if ok {
-		return iso
+		if override, ok := _languageOverrides[code]; ok {
+			return override
+		}
+		return code
+	}
+
+	// Upstream might have given us a code already.
+	if code := languageCode(key); code != "" {
+		return code
+	}
+
+	if _, seen := _unmappedLanguages.LoadOrStore(lang, struct{}{}); !seen {
+		Log(context.Background()).Warn("unmapped language", "name", lang)
+	}
+	return lang
+}
+
+// _iso639_1 maps two-letter language codes, as used by ?lang= and
+// Accept-Language, to the three-letter codes we use for editions.
+var _iso639_1 = map[string]string{
+	"en": "eng",
+	"fr": "fra",
+	"es": "spa",
+	"de": "deu",
+	"it": "ita",
+	"da": "dan",
+	"nl": "nld",
+	"ja": "jpn",
+	"is": "isl",
+	"zh": "zho",
+	"ru": "rus",
+	"pl": "pol",
+	"vi": "vie",
+	"sv": "swe",
+	"no": "nor",
+	"nb": "nob",
+	"fi": "fin",
+	"tr": "tur",
+	"pt": "por",
+	"el": "ell",
+	"ko": "kor",
+	"hu": "hun",
+	"he": "heb",
+	"cs": "ces",
+	"hi": "hin",
+	"th": "tha",
+	"bg": "bul",
+	"ro": "ron",
+	"ar": "ara",
+	"uk": "ukr",
+}
+
+// languageCode normalizes a two- or three-letter language tag like "fr-CH" or
+// "fra" to its ISO 639-3 code. An empty string is returned if the tag isn't
+// recognized.
+func languageCode(tag string) string {
+	base, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(tag)), "-")
+	iso, ok := _iso639_1[base]
+	if !ok && len(base) == 3 {
+		iso, ok = base, true
+	}
+	if !ok {
+		return ""
+	}
+	if override, ok := _languageOverrides[iso]; ok {
+		return override
+	}
+	return iso
+}
+
+// _primaryLanguage is the ISO 639-3 code of the language preferred when
+// choosing and ordering editions. Empty means no preference.
+var _primaryLanguage string
+
+// SetPrimaryLanguage sets the language preferred when choosing and ordering
+// editions, as a two- or three-letter code. It should only be called during
+// startup.
+func SetPrimaryLanguage(tag string) error {
+	if tag == "" {
+		_primaryLanguage = ""
+		return nil
+	}
+	code := languageCode(tag)
+	if code == "" {
+		return fmt.Errorf("unrecognized language %q", tag)
+	}
+	_primaryLanguage = code
+	return nil
+}
+
+// primaryLanguage returns true if a primary language is configured and the
+// given ISO 639-3 code matches it.
+func primaryLanguage(code string) bool {
+	return _primaryLanguage != "" && code == _primaryLanguage
+}
+
+// preferredLanguage returns the ISO 639-3 code of the caller's preferred
+// language from the ?lang= param. An empty string means no preference.
+func preferredLanguage(r *http.Request) string {
+	return languageCode(r.URL.Query().Get("lang"))
+}
+
+// acceptLanguage returns the ISO 639-3 code of the caller's most preferred
+// language from the Accept-Language header. An empty string means no
+// preference.
+//
+// CDNs like Cloudflare ignore Vary: Accept-Language, so responses shouldn't
+// depend on the header directly. Callers should redirect to an equivalent
+// ?lang= URL instead.
+func acceptLanguage(r *http.Request) string {
+	type weighted struct {
+		code string
+		q    float64
 	}
-	return name
+	var prefs []weighted
+	for _, part := range strings.Split(r.Header.Get("Accept-Language"), ",") {
+		tag, params, _ := strings.Cut(part, ";")
+		code := languageCode(tag)
+		if code == "" {
+			continue
+		}
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		if q <= 0 {
+			continue
+		}
+		prefs = append(prefs, weighted{code: code, q: q})
+	}
+	if len(prefs) == 0 {
+		return ""
+	}
+
+	// Stable so earlier tags win ties, as per RFC 9110.
+	slices.SortStableFunc(prefs, func(a, b weighted) int {
+		return -cmp.Compare(a.q, b.q)
+	})
+	return prefs[0].code
+}
+
+// preferLanguage moves editions in the given language to the front while
+// otherwise preserving their order.
+func preferLanguage(books []bookResource, lang string) {
+	slices.SortStableFunc(books, func(a, b bookResource) int {
+		return compareBool(a.Language == lang, b.Language == lang)
+	})
 }
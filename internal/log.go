@@ -33,6 +33,11 @@ type Requestlogger struct{}
 // Wrap applies middleware.
 func (Requestlogger) Wrap(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if isDocsPath(r.URL.Path) {
+			next.ServeHTTP(w, r) // Not interesting.
+			return
+		}
+
 		ctx := r.Context()
 
 		attrs := []slog.Attr{
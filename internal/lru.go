@@ -0,0 +1,149 @@
+package internal
+
+import (
+	"container/list"
+	"sync"
+)
+
+// lru is a simple thread-safe, count-bounded LRU. It's meant for small
+// buffers where ristretto's probabilistic admission would get in the way.
+type lru[K comparable, V any] struct {
+	mu    sync.Mutex
+	size  int
+	order *list.List // Most recently used at the front.
+	items map[K]*list.Element
+
+	// keep, if set, protects entries from eviction. The lru may temporarily
+	// exceed its size while too many entries are kept.
+	keep func(V) bool
+
+	// limit, if positive, is a hard cap which applies even to kept entries.
+	// The oldest entries past it are evicted regardless, and dropped is called
+	// for every kept entry evicted this way. dropped must not call back into
+	// the lru.
+	limit   int
+	dropped func(K, V)
+}
+
+type lruEntry[K comparable, V any] struct {
+	key K
+	val V
+}
+
+func newLRU[K comparable, V any](size int) *lru[K, V] {
+	return &lru[K, V]{
+		size:  size,
+		order: list.New(),
+		items: map[K]*list.Element{},
+	}
+}
+
+// get returns the value for the key and marks it as recently used.
+func (l *lru[K, V]) get(key K) (V, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	e, ok := l.items[key]
+	if !ok {
+		var v V
+		return v, false
+	}
+	l.order.MoveToFront(e)
+	return e.Value.(*lruEntry[K, V]).val, true
+}
+
+// set adds or replaces the value for the key, evicting the least recently used
+// entries which aren't kept if we're over capacity.
+func (l *lru[K, V]) set(key K, val V) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if e, ok := l.items[key]; ok {
+		e.Value.(*lruEntry[K, V]).val = val
+		l.order.MoveToFront(e)
+		return
+	}
+
+	l.items[key] = l.order.PushFront(&lruEntry[K, V]{key: key, val: val})
+
+	for e := l.order.Back(); e != l.order.Front() && l.size > 0 && l.order.Len() > l.size; {
+		prev := e.Prev()
+		entry := e.Value.(*lruEntry[K, V])
+		if l.keep == nil || !l.keep(entry.val) {
+			l.order.Remove(e)
+			delete(l.items, entry.key)
+		}
+		e = prev
+	}
+
+	for l.limit > 0 && l.order.Len() > l.limit {
+		e := l.order.Back()
+		entry := e.Value.(*lruEntry[K, V])
+		l.order.Remove(e)
+		delete(l.items, entry.key)
+		if l.dropped != nil && l.keep != nil && l.keep(entry.val) {
+			l.dropped(entry.key, entry.val)
+		}
+	}
+}
+
+// update atomically modifies the value for an existing key without affecting
+// its recency. The value is left unchanged if fn returns false.
+func (l *lru[K, V]) update(key K, fn func(V) (V, bool)) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	e, ok := l.items[key]
+	if !ok {
+		return
+	}
+	entry := e.Value.(*lruEntry[K, V])
+	if val, ok := fn(entry.val); ok {
+		entry.val = val
+	}
+}
+
+// delete removes the key if it exists.
+func (l *lru[K, V]) delete(key K) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if e, ok := l.items[key]; ok {
+		l.order.Remove(e)
+		delete(l.items, key)
+	}
+}
+
+// take removes and returns the value for the key.
+func (l *lru[K, V]) take(key K) (V, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	e, ok := l.items[key]
+	if !ok {
+		var v V
+		return v, false
+	}
+	l.order.Remove(e)
+	delete(l.items, key)
+	return e.Value.(*lruEntry[K, V]).val, true
+}
+
+// len returns the number of entries currently held.
+func (l *lru[K, V]) len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.order.Len()
+}
+
+// each calls fn for every entry from most to least recently used without
+// affecting recency. fn must not call back into the lru.
+func (l *lru[K, V]) each(fn func(K, V)) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for e := l.order.Front(); e != nil; e = e.Next() {
+		entry := e.Value.(*lruEntry[K, V])
+		fn(entry.key, entry.val)
+	}
+}
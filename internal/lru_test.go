@@ -0,0 +1,88 @@
+package internal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLRU(t *testing.T) {
+	l := newLRU[string, int](2)
+
+	l.set("a", 1)
+	l.set("b", 2)
+
+	// Touch "a" so "b" becomes the oldest.
+	v, ok := l.get("a")
+	assert.True(t, ok)
+	assert.Equal(t, 1, v)
+
+	l.set("c", 3)
+	assert.Equal(t, 2, l.len())
+
+	_, ok = l.get("b")
+	assert.False(t, ok, "least recently used should be evicted")
+
+	l.update("a", func(v int) (int, bool) { return v + 10, true })
+	v, _ = l.get("a")
+	assert.Equal(t, 11, v)
+
+	l.update("a", func(v int) (int, bool) { return 0, false })
+	v, _ = l.get("a")
+	assert.Equal(t, 11, v, "update should no-op")
+
+	l.delete("a")
+	_, ok = l.get("a")
+	assert.False(t, ok)
+	assert.Equal(t, 1, l.len())
+}
+
+func TestLRUKeep(t *testing.T) {
+	l := newLRU[string, int](1)
+	l.keep = func(v int) bool { return v < 0 }
+
+	l.set("kept", -1)
+	l.set("a", 1)
+	l.set("b", 2)
+
+	// Kept entries survive eviction even when they're the oldest.
+	v, ok := l.get("kept")
+	assert.True(t, ok)
+	assert.Equal(t, -1, v)
+
+	_, ok = l.get("a")
+	assert.False(t, ok)
+	assert.Equal(t, 2, l.len())
+
+	// Once it's no longer kept it's evicted normally.
+	l.update("kept", func(int) (int, bool) { return 0, true })
+	l.set("c", 3)
+	l.set("d", 4)
+	_, ok = l.get("kept")
+	assert.False(t, ok)
+}
+
+func TestLRULimit(t *testing.T) {
+	l := newLRU[string, int](1)
+	l.keep = func(v int) bool { return v < 0 }
+	l.limit = 2
+	dropped := []string{}
+	l.dropped = func(k string, _ int) { dropped = append(dropped, k) }
+
+	l.set("a", -1)
+	l.set("b", -2)
+	l.set("c", -3)
+
+	// The oldest kept entry is dropped once we're past the hard limit.
+	assert.Equal(t, 2, l.len())
+	_, ok := l.get("a")
+	assert.False(t, ok)
+	assert.Equal(t, []string{"a"}, dropped)
+
+	// Entries which aren't kept are evicted without being reported.
+	l.update("b", func(int) (int, bool) { return 2, true })
+	l.set("d", -4)
+	_, ok = l.get("b")
+	assert.False(t, ok)
+	assert.Equal(t, []string{"a"}, dropped)
+}
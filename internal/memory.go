@@ -10,12 +10,22 @@ import (
 
 var _ cache[[]byte] = (*memoryCache)(nil)
 
-// newMemoryCache returns a new in-memory cache.
+// newMemoryCache returns a new in-memory cache using up to half of the
+// memory limit.
 func newMemoryCache() cache[[]byte] {
+	return newSizedMemoryCache(0)
+}
+
+// newSizedMemoryCache returns a new in-memory cache holding up to maxBytes of
+// values. Non-positive sizes use half of the memory limit.
+func newSizedMemoryCache(maxBytes int64) *memoryCache {
+	if maxBytes <= 0 {
+		maxBytes = debug.SetMemoryLimit(-1) / 2 // Use 50% of available memory.
+	}
 	r, err := ristretto.NewCache(&ristretto.Config[string, []byte]{
-		NumCounters: 4e6,                          // Track LRU for up to 4M keys which is ~10x in-memory items.
-		MaxCost:     debug.SetMemoryLimit(-1) / 2, // Use 50% of available memory.
-		BufferItems: 64,                           // Number of keys per Get buffer.
+		NumCounters: 4e6,      // Track LRU for up to 4M keys which is ~10x in-memory items.
+		MaxCost:     maxBytes, // Values are costed by their size.
+		BufferItems: 64,       // Number of keys per Get buffer.
 	})
 	if err != nil {
 		panic(err)
@@ -43,6 +53,12 @@ func (c *memoryCache) Set(_ context.Context, key string, value []byte, ttl time.
 	c.r.Wait() // Synchronous set.
 }
 
+// size returns roughly how many bytes are cached, including per-item
+// overhead.
+func (c *memoryCache) size() int64 {
+	return c.r.MaxCost() - c.r.RemainingCost()
+}
+
 func (c *memoryCache) Expire(_ context.Context, key string) error {
 	c.r.Del(key)
 	c.r.Wait() // Synchronous delete.
@@ -2,6 +2,7 @@ package internal
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"regexp"
@@ -41,8 +42,10 @@ var _metricsNamespace = "rg"
 var _patternRE = regexp.MustCompile(`\{[^/]+\}`)
 
 type controllerMetrics struct {
-	totals *prometheus.CounterVec
-	gauge  *prometheus.GaugeVec
+	totals    *prometheus.CounterVec
+	gauge     *prometheus.GaugeVec
+	series    prometheus.Histogram
+	heartbeat prometheus.Gauge
 }
 
 type cacheMetrics struct {
@@ -59,9 +62,16 @@ type cloudflareMetrics struct {
 	gauge  *prometheus.GaugeVec
 }
 
+type upstreamMetrics struct {
+	totals  *prometheus.CounterVec
+	results *prometheus.CounterVec
+}
+
 type dbMetrics struct {
-	dirty atomic.Bool // dirty signals that the DB has been modified so stats should be collected.
-	gauge *prometheus.GaugeVec
+	dirty  atomic.Bool // dirty signals that the DB has been modified so stats should be collected.
+	gauge  *prometheus.GaugeVec
+	totals *prometheus.CounterVec
+	reaped prometheus.Counter
 }
 
 // instrument wraps an HTTP handler to automatically record timing and status
@@ -136,12 +146,31 @@ func newControllerMetrics(reg *prometheus.Registry) *controllerMetrics {
 		},
 		[]string{"type"},
 	)
+	series := prometheus.NewHistogram(
+		prometheus.HistogramOpts{
+			Namespace: _metricsNamespace,
+			Subsystem: "controller",
+			Name:      "series_fetched",
+			Help:      "How many series were fetched per author denormalization.",
+			Buckets:   []float64{0, 1, 5, 10, 25, 50, 100, 250},
+		},
+	)
+	heartbeat := prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: _metricsNamespace,
+			Subsystem: "controller",
+			Name:      "denormalization_heartbeat_seconds",
+			Help:      "Unix time denormalization last made progress or was seen idle. A stale value means it's stuck.",
+		},
+	)
 	if reg != nil {
-		reg.MustRegister(totals, gauge)
+		reg.MustRegister(totals, gauge, series, heartbeat)
 	}
 	return &controllerMetrics{
-		totals: totals,
-		gauge:  gauge,
+		totals:    totals,
+		gauge:     gauge,
+		series:    series,
+		heartbeat: heartbeat,
 	}
 }
 
@@ -211,7 +240,34 @@ func newCloudflareMetrics(reg *prometheus.Registry) *cloudflareMetrics {
 	return &cloudflareMetrics{totals: totals, gauge: gauge}
 }
 
-func newDBMetrics(db *pgxpool.Pool, reg *prometheus.Registry) *dbMetrics {
+func newUpstreamMetrics(reg *prometheus.Registry) *upstreamMetrics {
+	totals := prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: _metricsNamespace,
+			Subsystem: "upstream",
+			Name:      "total",
+			Help:      "Counts of upstream responses by type.",
+		},
+		[]string{"type"},
+	)
+	results := prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: _metricsNamespace,
+			Subsystem: "getter",
+			Name:      "total",
+			Help:      "Counts of getter calls by method and outcome: ok, not_found, invalid or error.",
+		},
+		[]string{"method", "outcome"},
+	)
+	if reg != nil {
+		reg.MustRegister(totals, results)
+	}
+	return &upstreamMetrics{totals: totals, results: results}
+}
+
+// newDBMetrics registers DB metrics and periodically collects stats from the
+// DB until the context is cancelled.
+func newDBMetrics(ctx context.Context, db *pgxpool.Pool, reg *prometheus.Registry) *dbMetrics {
 	gauge := prometheus.NewGaugeVec(
 		prometheus.GaugeOpts{
 			Namespace: _metricsNamespace,
@@ -221,16 +277,35 @@ func newDBMetrics(db *pgxpool.Pool, reg *prometheus.Registry) *dbMetrics {
 		},
 		[]string{"type"},
 	)
+	totals := prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: _metricsNamespace,
+			Subsystem: "db",
+			Name:      "buffer_total",
+			Help:      "Totals for the in-memory write buffer in front of the DB.",
+		},
+		[]string{"type"},
+	)
+	reaped := prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: _metricsNamespace,
+			Subsystem: "db",
+			Name:      "reaped_total",
+			Help:      "Expired rows deleted by compaction.",
+		},
+	)
 	if reg != nil {
-		reg.MustRegister(gauge, pgxpoolprometheus.NewCollector(db, nil))
+		reg.MustRegister(gauge, totals, reaped, pgxpoolprometheus.NewCollector(db, nil))
 	}
-	dbm := &dbMetrics{gauge: gauge}
+	dbm := &dbMetrics{gauge: gauge, totals: totals, reaped: reaped}
 	// This is an expensive query so we only run it every 5 minutes,
 	// and only if there's been some DB activity that changed the
 	// relevant stats.
 	dbm.dirty.Store(true) // Start dirty to trigger an initial query.
 	go func() {
-		ctx := context.Background()
+		ctx := context.WithValue(ctx, middleware.RequestIDKey, "db-stats")
+		ticker := time.NewTicker(5 * time.Minute)
+		defer ticker.Stop()
 		for {
 			row := db.QueryRow(ctx, `
 			  SELECT
@@ -257,10 +332,14 @@ func newDBMetrics(db *pgxpool.Pool, reg *prometheus.Registry) *dbMetrics {
 				dbm.isbnSet(isbn)
 			}
 			dbm.dirty.Store(false)
-			time.Sleep(5 * time.Minute)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
 		}
 	}()
-	return &dbMetrics{gauge: gauge}
+	return dbm
 }
 
 func (dbm *dbMetrics) authorsSet(n int64) {
@@ -291,6 +370,30 @@ func (dbm *dbMetrics) seriesSet(n int64) {
 	dbm.gauge.WithLabelValues("series").Set(float64(n))
 }
 
+func (dbm *dbMetrics) reapedAdd(n int64) {
+	if n == 0 {
+		return
+	}
+	dbm.reaped.Add(float64(n))
+	dbm.dirty.Store(true)
+}
+
+func (dbm *dbMetrics) bufferHitsInc() {
+	dbm.totals.WithLabelValues("hits").Inc()
+}
+
+func (dbm *dbMetrics) bufferDeferredInc() {
+	dbm.totals.WithLabelValues("deferred").Inc()
+}
+
+func (dbm *dbMetrics) bufferFlushedInc() {
+	dbm.totals.WithLabelValues("flushed").Inc()
+}
+
+func (dbm *dbMetrics) bufferDroppedInc() {
+	dbm.totals.WithLabelValues("dropped").Inc()
+}
+
 func (cm *controllerMetrics) denormWaitingSet(n int) {
 	cm.gauge.WithLabelValues("denormalization").Set(float64(n))
 }
@@ -320,6 +423,219 @@ func (cm *controllerMetrics) refreshWaitingGet() float64 {
 	return m.GetGauge().GetValue()
 }
 
+func (cm *controllerMetrics) inflightKeysAdd(delta int64) {
+	cm.gauge.WithLabelValues("inflight_keys").Add(float64(delta))
+}
+
+func (cm *controllerMetrics) inflightKeysGet() float64 {
+	m := &dto.Metric{}
+	err := cm.gauge.WithLabelValues("inflight_keys").Write(m)
+	if err != nil {
+		return 0.0
+	}
+	return m.GetGauge().GetValue()
+}
+
+func (cm *controllerMetrics) coalescedPanicsInc() {
+	cm.totals.WithLabelValues("coalesced_panics").Inc()
+}
+
+func (cm *controllerMetrics) coalescedPanicsGet() float64 {
+	m := &dto.Metric{}
+	err := cm.totals.WithLabelValues("coalesced_panics").Write(m)
+	if err != nil {
+		return 0.0
+	}
+	return m.GetCounter().GetValue()
+}
+
+func (cm *controllerMetrics) denormRetryWaitingAdd(delta int64) {
+	cm.gauge.WithLabelValues("denormalization_retry").Add(float64(delta))
+}
+
+func (cm *controllerMetrics) denormRetryWaitingGet() float64 {
+	m := &dto.Metric{}
+	err := cm.gauge.WithLabelValues("denormalization_retry").Write(m)
+	if err != nil {
+		return 0.0
+	}
+	return m.GetGauge().GetValue()
+}
+
+func (cm *controllerMetrics) denormExhaustedInc() {
+	cm.totals.WithLabelValues("denormalization_exhausted").Inc()
+}
+
+func (cm *controllerMetrics) denormExhaustedGet() float64 {
+	m := &dto.Metric{}
+	err := cm.totals.WithLabelValues("denormalization_exhausted").Write(m)
+	if err != nil {
+		return 0.0
+	}
+	return m.GetCounter().GetValue()
+}
+
+func (cm *controllerMetrics) refreshPausedSet(paused bool) {
+	v := 0.0
+	if paused {
+		v = 1.0
+	}
+	cm.gauge.WithLabelValues("refresh_paused").Set(v)
+}
+
+func (cm *controllerMetrics) refreshPausedGet() float64 {
+	m := &dto.Metric{}
+	err := cm.gauge.WithLabelValues("refresh_paused").Write(m)
+	if err != nil {
+		return 0.0
+	}
+	return m.GetGauge().GetValue()
+}
+
+func (cm *controllerMetrics) denormHeartbeatSet(t time.Time) {
+	cm.heartbeat.Set(float64(t.Unix()))
+}
+
+func (cm *controllerMetrics) denormHeartbeatGet() float64 {
+	m := &dto.Metric{}
+	err := cm.heartbeat.Write(m)
+	if err != nil {
+		return 0.0
+	}
+	return m.GetGauge().GetValue()
+}
+
+func (cm *controllerMetrics) seriesFetchedObserve(n int) {
+	cm.series.Observe(float64(n))
+}
+
+func (cm *controllerMetrics) nullsRepairedInc() {
+	cm.totals.WithLabelValues("nulls_repaired").Inc()
+}
+
+func (cm *controllerMetrics) nullsRepairedGet() float64 {
+	m := &dto.Metric{}
+	err := cm.totals.WithLabelValues("nulls_repaired").Write(m)
+	if err != nil {
+		return 0.0
+	}
+	return m.GetCounter().GetValue()
+}
+
+func (cm *controllerMetrics) consumerRestartsInc() {
+	cm.totals.WithLabelValues("consumer_restarts").Inc()
+}
+
+func (cm *controllerMetrics) consumerRestartsGet() float64 {
+	m := &dto.Metric{}
+	err := cm.totals.WithLabelValues("consumer_restarts").Write(m)
+	if err != nil {
+		return 0.0
+	}
+	return m.GetCounter().GetValue()
+}
+
+func (cm *controllerMetrics) notificationsSentAdd(n int) {
+	if n == 0 {
+		return
+	}
+	cm.totals.WithLabelValues("notifications_sent").Add(float64(n))
+}
+
+func (cm *controllerMetrics) notificationsSentGet() float64 {
+	m := &dto.Metric{}
+	err := cm.totals.WithLabelValues("notifications_sent").Write(m)
+	if err != nil {
+		return 0.0
+	}
+	return m.GetCounter().GetValue()
+}
+
+func (cm *controllerMetrics) notificationsDroppedAdd(n int) {
+	if n == 0 {
+		return
+	}
+	cm.totals.WithLabelValues("notifications_dropped").Add(float64(n))
+}
+
+func (cm *controllerMetrics) notificationsDroppedGet() float64 {
+	m := &dto.Metric{}
+	err := cm.totals.WithLabelValues("notifications_dropped").Write(m)
+	if err != nil {
+		return 0.0
+	}
+	return m.GetCounter().GetValue()
+}
+
+func (cm *controllerMetrics) editionsRevalidatedInc() {
+	cm.totals.WithLabelValues("editions_revalidated").Inc()
+}
+
+func (cm *controllerMetrics) editionsRevalidatedGet() float64 {
+	m := &dto.Metric{}
+	err := cm.totals.WithLabelValues("editions_revalidated").Write(m)
+	if err != nil {
+		return 0.0
+	}
+	return m.GetCounter().GetValue()
+}
+
+func (cm *controllerMetrics) editionsRevalidationSkippedInc() {
+	cm.totals.WithLabelValues("editions_revalidation_skipped").Inc()
+}
+
+func (cm *controllerMetrics) editionsRevalidationSkippedGet() float64 {
+	m := &dto.Metric{}
+	err := cm.totals.WithLabelValues("editions_revalidation_skipped").Write(m)
+	if err != nil {
+		return 0.0
+	}
+	return m.GetCounter().GetValue()
+}
+
+func (cm *controllerMetrics) staleServedInc() {
+	cm.totals.WithLabelValues("stale_served").Inc()
+}
+
+func (cm *controllerMetrics) refreshesDedupedInc() {
+	cm.totals.WithLabelValues("refreshes_deduplicated").Inc()
+}
+
+func (cm *controllerMetrics) refreshesDedupedGet() float64 {
+	m := &dto.Metric{}
+	err := cm.totals.WithLabelValues("refreshes_deduplicated").Write(m)
+	if err != nil {
+		return 0.0
+	}
+	return m.GetCounter().GetValue()
+}
+
+func (cm *controllerMetrics) editionsExcludedInc() {
+	cm.totals.WithLabelValues("editions_excluded").Inc()
+}
+
+func (cm *controllerMetrics) editionsExcludedGet() float64 {
+	m := &dto.Metric{}
+	err := cm.totals.WithLabelValues("editions_excluded").Write(m)
+	if err != nil {
+		return 0
+	}
+	return m.Counter.GetValue()
+}
+
+func (cm *controllerMetrics) authorDecodesSkippedInc() {
+	cm.totals.WithLabelValues("author_decodes_skipped").Inc()
+}
+
+func (cm *controllerMetrics) authorDecodesSkippedGet() float64 {
+	m := &dto.Metric{}
+	err := cm.totals.WithLabelValues("author_decodes_skipped").Write(m)
+	if err != nil {
+		return 0.0
+	}
+	return m.GetCounter().GetValue()
+}
+
 func (cm *controllerMetrics) etagMatchesInc() {
 	cm.totals.WithLabelValues("etag_matches").Inc()
 }
@@ -392,6 +708,59 @@ func (cm *cacheMetrics) cacheHitRatioGet() float64 {
 	return ratio
 }
 
+// memoryResultInc records whether a read was served by the in-memory layer,
+// which is always the first.
+func (cm *cacheMetrics) memoryResultInc(hit bool) {
+	if hit {
+		cm.totals.WithLabelValues("memory_hits").Inc()
+		return
+	}
+	cm.totals.WithLabelValues("memory_misses").Inc()
+}
+
+func (cm *cacheMetrics) memoryHitRatioGet() float64 {
+	m := &dto.Metric{}
+	if err := cm.totals.WithLabelValues("memory_hits").Write(m); err != nil {
+		return 0.0
+	}
+	hits := m.GetCounter().GetValue()
+	if err := cm.totals.WithLabelValues("memory_misses").Write(m); err != nil {
+		return 0.0
+	}
+	misses := m.GetCounter().GetValue()
+	if hits+misses == 0 {
+		return 0.0
+	}
+	return hits / (hits + misses)
+}
+
+// registerMemoryCacheGauges reports the in-memory layer's size and hit ratio.
+func registerMemoryCacheGauges(reg *prometheus.Registry, cm *cacheMetrics, size func() int64) {
+	if reg == nil {
+		return
+	}
+	reg.MustRegister(
+		prometheus.NewGaugeFunc(
+			prometheus.GaugeOpts{
+				Namespace: _metricsNamespace,
+				Subsystem: "cache",
+				Name:      "memory_bytes",
+				Help:      "Approximate bytes held by the in-memory cache.",
+			},
+			func() float64 { return float64(size()) },
+		),
+		prometheus.NewGaugeFunc(
+			prometheus.GaugeOpts{
+				Namespace: _metricsNamespace,
+				Subsystem: "cache",
+				Name:      "memory_hit_ratio",
+				Help:      "Fraction of reads served by the in-memory cache.",
+			},
+			cm.memoryHitRatioGet,
+		),
+	)
+}
+
 func (gm *gqlMetrics) batchesSentInc() {
 	gm.totals.WithLabelValues("batches_sent").Inc()
 }
@@ -434,6 +803,26 @@ func (gm *gqlMetrics) batchesWaitingGet() int {
 	return int(m.GetCounter().GetValue())
 }
 
+func (gm *gqlMetrics) authBackoffsInc() {
+	gm.totals.WithLabelValues("auth_backoffs").Inc()
+}
+
+func (gm *gqlMetrics) authBackoffsGet() int64 {
+	m := &dto.Metric{}
+	err := gm.totals.WithLabelValues("auth_backoffs").Write(m)
+	if err != nil {
+		return 0
+	}
+	return int64(m.GetCounter().GetValue())
+}
+
+func (gm *gqlMetrics) queriesRejectedAdd(delta int64) {
+	if delta <= 0 {
+		return
+	}
+	gm.totals.WithLabelValues("queries_rejected").Add(float64(delta))
+}
+
 func (cm *cloudflareMetrics) urlsBustedAdd(delta int) {
 	if delta <= 0 {
 		return
@@ -454,6 +843,44 @@ func (cm cloudflareMetrics) batchesWaitingGet() int {
 	return int(m.GetCounter().GetValue())
 }
 
+func (um *upstreamMetrics) malformedInc() {
+	um.totals.WithLabelValues("malformed").Inc()
+}
+
+func (um *upstreamMetrics) malformedGet() int64 {
+	m := &dto.Metric{}
+	err := um.totals.WithLabelValues("malformed").Write(m)
+	if err != nil {
+		return 0
+	}
+	return int64(m.GetCounter().GetValue())
+}
+
+// resultInc records the outcome of a getter method. Upstream 404s are
+// "not_found", data we rejected is "invalid", and anything else is "error".
+func (um *upstreamMetrics) resultInc(method string, err error) {
+	outcome := "ok"
+	switch {
+	case err == nil:
+	case errors.Is(err, errInvalid), errors.Is(err, errMalformed):
+		outcome = "invalid"
+	case errors.Is(err, errNotFound):
+		outcome = "not_found"
+	default:
+		outcome = "error"
+	}
+	um.results.WithLabelValues(method, outcome).Inc()
+}
+
+func (um *upstreamMetrics) resultGet(method, outcome string) float64 {
+	m := &dto.Metric{}
+	err := um.results.WithLabelValues(method, outcome).Write(m)
+	if err != nil {
+		return 0
+	}
+	return m.GetCounter().GetValue()
+}
+
 // normalizePattern derives the constant label from the pattern:
 //
 //	"/author/{foreignAuthorID}" → "/author"
@@ -464,3 +891,29 @@ func normalizePattern(pattern string) string {
 	p = strings.ReplaceAll(p, "//", "/")
 	return p
 }
+
+func (cm *controllerMetrics) backgroundAdd(delta int64) {
+	cm.gauge.WithLabelValues("background_goroutines").Add(float64(delta))
+}
+
+func (cm *controllerMetrics) backgroundGet() float64 {
+	m := &dto.Metric{}
+	err := cm.gauge.WithLabelValues("background_goroutines").Write(m)
+	if err != nil {
+		return 0.0
+	}
+	return m.GetGauge().GetValue()
+}
+
+func (cm *controllerMetrics) backgroundDroppedInc() {
+	cm.totals.WithLabelValues("background_dropped").Inc()
+}
+
+func (cm *controllerMetrics) backgroundDroppedGet() float64 {
+	m := &dto.Metric{}
+	err := cm.totals.WithLabelValues("background_dropped").Write(m)
+	if err != nil {
+		return 0.0
+	}
+	return m.GetCounter().GetValue()
+}
@@ -17,6 +17,132 @@ import (
 	gomock "go.uber.org/mock/gomock"
 )
 
+// MockauthorStubber is a mock of authorStubber interface.
+type MockauthorStubber struct {
+	ctrl     *gomock.Controller
+	recorder *MockauthorStubberMockRecorder
+	isgomock struct{}
+}
+
+// MockauthorStubberMockRecorder is the mock recorder for MockauthorStubber.
+type MockauthorStubberMockRecorder struct {
+	mock *MockauthorStubber
+}
+
+// NewMockauthorStubber creates a new mock instance.
+func NewMockauthorStubber(ctrl *gomock.Controller) *MockauthorStubber {
+	mock := &MockauthorStubber{ctrl: ctrl}
+	mock.recorder = &MockauthorStubberMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockauthorStubber) EXPECT() *MockauthorStubberMockRecorder {
+	return m.recorder
+}
+
+// GetAuthorStub mocks base method.
+func (m *MockauthorStubber) GetAuthorStub(ctx context.Context, authorID int64) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAuthorStub", ctx, authorID)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAuthorStub indicates an expected call of GetAuthorStub.
+func (mr *MockauthorStubberMockRecorder) GetAuthorStub(ctx, authorID any) *MockauthorStubberGetAuthorStubCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuthorStub", reflect.TypeOf((*MockauthorStubber)(nil).GetAuthorStub), ctx, authorID)
+	return &MockauthorStubberGetAuthorStubCall{Call: call}
+}
+
+// MockauthorStubberGetAuthorStubCall wrap *gomock.Call
+type MockauthorStubberGetAuthorStubCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockauthorStubberGetAuthorStubCall) Return(arg0 []byte, arg1 error) *MockauthorStubberGetAuthorStubCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockauthorStubberGetAuthorStubCall) Do(f func(context.Context, int64) ([]byte, error)) *MockauthorStubberGetAuthorStubCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockauthorStubberGetAuthorStubCall) DoAndReturn(f func(context.Context, int64) ([]byte, error)) *MockauthorStubberGetAuthorStubCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// MockslugResolver is a mock of slugResolver interface.
+type MockslugResolver struct {
+	ctrl     *gomock.Controller
+	recorder *MockslugResolverMockRecorder
+	isgomock struct{}
+}
+
+// MockslugResolverMockRecorder is the mock recorder for MockslugResolver.
+type MockslugResolverMockRecorder struct {
+	mock *MockslugResolver
+}
+
+// NewMockslugResolver creates a new mock instance.
+func NewMockslugResolver(ctrl *gomock.Controller) *MockslugResolver {
+	mock := &MockslugResolver{ctrl: ctrl}
+	mock.recorder = &MockslugResolverMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockslugResolver) EXPECT() *MockslugResolverMockRecorder {
+	return m.recorder
+}
+
+// GetWorkIDBySlug mocks base method.
+func (m *MockslugResolver) GetWorkIDBySlug(ctx context.Context, slug string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkIDBySlug", ctx, slug)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkIDBySlug indicates an expected call of GetWorkIDBySlug.
+func (mr *MockslugResolverMockRecorder) GetWorkIDBySlug(ctx, slug any) *MockslugResolverGetWorkIDBySlugCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkIDBySlug", reflect.TypeOf((*MockslugResolver)(nil).GetWorkIDBySlug), ctx, slug)
+	return &MockslugResolverGetWorkIDBySlugCall{Call: call}
+}
+
+// MockslugResolverGetWorkIDBySlugCall wrap *gomock.Call
+type MockslugResolverGetWorkIDBySlugCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockslugResolverGetWorkIDBySlugCall) Return(arg0 int64, arg1 error) *MockslugResolverGetWorkIDBySlugCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockslugResolverGetWorkIDBySlugCall) Do(f func(context.Context, string) (int64, error)) *MockslugResolverGetWorkIDBySlugCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockslugResolverGetWorkIDBySlugCall) DoAndReturn(f func(context.Context, string) (int64, error)) *MockslugResolverGetWorkIDBySlugCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
 // Mockgetter is a mock of getter interface.
 type Mockgetter struct {
 	ctrl     *gomock.Controller
@@ -239,18 +365,18 @@ func (c *MockgetterGetWorkCall) DoAndReturn(f func(context.Context, int64, editi
 }
 
 // Recommendations mocks base method.
-func (m *Mockgetter) Recommendations(ctx context.Context, page int64) (RecommentationsResource, error) {
+func (m *Mockgetter) Recommendations(ctx context.Context, page int64, filter recommendationsFilter) (RecommentationsResource, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Recommendations", ctx, page)
+	ret := m.ctrl.Call(m, "Recommendations", ctx, page, filter)
 	ret0, _ := ret[0].(RecommentationsResource)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Recommendations indicates an expected call of Recommendations.
-func (mr *MockgetterMockRecorder) Recommendations(ctx, page any) *MockgetterRecommendationsCall {
+func (mr *MockgetterMockRecorder) Recommendations(ctx, page, filter any) *MockgetterRecommendationsCall {
 	mr.mock.ctrl.T.Helper()
-	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Recommendations", reflect.TypeOf((*Mockgetter)(nil).Recommendations), ctx, page)
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Recommendations", reflect.TypeOf((*Mockgetter)(nil).Recommendations), ctx, page, filter)
 	return &MockgetterRecommendationsCall{Call: call}
 }
 
@@ -266,13 +392,13 @@ func (c *MockgetterRecommendationsCall) Return(arg0 RecommentationsResource, arg
 }
 
 // Do rewrite *gomock.Call.Do
-func (c *MockgetterRecommendationsCall) Do(f func(context.Context, int64) (RecommentationsResource, error)) *MockgetterRecommendationsCall {
+func (c *MockgetterRecommendationsCall) Do(f func(context.Context, int64, recommendationsFilter) (RecommentationsResource, error)) *MockgetterRecommendationsCall {
 	c.Call = c.Call.Do(f)
 	return c
 }
 
 // DoAndReturn rewrite *gomock.Call.DoAndReturn
-func (c *MockgetterRecommendationsCall) DoAndReturn(f func(context.Context, int64) (RecommentationsResource, error)) *MockgetterRecommendationsCall {
+func (c *MockgetterRecommendationsCall) DoAndReturn(f func(context.Context, int64, recommendationsFilter) (RecommentationsResource, error)) *MockgetterRecommendationsCall {
 	c.Call = c.Call.DoAndReturn(f)
 	return c
 }
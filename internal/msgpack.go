@@ -0,0 +1,96 @@
+package internal
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// _msgpackMaxBytes bounds how large a cached JSON payload can be before we
+// stop offering it as MessagePack. Converting requires decoding and
+// re-encoding the whole resource, which costs tens of milliseconds per
+// megabyte, and the largest authors aren't worth the CPU.
+var _msgpackMaxBytes = 4 << 20
+
+// acceptsMsgpack returns true if the client asked for MessagePack in its
+// Accept header.
+//
+// CDNs like Cloudflare ignore Vary: Accept, so responses shouldn't depend on
+// the header directly. Callers should redirect to an equivalent
+// ?format=msgpack URL instead.
+func acceptsMsgpack(r *http.Request) bool {
+	for _, accept := range r.Header.Values("Accept") {
+		for part := range strings.SplitSeq(accept, ",") {
+			mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(part))
+			if err != nil {
+				continue
+			}
+			if mediaType == "application/msgpack" || mediaType == "application/x-msgpack" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// wantsMsgpack returns true if the request is for ?format=msgpack.
+func wantsMsgpack(r *http.Request) bool {
+	return r.URL.Query().Get("format") == "msgpack"
+}
+
+// redirectMsgpack redirects clients which accept MessagePack to the
+// equivalent ?format=msgpack URL, which CDNs can cache separately from JSON.
+// The redirect itself depends on the header so it isn't shared. Returns true
+// if the request was redirected.
+func (h *Handler) redirectMsgpack(w http.ResponseWriter, r *http.Request) bool {
+	if r.URL.Query().Has("format") || !acceptsMsgpack(r) {
+		return false
+	}
+	query := r.URL.Query()
+	query.Set("format", "msgpack")
+	target := url.URL{Path: h.link(r.URL.Path), RawQuery: query.Encode()}
+	w.Header().Set("Cache-Control", "private")
+	vary(w, "Accept")
+	http.Redirect(w, r, target.String(), http.StatusSeeOther)
+	return true
+}
+
+// negotiate returns the response body in the format the client asked for,
+// setting the corresponding Content-Type. Cached payloads are JSON encodings
+// of T, which are returned unchanged unless the client asked for
+// ?format=msgpack and the payload isn't too large to convert.
+func negotiate[T any](w http.ResponseWriter, r *http.Request, out []byte) []byte {
+	if !wantsMsgpack(r) || len(out) > _msgpackMaxBytes {
+		return out
+	}
+	packed, err := jsonToMsgpack[T](out)
+	if err != nil {
+		Log(r.Context()).Warn("unable to convert to msgpack", "err", err)
+		return out
+	}
+	w.Header().Set("Content-Type", "application/msgpack")
+	return packed
+}
+
+// jsonToMsgpack re-encodes a JSON encoding of T as MessagePack. Fields keep
+// their JSON names and are encoded in the order they're declared.
+func jsonToMsgpack[T any](data []byte) ([]byte, error) {
+	var v T
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil, err
+	}
+
+	buf := &bytes.Buffer{}
+	enc := msgpack.NewEncoder(buf)
+	enc.SetCustomStructTag("json")
+	enc.UseCompactInts(true)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
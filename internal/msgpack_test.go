@@ -0,0 +1,182 @@
+package internal
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vmihailenco/msgpack/v5"
+	"go.uber.org/mock/gomock"
+)
+
+func TestJSONToMsgpack(t *testing.T) {
+	book := bookResource{
+		ForeignID:          1,
+		Asin:               "B000000001",
+		Description:        "A <b>long</b> description — with “quotes” and émoji 📚.",
+		Isbn13:             "9780441172719",
+		Title:              "Dune",
+		FullTitle:          "Dune: Deluxe Edition",
+		ShortTitle:         "Dune",
+		Language:           "eng",
+		Format:             "Hardcover",
+		EditionInformation: "Deluxe",
+		Publisher:          "Ace",
+		ImageURL:           "https://assets.hardcover.app/editions/1/cover.jpg",
+		NumPages:           896,
+		RatingCount:        1_234_567,
+		AverageRating:      4.27,
+		URL:                "https://hardcover.app/books/dune",
+		ReleaseDate:        "1965-08-01 00:00:00",
+		ReleaseDateRaw:     "1965-08-01",
+		Contributors:       []contributorResource{{ForeignID: 100, Role: "Author"}},
+		KCA:                "kca://book/1",
+		RatingSum:          5_271_601,
+	}
+	work := workResource{
+		ForeignID:     10,
+		Title:         "Dune",
+		FullTitle:     "Dune",
+		ShortTitle:    "Dune",
+		URL:           "https://hardcover.app/books/dune",
+		ReleaseDate:   "1965-08-01 00:00:00",
+		Genres:        []string{"Science Fiction", "Classics"},
+		RelatedWorks:  []int{},
+		Books:         []bookResource{book, {ForeignID: 2, Title: "Dune", Contributors: []contributorResource{}}},
+		Series:        []SeriesResource{{ForeignID: 1000, Title: "Dune", LinkItems: []seriesWorkLinkResource{{ForeignWorkID: 10, PositionInSeries: "1", SeriesPosition: 1, Primary: true}}}},
+		KCA:           "kca://work/10",
+		BestBookID:    1,
+		RatingCount:   1_234_567,
+		AverageRating: 4.27,
+		RatingSum:     5_271_601,
+	}
+	author := AuthorResource{
+		ForeignID:     100,
+		Name:          "Frank Herbert",
+		Description:   "American science fiction author.",
+		ImageURL:      "https://assets.hardcover.app/authors/100.jpg",
+		URL:           "https://hardcover.app/authors/frank-herbert",
+		RatingCount:   3_000_000,
+		AverageRating: 4.1,
+		Works:         []workResource{work},
+		Series:        work.Series,
+		KCA:           "kca://author/100",
+		Aliases:       []AuthorAlias{{ForeignID: 101, Name: "F. Herbert"}},
+		Genres:        []string{"Science Fiction"},
+	}
+	work.Authors = []AuthorResource{{ForeignID: 100, Name: "Frank Herbert"}}
+
+	t.Run("author", func(t *testing.T) {
+		testMsgpackRoundTrip(t, author)
+	})
+	t.Run("work", func(t *testing.T) {
+		testMsgpackRoundTrip(t, work)
+	})
+
+	_, err := jsonToMsgpack[AuthorResource]([]byte(`{"ForeignId":`))
+	assert.Error(t, err)
+}
+
+// testMsgpackRoundTrip checks that a resource converted from JSON to
+// MessagePack decodes to the same resource, with the same field names.
+func testMsgpackRoundTrip[T any](t *testing.T, want T) {
+	t.Helper()
+
+	data, err := json.Marshal(want)
+	require.NoError(t, err)
+	packed, err := jsonToMsgpack[T](data)
+	require.NoError(t, err)
+
+	var generic any
+	require.NoError(t, msgpack.Unmarshal(packed, &generic))
+	roundTripped, err := json.Marshal(generic)
+	require.NoError(t, err)
+	assert.JSONEq(t, string(data), string(roundTripped))
+
+	var got T
+	dec := msgpack.NewDecoder(bytes.NewReader(packed))
+	dec.SetCustomStructTag("json")
+	require.NoError(t, dec.Decode(&got))
+	assert.Equal(t, want, got)
+}
+
+func TestNegotiate(t *testing.T) {
+	body := []byte(`{"ForeignId":1}`)
+
+	// The Accept header alone doesn't change the response.
+	r := httptest.NewRequest(http.MethodGet, "/author/1", nil)
+	r.Header.Set("Accept", "application/json;q=0.5, application/msgpack")
+	w := httptest.NewRecorder()
+	assert.Equal(t, body, negotiate[AuthorResource](w, r, body))
+	assert.Empty(t, w.Header().Get("Content-Type"))
+
+	r = httptest.NewRequest(http.MethodGet, "/author/1?format=msgpack", nil)
+	w = httptest.NewRecorder()
+	var author map[string]any
+	require.NoError(t, msgpack.Unmarshal(negotiate[AuthorResource](w, r, body), &author))
+	assert.EqualValues(t, 1, author["ForeignId"])
+	assert.Equal(t, "application/msgpack", w.Header().Get("Content-Type"))
+
+	// Payloads which are too large are served as JSON.
+	previous := _msgpackMaxBytes
+	_msgpackMaxBytes = 4
+	t.Cleanup(func() { _msgpackMaxBytes = previous })
+	w = httptest.NewRecorder()
+	assert.Equal(t, body, negotiate[AuthorResource](w, r, body))
+	assert.Empty(t, w.Header().Get("Content-Type"))
+}
+
+func TestMsgpackCaching(t *testing.T) {
+	// MessagePack is only served from its own URL, so CDNs which ignore Vary:
+	// Accept can't hand it to JSON clients.
+	ctx := t.Context()
+	cache := newMemoryCache()
+	authorBytes, err := json.Marshal(AuthorResource{ForeignID: 1, Works: []workResource{}})
+	require.NoError(t, err)
+	cache.Set(ctx, AuthorKey(1), authorBytes, time.Hour)
+	workBytes, err := json.Marshal(workResource{ForeignID: 2, Books: []bookResource{}})
+	require.NoError(t, err)
+	cache.Set(ctx, WorkKey(2), workBytes, time.Hour)
+
+	ctrl, err := NewController(cache, NewMockgetter(gomock.NewController(t)), nil, nil)
+	require.NoError(t, err)
+	mux := NewMux(NewHandler(ctrl), prometheus.NewRegistry())
+
+	for _, path := range []string{"/author/1", "/work/2"} {
+		t.Run(path, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, path, nil)
+			r.Header.Set("Accept", "application/msgpack")
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, r)
+			assert.Equal(t, http.StatusSeeOther, w.Code)
+			assert.Equal(t, path+"?format=msgpack", w.Header().Get("Location"))
+			assert.Equal(t, "private", w.Header().Get("Cache-Control"))
+			assert.Contains(t, w.Header().Values("Vary"), "Accept")
+
+			w = httptest.NewRecorder()
+			mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path+"?format=msgpack", nil))
+			require.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, "application/msgpack", w.Header().Get("Content-Type"))
+			assert.Contains(t, w.Header().Get("Cache-Control"), "public, s-maxage=")
+			if nvs := w.Header().Get("No-Vary-Search"); nvs != "" {
+				assert.Contains(t, nvs, `"format"`) // CDNs must key on it.
+			}
+
+			var resource map[string]any
+			require.NoError(t, msgpack.Unmarshal(w.Body.Bytes(), &resource))
+
+			// Plain requests still get JSON.
+			w = httptest.NewRecorder()
+			mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+			require.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+			assert.True(t, json.Valid(w.Body.Bytes()))
+		})
+	}
+}
@@ -0,0 +1,152 @@
+package internal
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+)
+
+// _notifyMaxPending bounds how many distinct updates are held between
+// flushes. Anything beyond it is dropped -- clients still pick the change up
+// on their next poll.
+var _notifyMaxPending = 1000
+
+// _notifyFlushTimeout bounds how long a single flush can take, so an
+// unresponsive webhook can't hold up later notifications.
+var _notifyFlushTimeout = 30 * time.Second
+
+// notification is POSTed to the webhook when an author or work changes.
+type notification struct {
+	Type      string `json:"type"` // "author" or "work".
+	ForeignID int64  `json:"foreignId"`
+}
+
+// notifier collects updated authors and works and periodically POSTs them to
+// a webhook. Repeated updates to the same resource between flushes are only
+// sent once, so a large author load doesn't spam the webhook.
+type notifier struct {
+	mu      sync.Mutex
+	pending set[notification]
+
+	client *http.Client
+}
+
+func newNotifier() *notifier {
+	return &notifier{
+		pending: newSet[notification](),
+		client:  &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// add queues a notification, returning false if too many are already pending.
+func (n *notifier) add(kind string, id int64) bool {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	note := notification{Type: kind, ForeignID: id}
+	if _, ok := n.pending[note]; ok {
+		return true
+	}
+	if len(n.pending) >= _notifyMaxPending {
+		return false
+	}
+	n.pending[note] = struct{}{}
+	return true
+}
+
+// take removes and returns all pending notifications, sorted so they're
+// delivered deterministically.
+func (n *notifier) take() []notification {
+	n.mu.Lock()
+	pending := n.pending
+	n.pending = newSet[notification]()
+	n.mu.Unlock()
+
+	notes := make([]notification, 0, len(pending))
+	for note := range pending {
+		notes = append(notes, note)
+	}
+	slices.SortFunc(notes, func(a, b notification) int {
+		return cmp.Or(cmp.Compare(a.Type, b.Type), cmp.Compare(a.ForeignID, b.ForeignID))
+	})
+	return notes
+}
+
+// flush POSTs pending notifications to url, returning how many were delivered
+// and how many were dropped. We give up after the first failure, or once the
+// flush times out, rather than waiting on a webhook which is down. Clients
+// still pick up dropped updates on their next poll.
+func (n *notifier) flush(ctx context.Context, url string) (sent int, dropped int) {
+	ctx, cancel := context.WithTimeout(ctx, _notifyFlushTimeout)
+	defer cancel()
+
+	notes := n.take()
+	for _, note := range notes {
+		if err := n.post(ctx, url, note); err != nil {
+			Log(ctx).Warn("problem sending notifications", "err", err, "type", note.Type, "foreignId", note.ForeignID, "dropped", len(notes)-sent)
+			return sent, len(notes) - sent
+		}
+		sent++
+	}
+	return sent, 0
+}
+
+func (n *notifier) post(ctx context.Context, url string, note notification) error {
+	body, err := json.Marshal(note)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// notify queues a webhook notification for an updated author or work, if a
+// webhook is configured.
+func (c *Controller) notify(ctx context.Context, kind string, id int64) {
+	if c.options().notifyURL == "" {
+		return
+	}
+	if !c.notifier.add(kind, id) {
+		c.metrics.notificationsDroppedAdd(1)
+		Log(ctx).Debug("dropping notification", "type", kind, "foreignId", id)
+	}
+}
+
+// runNotifier flushes pending notifications after every debounce interval
+// until ctx is cancelled.
+func (c *Controller) runNotifier(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(c.options().notifyDebounce):
+		}
+		url := c.options().notifyURL
+		if url == "" {
+			_ = c.notifier.take() // Disabled by a reload.
+			continue
+		}
+		sent, dropped := c.notifier.flush(ctx, url)
+		c.metrics.notificationsSentAdd(sent)
+		c.metrics.notificationsDroppedAdd(dropped)
+	}
+}
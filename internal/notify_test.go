@@ -0,0 +1,99 @@
+package internal
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotify(t *testing.T) {
+	maxPending := _notifyMaxPending
+	_notifyMaxPending = 3
+	t.Cleanup(func() { _notifyMaxPending = maxPending })
+
+	var mu sync.Mutex
+	received := []notification{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		var note notification
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&note))
+		mu.Lock()
+		received = append(received, note)
+		mu.Unlock()
+	}))
+	t.Cleanup(srv.Close)
+
+	t.Run("disabled", func(t *testing.T) {
+		ctrl, err := NewController(newMemoryCache(), nil, nil, nil)
+		require.NoError(t, err)
+
+		ctrl.notify(t.Context(), "author", 1)
+		assert.Empty(t, ctrl.notifier.take())
+	})
+
+	t.Run("debounced and bounded", func(t *testing.T) {
+		ctrl, err := NewController(newMemoryCache(), nil, nil, nil, WithNotifyURL(srv.URL, 0))
+		require.NoError(t, err)
+
+		ctrl.notify(t.Context(), "work", 2)
+		ctrl.notify(t.Context(), "author", 1)
+		ctrl.notify(t.Context(), "work", 2) // Duplicate.
+		ctrl.notify(t.Context(), "work", 1)
+		ctrl.notify(t.Context(), "work", 3) // Dropped.
+
+		sent, dropped := ctrl.notifier.flush(t.Context(), srv.URL)
+		assert.Equal(t, 3, sent)
+		assert.Equal(t, 0, dropped)
+		assert.Equal(t, []notification{
+			{Type: "author", ForeignID: 1},
+			{Type: "work", ForeignID: 1},
+			{Type: "work", ForeignID: 2},
+		}, received)
+		assert.Equal(t, 1.0, ctrl.metrics.notificationsDroppedGet())
+
+		// Nothing is sent twice.
+		sent, _ = ctrl.notifier.flush(t.Context(), srv.URL)
+		assert.Equal(t, 0, sent)
+	})
+
+	t.Run("webhook down", func(t *testing.T) {
+		var calls atomic.Int32
+		down := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			calls.Add(1)
+			w.WriteHeader(http.StatusBadGateway)
+		}))
+		t.Cleanup(down.Close)
+
+		ctrl, err := NewController(newMemoryCache(), nil, nil, nil, WithNotifyURL(down.URL, 0))
+		require.NoError(t, err)
+
+		ctrl.notify(t.Context(), "author", 1)
+		ctrl.notify(t.Context(), "work", 1)
+		ctrl.notify(t.Context(), "work", 2)
+
+		// We give up after the first failure instead of trying every one.
+		sent, dropped := ctrl.notifier.flush(t.Context(), down.URL)
+		assert.Equal(t, 0, sent)
+		assert.Equal(t, 3, dropped)
+		assert.Equal(t, int32(1), calls.Load())
+		assert.Empty(t, ctrl.notifier.take())
+	})
+
+	t.Run("flushed in the background", func(t *testing.T) {
+		ctrl, err := NewController(newMemoryCache(), nil, nil, nil, WithNotifyURL(srv.URL, time.Millisecond))
+		require.NoError(t, err)
+		go ctrl.runNotifier(t.Context())
+
+		ctrl.notify(t.Context(), "author", 2)
+		assert.Eventually(t, func() bool {
+			return ctrl.metrics.notificationsSentGet() == 1
+		}, time.Second, time.Millisecond)
+	})
+}
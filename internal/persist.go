@@ -12,6 +12,11 @@ import (
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+// _refreshStateTTL is effectively forever. An author's pre-refresh state must
+// outlive the refresh however long it takes -- otherwise the next read would
+// kick off a second refresh -- so it's only removed by Delete.
+const _refreshStateTTL = 365 * 24 * time.Hour
+
 // persister records in-flight author refreshes so we can recover them on reboot.
 type persister interface {
 	Persist(ctx context.Context, authorID int64, current []byte) error
@@ -53,7 +58,7 @@ func NewPersister(ctx context.Context, cache cache[[]byte], dsn string) (*Persis
 
 // Persist records an author's refresh as in-flight.
 func (p *Persister) Persist(ctx context.Context, authorID int64, bytes []byte) error {
-	p.cache.Set(ctx, refreshAuthorKey(authorID), bytes, 365*24*time.Hour)
+	p.cache.Set(ctx, refreshAuthorKey(authorID), bytes, _refreshStateTTL)
 	return nil
 }
 
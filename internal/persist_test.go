@@ -2,9 +2,11 @@ package internal
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
 )
 
 func TestPersister(t *testing.T) {
@@ -37,3 +39,33 @@ func TestPersister(t *testing.T) {
 	assert.NoError(t, p.Delete(ctx, 3))
 	assert.NoError(t, p.Delete(ctx, 10))
 }
+
+func TestLongRefresh(t *testing.T) {
+	// An author whose refresh outlasts its cached TTL keeps serving its
+	// pre-refresh state instead of starting a second refresh. The mock getter
+	// fails on any unexpected call.
+	ctx := t.Context()
+	cache := newMemoryCache()
+	p := &Persister{cache: cache}
+
+	ctrl, err := NewController(cache, NewMockgetter(gomock.NewController(t)), p, nil)
+	require.NoError(t, err)
+
+	before := []byte(`{"ForeignId":1,"Name":"before"}`)
+	require.NoError(t, p.Persist(ctx, 1, before))
+
+	// Hours later the author itself has expired but the refresh is still
+	// running.
+	cache.Set(ctx, AuthorKey(1), []byte(`{"ForeignId":1,"Name":"partial"}`), time.Hour)
+	require.NoError(t, cache.Expire(ctx, AuthorKey(1)))
+
+	_, ttl, ok := cache.GetWithTTL(ctx, refreshAuthorKey(1))
+	require.True(t, ok)
+	assert.Greater(t, ttl, 24*time.Hour)
+
+	for range 3 {
+		got, _, err := ctrl.GetAuthor(ctx, 1)
+		require.NoError(t, err)
+		assert.Equal(t, before, got)
+	}
+}
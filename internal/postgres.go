@@ -8,9 +8,15 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"iter"
+	"strconv"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	_ "github.com/jackc/pgx/v5/stdlib" // pgx driver
 	"github.com/prometheus/client_golang/prometheus"
@@ -20,7 +26,10 @@ import (
 //go:embed schema.sql
 var _schema string
 
-var _ cache[[]byte] = (*pgcache)(nil)
+var (
+	_ cache[[]byte] = (*pgcache)(nil)
+	_ staleGetter   = (*pgcache)(nil)
+)
 
 // _buffers reduces GC.
 var _buffers = buffer.NewPool()
@@ -37,12 +46,84 @@ var _zipReaders = sync.Pool{New: func() any {
 	return &gzip.Reader{}
 }}
 
-func newPostgresCache(ctx context.Context, dsn string, reg *prometheus.Registry) (*pgcache, error) {
+var (
+	// _pgBufferSize is how many recent writes we hold in memory in case the DB
+	// becomes briefly unavailable. Writes which haven't been flushed yet are
+	// kept past this, up to _pgBufferLimit.
+	_pgBufferSize = 1000
+
+	// _pgBufferLimit is a hard cap on buffered writes. During a long outage
+	// the oldest unflushed writes are dropped past it rather than growing
+	// without bound.
+	_pgBufferLimit = 10 * _pgBufferSize
+
+	// _pgFlushEvery is how often we retry writes which failed to persist.
+	_pgFlushEvery = 5 * time.Second
+
+	// _pgCompactBatch is how many expired rows we delete at a time during
+	// compaction, to avoid holding locks for too long.
+	_pgCompactBatch = 1000
+)
+
+func newPostgresCache(ctx context.Context, dsn string, reg *prometheus.Registry, opts ...CacheOption) (*pgcache, error) {
+	o := cacheOptions{}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
 	db, err := newDB(ctx, dsn)
 	if err != nil {
 		return nil, fmt.Errorf("creating db: %w", err)
 	}
-	return &pgcache{db: db, metrics: newDBMetrics(db, reg)}, nil
+	pg := &pgcache{
+		db:      db,
+		metrics: newDBMetrics(ctx, db, reg),
+		recent:  newLRU[string, pgentry](_pgBufferSize),
+	}
+	// Unflushed writes would otherwise be lost.
+	pg.recent.keep = func(e pgentry) bool { return e.dirty }
+	pg.recent.limit = _pgBufferLimit
+	pg.recent.dropped = func(key string, _ pgentry) {
+		pg.metrics.bufferDroppedInc()
+		Log(ctx).Warn("dropping buffered write", "key", key)
+	}
+
+	// Retry deferred writes once the DB comes back.
+	go func() {
+		ticker := time.NewTicker(_pgFlushEvery)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			pg.flush(ctx)
+		}
+	}()
+
+	if o.compactEvery > 0 && !o.readOnly {
+		go func() {
+			ctx := context.WithValue(ctx, middleware.RequestIDKey, "compaction")
+			ticker := time.NewTicker(o.compactEvery)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+				}
+				n, err := pg.compact(ctx, o.compactGrace)
+				if err != nil {
+					Log(ctx).Warn("problem compacting cache", "err", err, "reaped", n)
+					continue
+				}
+				Log(ctx).Info("compacted cache", "reaped", n)
+			}
+		}()
+	}
+
+	return pg, nil
 }
 
 // newDB connects to our DB and applies our schema.
@@ -76,6 +157,22 @@ func newDB(ctx context.Context, dsn string) (*pgxpool.Pool, error) {
 type pgcache struct {
 	db      *pgxpool.Pool
 	metrics *dbMetrics
+
+	// recent is a write-through buffer of recently set values. If the DB is
+	// briefly unavailable we serve reads from here instead of treating
+	// everything as a cold miss, which would otherwise cause a thundering herd
+	// of upstream refetches. Writes that failed are marked dirty and flushed
+	// once the DB recovers.
+	recent *lru[string, pgentry]
+	seq    atomic.Uint64
+}
+
+// pgentry is a buffered cache value.
+type pgentry struct {
+	val     []byte // val is compressed.
+	expires time.Time
+	dirty   bool   // dirty is true if the value hasn't been persisted yet.
+	seq     uint64 // seq distinguishes concurrent writes to the same key.
 }
 
 func (pg *pgcache) Get(ctx context.Context, key string) ([]byte, bool) {
@@ -84,15 +181,53 @@ func (pg *pgcache) Get(ctx context.Context, key string) ([]byte, bool) {
 }
 
 func (pg *pgcache) GetWithTTL(ctx context.Context, key string) ([]byte, time.Duration, bool) {
+	val, expires, ok := pg.get(ctx, key)
+	if !ok {
+		return nil, 0, false
+	}
+	// Treat expired entries as a miss to force a refresh, but still return
+	// the cached data because it can help speed up the refresh.
+	return val, max(time.Until(expires), 0), true
+}
+
+// GetStale returns a value even if it's expired, along with how long ago it
+// expired. Unexpired values have an age of zero.
+func (pg *pgcache) GetStale(ctx context.Context, key string) ([]byte, time.Duration, bool) {
+	val, expires, ok := pg.get(ctx, key)
+	if !ok {
+		return nil, 0, false
+	}
+	return val, max(time.Since(expires), 0), true
+}
+
+// get returns the value for key along with when it expires.
+func (pg *pgcache) get(ctx context.Context, key string) ([]byte, time.Time, bool) {
 	cbuf := _buffers.Get()
 	defer cbuf.Free()
 
 	cb := cbuf.Bytes()
 
+	// Anything we haven't managed to persist yet is newer than what the DB
+	// has.
+	if e, ok := pg.recent.get(key); ok && e.dirty {
+		pg.metrics.bufferHitsInc()
+		return pg.fromBuffer(ctx, key, e)
+	}
+
 	var expires time.Time
 	err := pg.db.QueryRow(ctx, `SELECT value, expires FROM cache WHERE key = $1;`, key).Scan(&cb, &expires)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, time.Time{}, false
+	}
 	if err != nil {
-		return nil, 0, false
+		// The DB is having problems, fall back to our buffer if possible.
+		e, ok := pg.recent.get(key)
+		if !ok {
+			Log(ctx).Warn("problem getting cache", "err", err, "key", key)
+			return nil, time.Time{}, false
+		}
+		pg.metrics.bufferHitsInc()
+		return pg.fromBuffer(ctx, key, e)
 	}
 
 	// TODO: The client doesn't support gzip content-encoding, which is
@@ -103,21 +238,24 @@ func (pg *pgcache) GetWithTTL(ctx context.Context, key string) ([]byte, time.Dur
 	err = decompress(ctx, bytes.NewReader(cb), dbuf)
 	if err != nil {
 		Log(ctx).Warn("problem decompressing", "err", err, "key", key)
-		return nil, 0, false
+		return nil, time.Time{}, false
 	}
 
 	// We can't return the buffer's underlying byte slice, so make a copy.
 	// Still allocates but simpler than returning the raw buffer for now.
-	uncompressed := bytes.Clone(dbuf.Bytes())
+	return bytes.Clone(dbuf.Bytes()), expires, true
+}
 
-	// Treat expired entries as a miss to force a refresh, but still return
-	// the cached data because it can help speed up the refresh.
-	ttl := time.Until(expires)
-	if ttl <= 0 {
-		return uncompressed, 0, true
-	}
+// fromBuffer decompresses a buffered value.
+func (pg *pgcache) fromBuffer(ctx context.Context, key string, e pgentry) ([]byte, time.Time, bool) {
+	buf := _buffers.Get()
+	defer buf.Free()
 
-	return uncompressed, ttl, true
+	if err := decompress(ctx, bytes.NewReader(e.val), buf); err != nil {
+		Log(ctx).Warn("problem decompressing", "err", err, "key", key)
+		return nil, time.Time{}, false
+	}
+	return bytes.Clone(buf.Bytes()), e.expires, true
 }
 
 func (pg *pgcache) Set(ctx context.Context, key string, val []byte, ttl time.Duration) {
@@ -126,32 +264,135 @@ func (pg *pgcache) Set(ctx context.Context, key string, val []byte, ttl time.Dur
 	// the data.
 	ctx = context.WithoutCancel(ctx)
 
-	expires := time.Now().Add(ttl)
-
 	buf := _buffers.Get()
 	defer buf.Free()
 
-	err := compress(bytes.NewReader(val), buf)
+	if err := compress(bytes.NewReader(val), buf); err != nil {
+		Log(ctx).Error("problem compressing", "err", err, "key", key)
+		return
+	}
+
+	// Buffer the compressed bytes so the buffer's footprint matches the DB's.
+	e := pgentry{val: bytes.Clone(buf.Bytes()), expires: time.Now().Add(ttl), seq: pg.seq.Add(1)}
+
+	err := pg.write(ctx, key, e.val, e.expires)
 	if err != nil {
-		Log(ctx).Error("problem compressing value", "err", err, "key", key)
+		Log(ctx).Error("problem setting cache", "err", err, "key", key)
+		e.dirty = true
+		pg.metrics.bufferDeferredInc()
 	}
-	_, err = pg.db.Exec(ctx,
+	pg.recent.set(key, e)
+}
+
+// write persists an already compressed value.
+func (pg *pgcache) write(ctx context.Context, key string, compressed []byte, expires time.Time) error {
+	_, err := pg.db.Exec(ctx,
 		`INSERT INTO cache (key, value, expires) VALUES ($1, $2, $3) ON CONFLICT (key) DO UPDATE SET value = $4, expires = $5;`,
-		key, buf.Bytes(), expires, buf.Bytes(), expires,
+		key, compressed, expires, compressed, expires,
 	)
-	if err != nil {
-		Log(ctx).Error("problem setting cache", "err", err, "key", key)
+	return err
+}
+
+// flush attempts to persist any buffered writes which previously failed.
+func (pg *pgcache) flush(ctx context.Context) {
+	dirty := map[string]pgentry{}
+	pg.recent.each(func(key string, e pgentry) {
+		if e.dirty {
+			dirty[key] = e
+		}
+	})
+
+	for key, e := range dirty {
+		if err := pg.write(ctx, key, e.val, e.expires); err != nil {
+			Log(ctx).Warn("db still unavailable", "err", err, "pending", len(dirty))
+			return // Try again later.
+		}
+		// Mark it clean as long as it wasn't overwritten in the meantime.
+		pg.recent.update(key, func(current pgentry) (pgentry, bool) {
+			current.dirty = false
+			return current, current.seq == e.seq
+		})
+		pg.metrics.bufferFlushedInc()
+	}
+
+	if len(dirty) > 0 {
+		Log(ctx).Info("flushed buffered writes", "count", len(dirty))
+	}
+}
+
+// compact deletes rows which expired more than grace ago, in batches, and
+// returns how many were deleted. Rows explicitly expired with Expire are kept.
+func (pg *pgcache) compact(ctx context.Context, grace time.Duration) (int64, error) {
+	var reaped int64
+	cutoff := time.Now().Add(-grace)
+	for {
+		tag, err := pg.db.Exec(ctx,
+			`DELETE FROM cache WHERE key IN (SELECT key FROM cache WHERE expires < $1 AND expires > $2 LIMIT $3);`,
+			cutoff, time.UnixMicro(0), _pgCompactBatch,
+		)
+		if err != nil {
+			return reaped, err
+		}
+		n := tag.RowsAffected()
+		reaped += n
+		pg.metrics.reapedAdd(n)
+		if n < int64(_pgCompactBatch) {
+			return reaped, nil
+		}
+	}
+}
+
+// CachedIDs yields the ID of every resource of the given kind persisted in
+// Postgres, including expired ones.
+func CachedIDs(ctx context.Context, dsn string, kind KeyKind) iter.Seq2[int64, error] {
+	return func(yield func(int64, error) bool) {
+		db, err := newDB(ctx, dsn)
+		if err != nil {
+			yield(0, err)
+			return
+		}
+		defer db.Close()
+
+		rows, err := db.Query(ctx, `SELECT key FROM cache WHERE key LIKE $1;`, string(kind)+"%")
+		if err != nil {
+			yield(0, fmt.Errorf("listing keys: %w", err))
+			return
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var key string
+			if err := rows.Scan(&key); err != nil {
+				yield(0, fmt.Errorf("scanning key: %w", err))
+				return
+			}
+			id, err := strconv.ParseInt(strings.TrimPrefix(key, string(kind)), 10, 64)
+			if err != nil {
+				continue // Not an ID, e.g. an ASIN.
+			}
+			if !yield(id, nil) {
+				return
+			}
+		}
+		if err := rows.Err(); err != nil {
+			yield(0, fmt.Errorf("listing keys: %w", err))
+		}
 	}
 }
 
 // Expire expires a row by setting its ttl to 0. The data is still persisted.
 func (pg *pgcache) Expire(ctx context.Context, key string) error {
+	pg.recent.update(key, func(e pgentry) (pgentry, bool) {
+		e.expires = time.UnixMicro(0)
+		return e, true
+	})
 	_, err := pg.db.Exec(ctx, `UPDATE cache SET expires = $1 WHERE key = $2;`, time.UnixMicro(0), key)
 	return err
 }
 
 // Delete deletes a row.
 func (pg *pgcache) Delete(ctx context.Context, key string) error {
+	pg.recent.delete(key)
 	_, err := pg.db.Exec(ctx, `DELETE FROM cache WHERE key = $1;`, key)
 	return err
 }
@@ -124,6 +124,100 @@ func TestStaleData(t *testing.T) {
 	assert.True(t, ok)
 	assert.Equal(t, time.Duration(0), ttl)
 	assert.Len(t, bytes, 1)
+
+	// We can tell how long ago it expired.
+	bytes, age, ok := cache.GetStale(t.Context(), "KEY")
+	assert.True(t, ok)
+	assert.Greater(t, age, time.Duration(0))
+	assert.Len(t, bytes, 1)
+}
+
+func TestPostgresBuffer(t *testing.T) {
+	// Recent writes should still be readable if the DB goes away.
+
+	ctx := t.Context()
+
+	cache, err := newPostgresCache(ctx, "postgres://postgres@localhost:5432/test", nil)
+	require.NoError(t, err)
+
+	cache.Set(ctx, "buffered-clean", []byte{1}, time.Hour)
+
+	cache.db.Close()
+
+	cache.Set(ctx, "buffered-dirty", []byte{2}, time.Hour)
+
+	clean, ttl, ok := cache.GetWithTTL(ctx, "buffered-clean")
+	assert.True(t, ok)
+	assert.Equal(t, []byte{1}, clean)
+	assert.Greater(t, ttl, time.Minute)
+
+	dirty, ok := cache.Get(ctx, "buffered-dirty")
+	assert.True(t, ok)
+	assert.Equal(t, []byte{2}, dirty)
+
+	_, ok = cache.Get(ctx, "never-set")
+	assert.False(t, ok)
+}
+
+func TestPostgresCompaction(t *testing.T) {
+	// Long-expired rows are deleted, but recently expired and explicitly
+	// expired rows are kept.
+
+	ctx := t.Context()
+
+	cache, err := newPostgresCache(ctx, "postgres://postgres@localhost:5432/test", nil)
+	require.NoError(t, err)
+
+	cache.Set(ctx, "compact-old", []byte{1}, time.Hour)
+	cache.Set(ctx, "compact-recent", []byte{2}, time.Hour)
+	cache.Set(ctx, "compact-busted", []byte{3}, time.Hour)
+
+	_, err = cache.db.Exec(ctx, `UPDATE cache SET expires = $1 WHERE key = 'compact-old';`, time.Now().Add(-48*time.Hour))
+	require.NoError(t, err)
+	_, err = cache.db.Exec(ctx, `UPDATE cache SET expires = $1 WHERE key = 'compact-recent';`, time.Now().Add(-time.Minute))
+	require.NoError(t, err)
+	require.NoError(t, cache.Expire(ctx, "compact-busted"))
+
+	reaped, err := cache.compact(ctx, 24*time.Hour)
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, reaped, int64(1))
+
+	var keys []string
+	rows, err := cache.db.Query(ctx, `SELECT key FROM cache WHERE key LIKE 'compact-%' ORDER BY key;`)
+	require.NoError(t, err)
+	for rows.Next() {
+		var key string
+		require.NoError(t, rows.Scan(&key))
+		keys = append(keys, key)
+	}
+	require.NoError(t, rows.Err())
+	assert.Equal(t, []string{"compact-busted", "compact-recent"}, keys)
+}
+
+func TestPostgresCachedIDs(t *testing.T) {
+	ctx := t.Context()
+	dsn := "postgres://postgres@localhost:5432/test"
+
+	cache, err := newPostgresCache(ctx, dsn, nil)
+	require.NoError(t, err)
+
+	authorID := rand.Int64()
+	cache.Set(ctx, AuthorKey(authorID), []byte{1}, time.Hour)
+	cache.Set(ctx, WorkKey(authorID), []byte{1}, time.Hour)
+
+	ids := []int64{}
+	for id, err := range CachedIDs(ctx, dsn, AuthorKeys) {
+		require.NoError(t, err)
+		ids = append(ids, id)
+	}
+	assert.Contains(t, ids, authorID)
+
+	ids = []int64{}
+	for id, err := range CachedIDs(ctx, dsn, SeriesKeys) {
+		require.NoError(t, err)
+		ids = append(ids, id)
+	}
+	assert.NotContains(t, ids, authorID)
 }
 
 func BenchmarkCompressDecompress(b *testing.B) {
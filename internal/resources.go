@@ -1,5 +1,13 @@
 package internal
 
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
 // TODO: These could be generated from the OpenAPI spec.
 // https://github.com/Readarr/Readarr/blob/develop/src/Readarr.Api.V1/openapi.json
 
@@ -7,6 +15,16 @@ type bulkBookResource struct {
 	Works   []workResource   `json:"Works"`
 	Series  []SeriesResource `json:"Series"`
 	Authors []AuthorResource `json:"Authors"`
+
+	// Identifiers is only set when editions were requested by ISBN or ASIN.
+	Identifiers []bulkIdentifierResource `json:"Identifiers,omitempty"`
+}
+
+// bulkIdentifierResource is what became of an ISBN or ASIN in a bulk request.
+type bulkIdentifierResource struct {
+	Identifier       string `json:"Identifier"`
+	Status           int    `json:"Status"`                     // An HTTP status, e.g. 404 if it didn't resolve.
+	ForeignEditionID int64  `json:"ForeignEditionId,omitempty"` // Set once resolved, even if it couldn't be loaded.
 }
 
 type workResource struct {
@@ -25,12 +43,16 @@ type workResource struct {
 	Authors []AuthorResource `json:"Authors"`
 
 	// New fields
-	KCA        string `json:"KCA"`
-	BestBookID int64  `json:"BestBookId"`
+	KCA           string `json:"KCA"`
+	BestBookID    int64  `json:"BestBookId"`
+	OriginalTitle string `json:"OriginalTitle,omitempty"` // The title in the work's original language, if enabled.
 
 	RatingCount   int64   `json:"RatingCount"`
 	AverageRating float64 `json:"AverageRating"`
 	RatingSum     int64   `json:"RatingSum"`
+
+	// Source is the getter which produced the work. Only served for debugging.
+	Source string `json:"Source,omitempty"`
 }
 
 // AuthorResource collects every edition of every work by an author.
@@ -49,6 +71,42 @@ type AuthorResource struct {
 
 	// New fields.
 	KCA string `json:"KCA"`
+
+	// Continuing is true if the author released a work recently, or has one
+	// coming out.
+	Continuing bool `json:"Continuing"`
+
+	// Aliases are other names the author publishes under, like pseudonyms.
+	// Hardcover only.
+	Aliases []AuthorAlias `json:"Aliases,omitempty"`
+
+	// Genres are the most common genres across the author's works, most
+	// common first.
+	Genres []string `json:"Genres,omitempty"`
+
+	// Source is the getter which produced the author. Only served for
+	// debugging.
+	Source string `json:"Source,omitempty"`
+}
+
+// AuthorAlias is another name an author publishes under.
+type AuthorAlias struct {
+	ForeignID int64  `json:"ForeignId"`
+	Name      string `json:"Name"`
+}
+
+// mergeAliases adds aliases which aren't already present, keeping them sorted
+// by ID.
+func mergeAliases(aliases []AuthorAlias, more ...AuthorAlias) []AuthorAlias {
+	for _, a := range more {
+		idx, found := slices.BinarySearchFunc(aliases, a.ForeignID, func(a AuthorAlias, id int64) int {
+			return cmp.Compare(a.ForeignID, id)
+		})
+		if !found {
+			aliases = slices.Insert(aliases, idx, a)
+		}
+	}
+	return aliases
 }
 
 type bookResource struct {
@@ -61,7 +119,7 @@ type bookResource struct {
 	ShortTitle         string  `json:"ShortTitle"` // Just the title.
 	Language           string  `json:"Language"`
 	Format             string  `json:"Format"`
-	EditionInformation string  `json:"EditionInformation"`
+	EditionInformation string  `json:"EditionInformation"` // Notes like "Illustrated" which tell editions apart.
 	Publisher          string  `json:"Publisher"`
 	ImageURL           string  `json:"ImageUrl"`
 	IsEbook            bool    `json:"IsEbook"`
@@ -74,9 +132,15 @@ type bookResource struct {
 
 	Contributors []contributorResource `json:"Contributors"`
 
+	// contributorIDs holds every contributor credited on the edition, not
+	// just the primary one. It isn't serialized.
+	contributorIDs []int64
+
 	// New fields
-	KCA       string `json:"KCA"`
-	RatingSum int64  `json:"RatingSum"`
+	KCA                 string `json:"KCA"`
+	RatingSum           int64  `json:"RatingSum"`
+	PhysicalFormat      string `json:"PhysicalFormat,omitempty"`      // e.g. "Mass Market Paperback".
+	PhysicalInformation string `json:"PhysicalInformation,omitempty"` // Dimensions, binding, etc.
 }
 
 // SeriesResource is a collection of works by one or more authors.
@@ -108,6 +172,12 @@ type SearchResource struct {
 	BookID int64                `json:"bookId"`
 	WorkID int64                `json:"workId"`
 	Author SearchResourceAuthor `json:"author"`
+	ISBN13 string               `json:"isbn13,omitempty"` // Only set with --search-isbn.
+
+	// Signals for optionally re-ranking results. Not part of the response.
+	title       string
+	ratingCount int64
+	releaseDate string
 }
 
 // SearchResourceAuthor is a nested field on SearchResource.
@@ -115,6 +185,16 @@ type SearchResourceAuthor struct {
 	ID int64 `json:"id"`
 }
 
+// AuthorWorksResource is a page of an author's works. Works only include
+// their best edition, and not their authors.
+type AuthorWorksResource struct {
+	ForeignID int64          `json:"ForeignId"`
+	Page      int            `json:"Page"`
+	PageSize  int            `json:"PageSize"`
+	Total     int            `json:"Total"` // How many works the author has in total.
+	Works     []workResource `json:"Works"`
+}
+
 // RecommentationsResource contains recommended work IDs.
 type RecommentationsResource struct {
 	WorkIDs []int64 `json:"workIds"`
@@ -125,3 +205,263 @@ type RecommentationsResource struct {
 type lookupResource struct {
 	EditionID int64 `json:"editionId"`
 }
+
+// _descriptionPlaceholder is used in place of empty book and author
+// descriptions. The field is a plain string so R is fine with an empty value,
+// but "N/A" is kept as the default for backwards compatibility.
+var _descriptionPlaceholder = "N/A"
+
+// SetDescriptionPlaceholder sets the text used for empty descriptions. It
+// should only be called during startup.
+func SetDescriptionPlaceholder(s string) {
+	_descriptionPlaceholder = s
+}
+
+// description returns the trimmed description, or the configured placeholder
+// if it's empty.
+func description(s string) string {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return _descriptionPlaceholder
+	}
+	return s
+}
+
+// _genrePlaceholder is used in place of an empty genre list. An empty
+// placeholder yields an empty (not null) list, which R handles fine; "none" is
+// kept as the default for backwards compatibility.
+var _genrePlaceholder = "none"
+
+// SetGenrePlaceholder sets the genre used when a book has none. It should only
+// be called during startup.
+func SetGenrePlaceholder(s string) {
+	_genrePlaceholder = s
+}
+
+// withGenrePlaceholder returns the given genres, or the configured placeholder
+// if there are none. The result is never nil.
+func withGenrePlaceholder(g []string) []string {
+	if len(g) > 0 {
+		return g
+	}
+	if _genrePlaceholder == "" {
+		return []string{}
+	}
+	return []string{_genrePlaceholder}
+}
+
+// _editionInformation controls whether editions include notes like
+// "Illustrated" or "Revised Edition".
+var _editionInformation = true
+
+// SetEditionInformation sets whether editions include notes like
+// "Illustrated". It should only be called during startup.
+func SetEditionInformation(enabled bool) {
+	_editionInformation = enabled
+}
+
+// withEditionInformation returns the given edition notes, or nothing if
+// they're disabled.
+func withEditionInformation(s string) string {
+	if !_editionInformation {
+		return ""
+	}
+	return strings.TrimSpace(s)
+}
+
+// _fullSizeImages controls whether size directives are stripped from image
+// URLs so clients get full-resolution covers.
+var _fullSizeImages = true
+
+// SetFullSizeImages sets whether image URLs point at the original image
+// rather than a thumbnail. It should only be called during startup.
+func SetFullSizeImages(enabled bool) {
+	_fullSizeImages = enabled
+}
+
+// _originalTitles controls whether works include their original-language
+// title.
+var _originalTitles = false
+
+// SetOriginalTitles sets whether works include their original-language title
+// alongside the displayed one. It should only be called during startup.
+func SetOriginalTitles(enabled bool) {
+	_originalTitles = enabled
+}
+
+// withOriginalTitle returns the given original title, or nothing if they're
+// disabled.
+func withOriginalTitle(s string) string {
+	if !_originalTitles {
+		return ""
+	}
+	return strings.TrimSpace(s)
+}
+
+// _unratedRating is the average rating reported for books and authors without
+// any ratings. Clients show 0 as "0 stars", so a negative value can be used to
+// tell "unrated" apart from "rated 0". Zero is kept as the default for
+// backwards compatibility.
+var _unratedRating float64
+
+// SetUnratedRating sets the average rating reported when there are no
+// ratings. It should only be called during startup.
+func SetUnratedRating(r float64) {
+	_unratedRating = r
+}
+
+// averageRating returns avg, or the configured unrated value if there are no
+// ratings to average.
+func averageRating(count int64, avg float64) float64 {
+	if count == 0 {
+		return _unratedRating
+	}
+	return avg
+}
+
+// EditionOrder is how a work's editions are ordered. By default they're sorted
+// by ID, which looks random to users.
+type EditionOrder string
+
+// Supported edition orders.
+const (
+	// EditionsByID keeps editions sorted by ID.
+	EditionsByID EditionOrder = "id"
+	// EditionsByRatings lists the best edition first, then editions with more
+	// ratings.
+	EditionsByRatings EditionOrder = "ratings"
+	// EditionsByRecency lists the best edition first, then more recently
+	// released editions.
+	EditionsByRecency EditionOrder = "recency"
+)
+
+// _editionOrder is how a work's editions are ordered when they're saved.
+var _editionOrder = EditionsByID
+
+// SetEditionOrder sets how a work's editions are ordered when they're saved. It
+// should only be called during startup.
+func SetEditionOrder(o EditionOrder) error {
+	switch o {
+	case EditionsByID, EditionsByRatings, EditionsByRecency:
+		_editionOrder = o
+		return nil
+	}
+	return fmt.Errorf("unknown edition order %q: expected id, ratings or recency", o)
+}
+
+// orderEditions sorts a work's editions, which are otherwise sorted by ID,
+// for presentation according to the configured order. Editions in the primary
+// language come first. Ties keep their ID order.
+//
+// The order and language are the same for every request, so this happens
+// once when the work is saved instead of whenever it's served.
+func orderEditions(work *workResource) {
+	var by func(a, b bookResource) int
+	switch _editionOrder {
+	case EditionsByRatings:
+		by = func(a, b bookResource) int { return cmp.Compare(b.RatingCount, a.RatingCount) }
+	case EditionsByRecency:
+		by = func(a, b bookResource) int { return cmp.Compare(b.ReleaseDate, a.ReleaseDate) }
+	}
+	if by != nil {
+		slices.SortStableFunc(work.Books, func(a, b bookResource) int {
+			return cmp.Or(
+				compareBool(a.ForeignID == work.BestBookID, b.ForeignID == work.BestBookID),
+				by(a, b),
+			)
+		})
+	}
+	if _primaryLanguage != "" {
+		preferLanguage(work.Books, _primaryLanguage)
+	}
+}
+
+// _maxFutureYears bounds how far in the future a release date can be before
+// it's considered a typo and omitted. Zero disables the bound.
+var _maxFutureYears = 0
+
+// SetMaxFutureYears sets how many years in the future a release date may be.
+// It should only be called during startup.
+func SetMaxFutureYears(n int) {
+	_maxFutureYears = n
+}
+
+// _minEditionPages is how many pages an edition needs to be preferred as a
+// work's best edition. Zero disables the check.
+var _minEditionPages int64
+
+// SetMinEditionPages sets how many pages an edition needs to be preferred as
+// a work's best edition. It should only be called during startup.
+func SetMinEditionPages(n int64) {
+	_minEditionPages = n
+}
+
+// stubEdition returns true if the edition looks like a placeholder record.
+// Audiobooks don't have pages so they're never considered stubs.
+func stubEdition(pages, audioSeconds int64) bool {
+	return pages < _minEditionPages && audioSeconds == 0
+}
+
+// tooFarInFuture returns true if the release date exceeds the configured
+// bound.
+func tooFarInFuture(t time.Time) bool {
+	if _maxFutureYears <= 0 {
+		return false
+	}
+	return t.After(time.Now().AddDate(_maxFutureYears, 0, 0))
+}
+
+// repairNulls replaces nil slices the client can't handle with empty ones. It
+// returns the fields which needed repairing, if any.
+func (w *workResource) repairNulls() []string {
+	repaired := []string{}
+	if w.Books == nil {
+		w.Books = []bookResource{}
+		repaired = append(repaired, "Books")
+	}
+	if w.Genres == nil {
+		w.Genres = []string{}
+		repaired = append(repaired, "Genres")
+	}
+	if w.RelatedWorks == nil {
+		w.RelatedWorks = []int{}
+		repaired = append(repaired, "RelatedWorks")
+	}
+	if w.Series == nil {
+		w.Series = []SeriesResource{}
+		repaired = append(repaired, "Series")
+	}
+	if w.Authors == nil {
+		w.Authors = []AuthorResource{}
+		repaired = append(repaired, "Authors")
+	}
+	for i := range w.Books {
+		if w.Books[i].Contributors == nil {
+			w.Books[i].Contributors = []contributorResource{}
+			repaired = append(repaired, "Books.Contributors")
+		}
+	}
+	return slices.Compact(repaired)
+}
+
+// repairNulls replaces nil slices the client can't handle with empty ones,
+// including on the author's works. It returns the fields which needed
+// repairing, if any.
+func (a *AuthorResource) repairNulls() []string {
+	repaired := []string{}
+	if a.Works == nil {
+		a.Works = []workResource{}
+		repaired = append(repaired, "Works")
+	}
+	if a.Series == nil {
+		a.Series = []SeriesResource{}
+		repaired = append(repaired, "Series")
+	}
+	for i := range a.Works {
+		for _, field := range a.Works[i].repairNulls() {
+			repaired = append(repaired, "Works."+field)
+		}
+	}
+	slices.Sort(repaired)
+	return slices.Compact(repaired)
+}
@@ -0,0 +1,31 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestStartSpan(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tracer := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)).Tracer("test")
+
+	ctx := context.WithValue(t.Context(), middleware.RequestIDKey, "req-1")
+	_, span := startSpan(ctx, tracer, "GetWork", attribute.Int64("work.id", 1))
+	endSpan(span, errors.New("boom"))
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "GetWork", spans[0].Name())
+	assert.Contains(t, spans[0].Attributes(), attribute.String("request.id", "req-1"))
+	assert.Contains(t, spans[0].Attributes(), attribute.Int64("work.id", 1))
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+}
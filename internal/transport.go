@@ -1,6 +1,7 @@
 package internal
 
 import (
+	"errors"
 	"net/http"
 	"time"
 )
@@ -58,14 +59,18 @@ type errorProxyTransport struct {
 }
 
 // RoundTrip wraps upstream 4XX and 5XX errors such that they are returned
-// directly to the client.
+// directly to the client. Any Retry-After is included as a retryAfterErr.
 func (t errorProxyTransport) RoundTrip(r *http.Request) (*http.Response, error) {
 	resp, err := t.RoundTripper.RoundTrip(r)
 	if err != nil {
 		return nil, err
 	}
 	if resp.StatusCode >= 400 {
-		return nil, statusErr(resp.StatusCode)
+		err := error(statusErr(resp.StatusCode))
+		if after := parseRetryAfter(resp.Header.Get("Retry-After")); after > 0 {
+			err = errors.Join(err, retryAfterErr(after))
+		}
+		return nil, err
 	}
 	return resp, nil
 }
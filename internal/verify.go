@@ -0,0 +1,94 @@
+package internal
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// ratingsCheck mirrors the rating fields of a serialized author with
+// pointers, so nulls can be told apart from zeroes.
+type ratingsCheck struct {
+	RatingCount   *int64   `json:"RatingCount"`
+	AverageRating *float64 `json:"AverageRating"`
+	Works         []struct {
+		ForeignID     int64    `json:"ForeignId"`
+		RatingCount   *int64   `json:"RatingCount"`
+		AverageRating *float64 `json:"AverageRating"`
+		RatingSum     *int64   `json:"RatingSum"`
+		Books         []struct {
+			ForeignID     int64    `json:"ForeignId"`
+			RatingCount   *int64   `json:"RatingCount"`
+			AverageRating *float64 `json:"AverageRating"`
+			RatingSum     *int64   `json:"RatingSum"`
+		} `json:"Books"`
+	} `json:"Works"`
+}
+
+// VerifyAuthor checks a serialized author against the invariants clients rely
+// on and returns a description of every violation. The input isn't modified.
+// An error is returned only if the author can't be decoded at all.
+func VerifyAuthor(authorBytes []byte) ([]string, error) {
+	var author AuthorResource
+	if err := json.Unmarshal(authorBytes, &author); err != nil {
+		return nil, fmt.Errorf("decoding author: %w", err)
+	}
+	var ratings ratingsCheck
+	if err := json.Unmarshal(authorBytes, &ratings); err != nil {
+		return nil, fmt.Errorf("decoding ratings: %w", err)
+	}
+
+	violations := []string{}
+	report := func(format string, args ...any) {
+		violations = append(violations, fmt.Sprintf(format, args...))
+	}
+
+	// The same repair the serialization guard applies, but only reported.
+	for _, field := range author.repairNulls() {
+		report("null %s", field)
+	}
+
+	if ratings.RatingCount == nil || ratings.AverageRating == nil {
+		report("null author ratings")
+	}
+	for _, w := range ratings.Works {
+		if w.RatingCount == nil || w.AverageRating == nil || w.RatingSum == nil {
+			report("null ratings on work %d", w.ForeignID)
+		}
+		for _, b := range w.Books {
+			if b.RatingCount == nil || b.AverageRating == nil || b.RatingSum == nil {
+				report("null ratings on edition %d of work %d", b.ForeignID, w.ForeignID)
+			}
+		}
+	}
+
+	for i, w := range author.Works {
+		// Works are kept sorted by ID so they can be binary searched, which
+		// also means IDs must be unique.
+		if i > 0 {
+			switch prev := author.Works[i-1].ForeignID; {
+			case prev == w.ForeignID:
+				report("duplicate work %d", w.ForeignID)
+			case prev > w.ForeignID:
+				report("work %d is out of order after %d", w.ForeignID, prev)
+			}
+		}
+		for _, b := range w.Books {
+			if !hasContributor(b) {
+				report("edition %d of work %d has no contributor with a foreign ID", b.ForeignID, w.ForeignID)
+			}
+		}
+	}
+
+	return violations, nil
+}
+
+// hasContributor returns true if the edition credits at least one contributor
+// with a foreign ID.
+func hasContributor(b bookResource) bool {
+	for _, c := range b.Contributors {
+		if c.ForeignID != 0 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,68 @@
+package internal
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyAuthor(t *testing.T) {
+	edition := func(id int64) bookResource {
+		return bookResource{ForeignID: id, Contributors: []contributorResource{{ForeignID: 1, Role: "Author"}}}
+	}
+	work := func(id int64, books ...bookResource) workResource {
+		w := workResource{ForeignID: id, Books: books}
+		w.repairNulls()
+		return w
+	}
+	author := func(works ...workResource) []byte {
+		out, err := json.Marshal(AuthorResource{ForeignID: 1, Works: works, Series: []SeriesResource{}})
+		require.NoError(t, err)
+		return out
+	}
+
+	tests := []struct {
+		name  string
+		given []byte
+		want  []string
+	}{
+		{
+			name:  "valid",
+			given: author(work(1, edition(10)), work(2, edition(20), edition(21))),
+			want:  []string{},
+		},
+		{
+			name:  "null books",
+			given: []byte(strings.Replace(string(author(work(1))), `"Books":[]`, `"Books":null`, 1)),
+			want:  []string{"null Works.Books"},
+		},
+		{
+			name:  "null ratings",
+			given: []byte(strings.Replace(string(author(work(1, edition(10)))), `"AverageRating":0,"Url"`, `"AverageRating":null,"Url"`, 1)),
+			want:  []string{"null ratings on edition 10 of work 1"},
+		},
+		{
+			name:  "no contributor",
+			given: author(work(1, bookResource{ForeignID: 10, Contributors: []contributorResource{{Role: "Author"}}})),
+			want:  []string{"edition 10 of work 1 has no contributor with a foreign ID"},
+		},
+		{
+			name:  "unsorted and duplicated works",
+			given: author(work(2), work(1), work(1)),
+			want:  []string{"work 1 is out of order after 2", "duplicate work 1"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := VerifyAuthor(tt.given)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	_, err := VerifyAuthor([]byte("{"))
+	assert.Error(t, err)
+}